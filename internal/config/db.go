@@ -26,16 +26,32 @@ func ConnectDB() {
 		user, pass, host, port, name)
 
 	var err error
-	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
+		// surface unique key violations as gorm.ErrDuplicatedKey (see services.translateDBError)
+		TranslateError: true,
+	})
 	if err != nil {
 		log.Fatalf("❌ Failed to connect to database: %v", err)
 	}
 
 	log.Println("Connected to MySQL Database!")
 
+	// move free text departments to their own table before the foreign key is added
+	if err = migrateDepartments(DB); err != nil {
+		log.Fatalf("❌ Failed to migrate departments: %v", err)
+	}
+
+	// merge duplicate attendance before the unique (student, day, section) index is added
+	if err = dedupeAttendance(DB); err != nil {
+		log.Fatalf("❌ Failed to merge duplicate attendance: %v", err)
+	}
+
 	// auto create tables if dne
-	if err = DB.AutoMigrate(&models.Student{}, &models.Attendance{}); err != nil {
+	if err = DB.AutoMigrate(&models.Department{}, &models.Student{}, &models.Course{}, &models.Section{}, &models.Enrollment{}, &models.TimetableSlot{}, &models.ClassSession{}, &models.Term{}, &models.Closure{}, &models.Attendance{}, &models.AttendanceCorrection{}, &models.LeaveRequest{}, &models.AbsenceAlert{}, &models.WebhookSubscription{}, &models.WebhookDelivery{}, &models.Report{}, &models.ReportRow{}, &models.User{}, &models.AuditLog{}, &models.OutboxEvent{}); err != nil {
 		log.Fatalf("❌ Failed to migrate database: %v", err)
 	}
+	if err = dropLegacyAttendanceIndex(DB); err != nil {
+		log.Fatalf("❌ Failed to migrate attendance: %v", err)
+	}
 	log.Println("Database Migrated Successfully!")
 }
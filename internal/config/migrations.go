@@ -0,0 +1,166 @@
+package config
+
+import (
+	"hrms_backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// unassignedDepartment receives the students that had no department before the migration
+const unassignedDepartment = "Unassigned"
+
+// migrateDepartments moves students from the old free text `department` column to the departments
+// table. It runs before AutoMigrate adds the department_id foreign key, because existing rows need a
+// valid department first, and does nothing once the old column is gone.
+// MySQL commits DDL implicitly, so instead of a transaction every step is safe to run again
+// if the migration is interrupted.
+//
+// Spellings that only differ in case or surrounding spaces ("CS", "cs ") become one department.
+// Other variants ("Computer Science") become departments of their own, to be combined with
+// POST /departments/:id/merge.
+func migrateDepartments(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable("students") || !m.HasColumn("students", "department") {
+		return nil
+	}
+
+	if err := m.AutoMigrate(&models.Department{}); err != nil {
+		return err
+	}
+	if !m.HasColumn("students", "department_id") {
+		if err := db.Exec("ALTER TABLE students ADD department_id bigint unsigned NULL").Error; err != nil {
+			return err
+		}
+	}
+
+	if err := db.Exec("UPDATE students SET department = ? WHERE TRIM(COALESCE(department, '')) = ''", unassignedDepartment).Error; err != nil {
+		return err
+	}
+	// DISTINCT compares with the column collation, which ignores case
+	var names []string
+	if err := db.Raw("SELECT DISTINCT TRIM(department) FROM students WHERE department_id IS NULL").Scan(&names).Error; err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := db.Where("name = ?", name).FirstOrCreate(&models.Department{Name: name}).Error; err != nil {
+			return err
+		}
+	}
+	err := db.Exec(`UPDATE students JOIN departments ON departments.name = TRIM(students.department)
+		SET students.department_id = departments.id WHERE students.department_id IS NULL`).Error
+	if err != nil {
+		return err
+	}
+
+	return m.DropColumn("students", "department")
+}
+
+// legacyAttendanceIndex was unique on (student_id, date), allowing a single record per day.
+// AutoMigrate adds idx_attendance_student_date_section but never drops indexes, so this does.
+// It runs after AutoMigrate: MySQL refuses to drop the old index until the new one, which also
+// starts with student_id, can back the student_id foreign key.
+const legacyAttendanceIndex = "idx_attendance_student_date"
+
+func dropLegacyAttendanceIndex(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasIndex(&models.Attendance{}, legacyAttendanceIndex) {
+		return nil
+	}
+	return m.DropIndex(&models.Attendance{}, legacyAttendanceIndex)
+}
+
+// attendanceRow is what dedupeAttendance needs to know about a record
+type attendanceRow struct {
+	ID        uint
+	StudentID uint
+	Day       time.Time // DATE(date)
+	Section   uint      // section_id, 0 for whole day records
+	Deleted   bool
+}
+
+// dedupeAttendance merges the records idx_attendance_student_date_section would refuse, so
+// AutoMigrate can turn date into a calendar day and add the index. Before the index, double
+// clicks stored a record twice, and records of the same day at different times become
+// duplicates once the time is dropped. Each group keeps its newest record (see mergeTargets);
+// the corrections of the others move onto it before they are deleted.
+// It runs before AutoMigrate and does nothing once there are no duplicates.
+func dedupeAttendance(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable("attendances") {
+		return nil
+	}
+	section, sectionA := "0", "0"
+	if m.HasColumn("attendances", "section_id") {
+		section, sectionA = "COALESCE(section_id, 0)", "COALESCE(a.section_id, 0)"
+	}
+
+	var rows []attendanceRow
+	err := db.Raw(`SELECT a.id, a.student_id, DATE(a.date) AS day, ` + sectionA + ` AS section, a.deleted_at IS NOT NULL AS deleted
+		FROM attendances a JOIN (
+			SELECT student_id, DATE(date) AS day, ` + section + ` AS section FROM attendances
+			WHERE student_id IS NOT NULL GROUP BY student_id, DATE(date), ` + section + ` HAVING COUNT(*) > 1
+		) d ON d.student_id = a.student_id AND d.day = DATE(a.date) AND d.section = ` + sectionA).Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+	targets := mergeTargets(rows)
+	if len(targets) == 0 {
+		return nil
+	}
+
+	merged := make(map[uint][]uint) // kept -> merged into it
+	for from, to := range targets {
+		merged[to] = append(merged[to], from)
+	}
+	hasCorrections := m.HasTable("attendance_corrections")
+	return db.Transaction(func(tx *gorm.DB) error {
+		for to, from := range merged {
+			if hasCorrections {
+				if err := tx.Exec("UPDATE attendance_corrections SET attendance_id = ? WHERE attendance_id IN ?", to, from).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Exec("DELETE FROM attendances WHERE id IN ?", from).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// mergeTargets groups the rows by student, day and section and maps every row of a group to the
+// one kept: the newest (highest ID) row not soft deleted, or the newest row if all of them are.
+// Rows alone in their group are left out.
+func mergeTargets(rows []attendanceRow) map[uint]uint {
+	type key struct {
+		studentID uint
+		day       time.Time
+		section   uint
+	}
+	groups := make(map[key][]attendanceRow)
+	for _, row := range rows {
+		k := key{row.StudentID, row.Day, row.Section}
+		groups[k] = append(groups[k], row)
+	}
+
+	targets := make(map[uint]uint)
+	for _, group := range groups {
+		keep := group[0]
+		for _, row := range group[1:] {
+			if keep.Deleted != row.Deleted {
+				if keep.Deleted {
+					keep = row
+				}
+			} else if row.ID > keep.ID {
+				keep = row
+			}
+		}
+		for _, row := range group {
+			if row.ID != keep.ID {
+				targets[row.ID] = keep.ID
+			}
+		}
+	}
+	return targets
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeTargets(t *testing.T) {
+	day := time.Date(2025, 12, 12, 0, 0, 0, 0, time.UTC)
+	rows := []attendanceRow{
+		// a double click, and a third mark later that day: the newest one is kept
+		{ID: 1, StudentID: 7, Day: day},
+		{ID: 2, StudentID: 7, Day: day},
+		{ID: 5, StudentID: 7, Day: day},
+		// the same student in a class that day is another record
+		{ID: 3, StudentID: 7, Day: day, Section: 4},
+		// a live record wins over a newer deleted one
+		{ID: 6, StudentID: 8, Day: day},
+		{ID: 9, StudentID: 8, Day: day, Deleted: true},
+		// all deleted: the newest is kept
+		{ID: 10, StudentID: 9, Day: day, Deleted: true},
+		{ID: 11, StudentID: 9, Day: day, Deleted: true},
+	}
+
+	assert.Equal(t, map[uint]uint{1: 5, 2: 5, 9: 6, 10: 11}, mergeTargets(rows))
+	assert.Empty(t, mergeTargets(rows[3:4]))
+}
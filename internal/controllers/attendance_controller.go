@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/models"
 	"hrms_backend/internal/services"
 	"hrms_backend/internal/viewmodels"
 	"net/http"
@@ -17,57 +19,298 @@ func NewAttendanceController(service services.AttendanceService) *AttendanceCont
 	return &AttendanceController{service: service}
 }
 
+// The group must be authenticated; students may only read their own records.
 func (ctl *AttendanceController) RegisterRoutes(rg *gin.RouterGroup) {
-	rg.POST("/mark", ctl.MarkAttendance)
-	rg.GET("/:student_id", ctl.GetAttendanceByStudentID)
+	staff := middleware.RequireRoles(models.RoleAdmin, models.RoleTeacher)
+
+	rg.GET("", staff, ctl.ListAttendance)
+	rg.POST("/mark", staff, ctl.MarkAttendance)
+	rg.POST("/bulk", staff, ctl.MarkBulkAttendance)
+	rg.PUT("/records/:id", staff, ctl.UpdateAttendance)
+	rg.POST("/records/:id/check-out", staff, ctl.CheckOut)
+	rg.DELETE("/records/:id", staff, ctl.DeleteAttendance)
+	rg.GET("/records/:id/history", staff, ctl.GetAttendanceHistory)
+	rg.GET("/:student_id", middleware.RequireRolesOrOwnStudent("student_id", models.RoleAdmin, models.RoleTeacher), ctl.GetAttendanceByStudentID)
 }
 
 // MarkAttendance handles POST /attendance/mark
 // @Summary      Mark student attendance
-// @Description  Marks a student's attendance as 'Present' or 'Absent' for a given date.
+// @Description  Marks a student's attendance for a given date. A student has at most one record per day, or per day and
+// @Description  section when section_id is set (the student must be enrolled in the section):
+// @Description  replaying the same status returns the stored record with 200, a different status returns 409.
+// @Description  An optional Idempotency-Key header makes retries resolve to the record created by the first call.
+// @Description  Weekends, holidays, closure days and days outside every term are rejected with 400 non_school_day.
+// @Description  With section_id and check_in_at, status may be left out: it is present or late depending on the
+// @Description  class session's start time and late_after_minutes, and late records keep their late_minutes.
 // @Tags         Attendance
 // @Accept       json
 // @Produce      json
-// @Param        attendance  body      viewmodels.CreateAttendanceRequest  true  "Attendance details"
-// @Success      201         "Created"
-// @Failure      400         {object}  viewmodels.ErrorResponse
+// @Param        Idempotency-Key  header    string                              false  "Client generated key for safe retries"
+// @Param        attendance       body      viewmodels.CreateAttendanceRequest  true   "Attendance details"
+// @Success      200              {object}  viewmodels.AttendanceResponse  "Already marked"
+// @Success      201              {object}  viewmodels.AttendanceResponse  "Created"
+// @Failure      400              {object}  viewmodels.ErrorResponse
+// @Failure      404              {object}  viewmodels.ErrorResponse
+// @Failure      409              {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
 // @Router       /attendance/mark [post]
 func (ctl *AttendanceController) MarkAttendance(c *gin.Context) {
 	var req viewmodels.CreateAttendanceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(c, err)
+		return
+	}
+
+	resp, created, err := ctl.service.MarkAttendance(req, c.GetHeader("Idempotency-Key"), middleware.CurrentActor(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if !created {
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// MarkBulkAttendance handles POST /attendance/bulk
+// @Summary      Mark attendance for many students
+// @Description  Marks attendance for a list of students, or for a whole department ("everyone present except these"), on one date.
+// @Description  Valid rows are inserted in a single transaction; each row reports its own success or failure.
+// @Description  A date that is not a school day fails the whole request with 400 non_school_day.
+// @Tags         Attendance
+// @Accept       json
+// @Produce      json
+// @Param        attendance  body      viewmodels.BulkAttendanceRequest  true  "Date and rows to mark"
+// @Success      200         {object}  viewmodels.BulkAttendanceResponse
+// @Failure      400         {object}  viewmodels.ErrorResponse
+// @Failure      500         {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendance/bulk [post]
+func (ctl *AttendanceController) MarkBulkAttendance(c *gin.Context) {
+	var req viewmodels.BulkAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	resp, err := ctl.service.MarkBulkAttendance(req, middleware.CurrentActor(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateAttendance handles PUT /attendance/records/:id
+// @Summary      Correct an attendance record
+// @Description  Changes the status of an attendance record. A reason is required and the previous status is kept in the record's history.
+// @Tags         Attendance
+// @Accept       json
+// @Produce      json
+// @Param        id          path      int                                 true  "Attendance record ID"
+// @Param        correction  body      viewmodels.UpdateAttendanceRequest  true  "New status and reason"
+// @Success      200         {object}  viewmodels.AttendanceResponse
+// @Failure      400         {object}  viewmodels.ErrorResponse
+// @Failure      404         {object}  viewmodels.ErrorResponse
+// @Failure      500         {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendance/records/{id} [put]
+func (ctl *AttendanceController) UpdateAttendance(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	var req viewmodels.UpdateAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	resp, err := ctl.service.UpdateAttendance(uint(id), req, middleware.CurrentActor(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// CheckOut handles POST /attendance/records/:id/check-out
+// @Summary      Record a check-out
+// @Description  Sets when the student left. It must be on the record's day and after the check-in, if any.
+// @Tags         Attendance
+// @Accept       json
+// @Produce      json
+// @Param        id         path      int                         true  "Attendance record ID"
+// @Param        check_out  body      viewmodels.CheckOutRequest  true  "Check-out time"
+// @Success      200        {object}  viewmodels.AttendanceResponse
+// @Failure      400        {object}  viewmodels.ErrorResponse
+// @Failure      404        {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendance/records/{id}/check-out [post]
+func (ctl *AttendanceController) CheckOut(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	var req viewmodels.CheckOutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	resp, err := ctl.service.CheckOut(uint(id), req, middleware.CurrentActor(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteAttendance handles DELETE /attendance/records/:id
+// @Summary      Delete an attendance record
+// @Description  Deletes an attendance record. A reason is required and the deleted status is kept in the record's history.
+// @Tags         Attendance
+// @Accept       json
+// @Produce      json
+// @Param        id      path  int                                 true  "Attendance record ID"
+// @Param        reason  body  viewmodels.DeleteAttendanceRequest  true  "Reason for the deletion"
+// @Success      204     "No Content"
+// @Failure      400     {object}  viewmodels.ErrorResponse
+// @Failure      404     {object}  viewmodels.ErrorResponse
+// @Failure      500     {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendance/records/{id} [delete]
+func (ctl *AttendanceController) DeleteAttendance(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	var req viewmodels.DeleteAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
 		return
 	}
 
-	if err := ctl.service.MarkAttendance(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := ctl.service.DeleteAttendance(uint(id), req, middleware.CurrentActor(c)); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.Status(http.StatusNoContent)
+}
+
+// GetAttendanceHistory handles GET /attendance/records/:id/history
+// @Summary      Get the correction history of an attendance record
+// @Description  Lists every update and deletion of an attendance record with the previous status and reason, oldest first.
+// @Tags         Attendance
+// @Produce      json
+// @Param        id   path      int  true  "Attendance record ID"
+// @Success      200  {array}   viewmodels.AttendanceCorrectionResponse
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Failure      500  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendance/records/{id}/history [get]
+func (ctl *AttendanceController) GetAttendanceHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	resp, err := ctl.service.GetAttendanceHistory(uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListAttendance handles GET /attendance
+// @Summary      List attendance records
+// @Description  Retrieves a paginated list of attendance records filtered by date range, status, department, section and student.
+// @Description  Follow next_cursor for pages that stay consistent while records are added or removed.
+// @Tags         Attendance
+// @Produce      json
+// @Param        from           query     string  false  "Earliest date (YYYY-MM-DD, inclusive)"
+// @Param        to             query     string  false  "Latest date (YYYY-MM-DD, inclusive)"
+// @Param        status         query     string  false  "Attendance status"  Enums(present, absent, late, excused)
+// @Param        department_id  query     int     false  "Student department ID"
+// @Param        section_id     query     int     false  "Section ID"
+// @Param        student_id     query     int     false  "Student ID"
+// @Param        page           query     int     false  "Page number for pagination"  minimum(1)
+// @Param        limit          query     int     false  "Number of items per page"    minimum(1)  maximum(100)
+// @Param        sort           query     string  false  "Sort key, prefix with - for descending (default -date)"  Enums(date, -date, student_id, -student_id, status, -status, created_at, -created_at)
+// @Param        cursor         query     string  false  "next_cursor of the previous page; replaces page"
+// @Success      200            {object}  viewmodels.AttendanceListResponse
+// @Failure      400            {object}  viewmodels.ErrorResponse
+// @Failure      500            {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /attendance [get]
+func (ctl *AttendanceController) ListAttendance(c *gin.Context) {
+	var query viewmodels.AttendanceListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	resp, err := ctl.service.ListAttendance(query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
 }
 
 // GetAttendanceByStudentID handles GET /attendance/:student_id
 // @Summary      Get attendance by student ID
-// @Description  Retrieves all attendance records for a specific student.
+// @Description  Retrieves a paginated list of a student's attendance records, filtered and paged like GET /attendance.
 // @Tags         Attendance
 // @Produce      json
-// @Param        student_id  path      int  true  "Student ID"
-// @Success      200         {array}   viewmodels.AttendanceResponse
+// @Param        student_id  path      int     true   "Student ID"
+// @Param        from        query     string  false  "Earliest date (YYYY-MM-DD, inclusive)"
+// @Param        to          query     string  false  "Latest date (YYYY-MM-DD, inclusive)"
+// @Param        status      query     string  false  "Attendance status"  Enums(present, absent, late, excused)
+// @Param        section_id  query     int     false  "Section ID"
+// @Param        page        query     int     false  "Page number for pagination"  minimum(1)
+// @Param        limit       query     int     false  "Number of items per page"    minimum(1)  maximum(100)
+// @Param        sort        query     string  false  "Sort key, prefix with - for descending (default -date)"  Enums(date, -date, status, -status, created_at, -created_at)
+// @Param        cursor      query     string  false  "next_cursor of the previous page; replaces page"
+// @Success      200         {object}  viewmodels.AttendanceListResponse
 // @Failure      400         {object}  viewmodels.ErrorResponse
+// @Failure      404         {object}  viewmodels.ErrorResponse
 // @Failure      500         {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
 // @Router       /attendance/{student_id} [get]
 func (ctl *AttendanceController) GetAttendanceByStudentID(c *gin.Context) {
 	idStr := c.Param("student_id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid student id"})
+		invalidParam(c, "student_id")
+		return
+	}
+	var query viewmodels.AttendanceListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		bindError(c, err)
 		return
 	}
 
-	resp, err := ctl.service.GetAttendanceByStudentID(uint(id))
+	resp, err := ctl.service.GetAttendanceByStudentID(uint(id), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
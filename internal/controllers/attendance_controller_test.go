@@ -2,12 +2,13 @@ package controllers_test
 
 import (
 	"bytes"
-	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"hrms_backend/internal/controllers"
+	"hrms_backend/internal/services"
 	"hrms_backend/internal/viewmodels"
 
 	"github.com/gin-gonic/gin"
@@ -20,25 +21,73 @@ type MockAttendanceService struct {
 	mock.Mock
 }
 
-func (m *MockAttendanceService) MarkAttendance(req viewmodels.CreateAttendanceRequest) error {
-	args := m.Called(req)
+func (m *MockAttendanceService) MarkAttendance(req viewmodels.CreateAttendanceRequest, idempotencyKey string, actor services.Actor) (*viewmodels.AttendanceResponse, bool, error) {
+	args := m.Called(req, idempotencyKey, actor)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*viewmodels.AttendanceResponse), args.Bool(1), args.Error(2)
+}
+
+func (m *MockAttendanceService) MarkBulkAttendance(req viewmodels.BulkAttendanceRequest, actor services.Actor) (*viewmodels.BulkAttendanceResponse, error) {
+	args := m.Called(req, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.BulkAttendanceResponse), args.Error(1)
+}
+
+func (m *MockAttendanceService) UpdateAttendance(id uint, req viewmodels.UpdateAttendanceRequest, actor services.Actor) (*viewmodels.AttendanceResponse, error) {
+	args := m.Called(id, req, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.AttendanceResponse), args.Error(1)
+}
+
+func (m *MockAttendanceService) CheckOut(id uint, req viewmodels.CheckOutRequest, actor services.Actor) (*viewmodels.AttendanceResponse, error) {
+	args := m.Called(id, req, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.AttendanceResponse), args.Error(1)
+}
+
+func (m *MockAttendanceService) DeleteAttendance(id uint, req viewmodels.DeleteAttendanceRequest, actor services.Actor) error {
+	args := m.Called(id, req, actor)
 	return args.Error(0)
 }
 
-func (m *MockAttendanceService) GetAttendanceByStudentID(studentID uint) ([]viewmodels.AttendanceResponse, error) {
-	args := m.Called(studentID)
+func (m *MockAttendanceService) GetAttendanceHistory(id uint) ([]viewmodels.AttendanceCorrectionResponse, error) {
+	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]viewmodels.AttendanceResponse), args.Error(1)
+	return args.Get(0).([]viewmodels.AttendanceCorrectionResponse), args.Error(1)
 }
 
-func (m *MockAttendanceService) GetWeeklyAttendance() ([]viewmodels.AttendanceResponse, error) {
-	args := m.Called()
+func (m *MockAttendanceService) ListAttendance(query viewmodels.AttendanceListQuery) (*viewmodels.AttendanceListResponse, error) {
+	args := m.Called(query)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]viewmodels.AttendanceResponse), args.Error(1)
+	return args.Get(0).(*viewmodels.AttendanceListResponse), args.Error(1)
+}
+
+func (m *MockAttendanceService) GetAttendanceByStudentID(studentID uint, query viewmodels.AttendanceListQuery) (*viewmodels.AttendanceListResponse, error) {
+	args := m.Called(studentID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.AttendanceListResponse), args.Error(1)
+}
+
+func (m *MockAttendanceService) GetAttendanceSummaryByStudentID(studentID uint, from, to time.Time) (*viewmodels.ReportRowResponse, error) {
+	args := m.Called(studentID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.ReportRowResponse), args.Error(1)
 }
 
 // --- Tests ---
@@ -47,11 +96,12 @@ func TestMarkAttendanceController(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockService := new(MockAttendanceService)
 	ctl := controllers.NewAttendanceController(mockService)
-	r := gin.Default()
+	r := newRouter()
 	r.POST("/attendance", ctl.MarkAttendance)
 
 	// Case 1: Success
-	mockService.On("MarkAttendance", mock.Anything).Return(nil).Once()
+	created := &viewmodels.AttendanceResponse{ID: 1, StudentID: 1, Status: "present"}
+	mockService.On("MarkAttendance", mock.Anything, "", mock.Anything).Return(created, true, nil).Once()
 
 	// Need a valid ISO8601 date string
 	reqBody := []byte(`{"student_id": 1, "date": "2025-12-12T09:00:00Z", "status": "present"}`)
@@ -61,38 +111,234 @@ func TestMarkAttendanceController(t *testing.T) {
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), "present")
 
 	// Case 2: Service Error (e.g., student not found)
-	mockService.On("MarkAttendance", mock.Anything).Return(errors.New("student not found")).Once()
+	mockService.On("MarkAttendance", mock.Anything, "", mock.Anything).Return(nil, false, services.ErrStudentNotFound).Once()
 	req, _ = http.NewRequest("POST", "/attendance", bytes.NewBuffer(reqBody))
 	req.Header.Set("Content-Type", "application/json")
 	w = httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"code":"student_not_found","message":"student not found"}`, w.Body.String())
+
+	// Case 3: Replay with Idempotency-Key returns the existing record
+	mockService.On("MarkAttendance", mock.Anything, "abc-123", mock.Anything).Return(created, false, nil).Once()
+	req, _ = http.NewRequest("POST", "/attendance", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "abc-123")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Case 4: Conflicting status for the same day
+	mockService.On("MarkAttendance", mock.Anything, "", mock.Anything).Return(nil, false, services.ErrAttendanceConflict).Once()
+	req, _ = http.NewRequest("POST", "/attendance", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestMarkBulkAttendanceController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAttendanceService)
+	ctl := controllers.NewAttendanceController(mockService)
+	r := newRouter()
+	r.POST("/attendance/bulk", ctl.MarkBulkAttendance)
+
+	// Case 1: Partial success is still 200, failures are reported per row
+	expected := &viewmodels.BulkAttendanceResponse{Succeeded: 1, Failed: 1, Results: []viewmodels.BulkAttendanceResult{
+		{StudentID: 1, Status: "present", Success: true, Created: true, AttendanceID: 5},
+		{StudentID: 2, Status: "absent", Error: "student not found"},
+	}}
+	mockService.On("MarkBulkAttendance", mock.Anything, mock.Anything).Return(expected, nil).Once()
+
+	reqBody := []byte(`{"date": "2025-12-12T09:00:00Z", "records": [{"student_id": 1, "status": "present"}, {"student_id": 2, "status": "absent"}]}`)
+	req, _ := http.NewRequest("POST", "/attendance/bulk", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "student not found")
+
+	// Case 2: Invalid status inside a row
+	reqBody = []byte(`{"date": "2025-12-12T09:00:00Z", "records": [{"student_id": 1, "status": "sleeping"}]}`)
+	req, _ = http.NewRequest("POST", "/attendance/bulk", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Case 3: Neither records nor department
+	mockService.On("MarkBulkAttendance", mock.Anything, mock.Anything).Return(nil, services.ErrEmptyBulkRequest).Once()
+	reqBody = []byte(`{"date": "2025-12-12T09:00:00Z"}`)
+	req, _ = http.NewRequest("POST", "/attendance/bulk", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdateAttendanceController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAttendanceService)
+	ctl := controllers.NewAttendanceController(mockService)
+	r := newRouter()
+	r.PUT("/attendance/records/:id", ctl.UpdateAttendance)
+
+	// Case 1: Success
+	expected := &viewmodels.AttendanceResponse{ID: 1, Status: "present"}
+	mockService.On("UpdateAttendance", uint(1), viewmodels.UpdateAttendanceRequest{Status: "present", Reason: "typo"}, mock.Anything).Return(expected, nil).Once()
+	reqBody := []byte(`{"status": "present", "reason": "typo"}`)
+	req, _ := http.NewRequest("PUT", "/attendance/records/1", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Case 2: Missing reason
+	req, _ = http.NewRequest("PUT", "/attendance/records/1", bytes.NewBuffer([]byte(`{"status": "present"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Case 3: Not Found
+	mockService.On("UpdateAttendance", uint(99), mock.Anything, mock.Anything).Return(nil, services.ErrAttendanceNotFound).Once()
+	req, _ = http.NewRequest("PUT", "/attendance/records/99", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCheckOutController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAttendanceService)
+	ctl := controllers.NewAttendanceController(mockService)
+	r := newRouter()
+	r.POST("/attendance/records/:id/check-out", ctl.CheckOut)
+
+	// Case 1: Success
+	checkOut := time.Date(2025, 12, 12, 15, 0, 0, 0, time.UTC)
+	mockService.On("CheckOut", uint(1), viewmodels.CheckOutRequest{CheckOutAt: checkOut}, mock.Anything).
+		Return(&viewmodels.AttendanceResponse{ID: 1, CheckOutAt: &checkOut}, nil).Once()
+	req, _ := http.NewRequest("POST", "/attendance/records/1/check-out", bytes.NewBuffer([]byte(`{"check_out_at": "2025-12-12T15:00:00Z"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"check_out_at":"2025-12-12T15:00:00Z"`)
+
+	// Case 2: Time missing
+	req, _ = http.NewRequest("POST", "/attendance/records/1/check-out", bytes.NewBuffer([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteAttendanceController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAttendanceService)
+	ctl := controllers.NewAttendanceController(mockService)
+	r := newRouter()
+	r.DELETE("/attendance/records/:id", ctl.DeleteAttendance)
+
+	// Case 1: Success
+	mockService.On("DeleteAttendance", uint(1), viewmodels.DeleteAttendanceRequest{Reason: "duplicate"}, mock.Anything).Return(nil).Once()
+	req, _ := http.NewRequest("DELETE", "/attendance/records/1", bytes.NewBuffer([]byte(`{"reason": "duplicate"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	// Case 2: Missing reason
+	req, _ = http.NewRequest("DELETE", "/attendance/records/1", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Case 3: Not Found
+	mockService.On("DeleteAttendance", uint(99), mock.Anything, mock.Anything).Return(services.ErrAttendanceNotFound).Once()
+	req, _ = http.NewRequest("DELETE", "/attendance/records/99", bytes.NewBuffer([]byte(`{"reason": "duplicate"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListAttendanceController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAttendanceService)
+	ctl := controllers.NewAttendanceController(mockService)
+	r := newRouter()
+	r.GET("/attendance", ctl.ListAttendance)
+
+	// Case 1: Success, query string is parsed into the filter
+	expected := &viewmodels.AttendanceListResponse{
+		Data:  []viewmodels.AttendanceResponse{{ID: 1, StudentID: 2, Status: "absent"}},
+		Total: 1, Page: 1, Limit: 20,
+	}
+	mockService.On("ListAttendance", mock.MatchedBy(func(q viewmodels.AttendanceListQuery) bool {
+		return q.StudentID == 2 && q.Status == "absent" && q.From.Equal(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC))
+	})).Return(expected, nil).Once()
+
+	req, _ := http.NewRequest("GET", "/attendance?student_id=2&status=absent&from=2025-12-01", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total":1`)
+
+	// Case 2: Invalid filters
+	for _, q := range []string{"status=sleeping", "from=12/01/2025", "sort=name", "limit=1000"} {
+		req, _ = http.NewRequest("GET", "/attendance?"+q, nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, q)
+	}
 }
 
 func TestGetAttendanceByStudentController(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockService := new(MockAttendanceService)
 	ctl := controllers.NewAttendanceController(mockService)
-	r := gin.Default()
-	r.GET("/attendance/student/:studentID", ctl.GetAttendanceByStudentID)
+	r := newRouter()
+	r.GET("/attendance/student/:student_id", ctl.GetAttendanceByStudentID)
 
 	// Case 1: Success
-	expected := []viewmodels.AttendanceResponse{
-		{ID: 1, StudentID: 1, Status: "present"},
+	expected := &viewmodels.AttendanceListResponse{
+		Data:       []viewmodels.AttendanceResponse{{ID: 1, StudentID: 1, Status: "present"}},
+		Total:      3,
+		Limit:      1,
+		NextCursor: "abc",
 	}
-	mockService.On("GetAttendanceByStudentID", uint(1)).Return(expected, nil).Once()
+	mockService.On("GetAttendanceByStudentID", uint(1), viewmodels.AttendanceListQuery{Limit: 1, Cursor: "xyz"}).Return(expected, nil).Once()
 
-	req, _ := http.NewRequest("GET", "/attendance/student/1", nil)
+	req, _ := http.NewRequest("GET", "/attendance/student/1?limit=1&cursor=xyz", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Contains(t, w.Body.String(), "present")
+	assert.Contains(t, w.Body.String(), `"next_cursor":"abc"`)
+
+	// Case 2: Limit out of range
+	req, _ = http.NewRequest("GET", "/attendance/student/1?limit=500", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	// Case 2: Invalid ID
+	// Case 3: Invalid ID
 	req, _ = http.NewRequest("GET", "/attendance/student/abc", nil)
 	w = httptest.NewRecorder()
 	r.ServeHTTP(w, req)
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTP for the audit log of changes to students and attendance.
+type AuditController struct {
+	service services.AuditService
+}
+
+// Constructor
+func NewAuditController(service services.AuditService) *AuditController {
+	return &AuditController{service: service}
+}
+
+// Register routes under an authenticated router group (e.g., /audit); admin only
+func (ctl *AuditController) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("", middleware.RequireRoles(models.RoleAdmin), ctl.ListAuditLogs)
+}
+
+// ListAuditLogs handles GET /audit
+// @Summary      List audit log entries
+// @Description  Retrieves a paginated list of changes, newest first, with who made them and the changed fields before and after.
+// @Tags         Audit
+// @Produce      json
+// @Param        entity_type  query     string  false  "Entity type"  Enums(student, attendance)
+// @Param        entity_id    query     int     false  "Entity ID"
+// @Param        actor_id     query     int     false  "ID of the user who made the change"
+// @Param        page         query     int     false  "Page number"          minimum(1)
+// @Param        limit        query     int     false  "Items per page"       minimum(1)  maximum(100)
+// @Success      200          {object}  viewmodels.AuditLogListResponse
+// @Failure      400          {object}  viewmodels.ErrorResponse
+// @Failure      500          {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /audit [get]
+func (ctl *AuditController) ListAuditLogs(c *gin.Context) {
+	var query viewmodels.AuditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	resp, err := ctl.service.ListAuditLogs(query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,70 @@
+package controllers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hrms_backend/internal/controllers"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mock Service ---
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) ListAuditLogs(query viewmodels.AuditLogQuery) (*viewmodels.AuditLogListResponse, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.AuditLogListResponse), args.Error(1)
+}
+
+// --- Tests ---
+
+func TestListAuditLogsController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAuditService)
+	ctl := controllers.NewAuditController(mockService)
+	send := func(role, url string) *httptest.ResponseRecorder {
+		r := newRouter()
+		ctl.RegisterRoutes(r.Group("/audit", asRole(role, nil)))
+		req, _ := http.NewRequest("GET", url, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Case 1: Success with filters
+	expected := &viewmodels.AuditLogListResponse{
+		Data:  []viewmodels.AuditLogResponse{{ID: 1, Action: "update", EntityType: "attendance", EntityID: 5}},
+		Total: 1, Page: 1, Limit: 20,
+	}
+	mockService.On("ListAuditLogs", viewmodels.AuditLogQuery{EntityType: "attendance", EntityID: 5, ActorID: 3}).Return(expected, nil).Once()
+	w := send(models.RoleAdmin, "/audit?entity_type=attendance&entity_id=5&actor_id=3")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"entity_id":5`)
+
+	// Case 2: Unknown entity type
+	w = send(models.RoleAdmin, "/audit?entity_type=teacher")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Case 3: Admin only
+	w = send(models.RoleTeacher, "/audit")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Case 4: Service Error
+	mockService.On("ListAuditLogs", mock.Anything).Return(nil, errors.New("db error")).Once()
+	w = send(models.RoleAdmin, "/audit")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	mockService.AssertExpectations(t)
+}
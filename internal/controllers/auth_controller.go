@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTP for login, token refresh and user accounts.
+type AuthController struct {
+	service services.AuthService
+}
+
+// Constructor
+func NewAuthController(service services.AuthService) *AuthController {
+	return &AuthController{service: service}
+}
+
+// Register routes under a router group (e.g., /auth). These are public.
+func (ctl *AuthController) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/login", ctl.Login)
+	rg.POST("/refresh", ctl.Refresh)
+}
+
+// Register user management routes under an authenticated group (e.g., /users)
+func (ctl *AuthController) RegisterUserRoutes(rg *gin.RouterGroup) {
+	rg.POST("", middleware.RequireRoles(models.RoleAdmin), ctl.CreateUser)
+}
+
+// Login handles POST /auth/login
+// @Summary      Log in
+// @Description  Exchanges an email and password for a short lived access token and a refresh token.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        credentials  body      viewmodels.LoginRequest  true  "Email and password"
+// @Success      200          {object}  viewmodels.TokenResponse
+// @Failure      400          {object}  viewmodels.ErrorResponse
+// @Failure      401          {object}  viewmodels.ErrorResponse
+// @Router       /auth/login [post]
+func (ctl *AuthController) Login(c *gin.Context) {
+	var req viewmodels.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	tokens, err := ctl.service.Login(req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Refresh handles POST /auth/refresh
+// @Summary      Refresh tokens
+// @Description  Exchanges a valid refresh token for a new access and refresh token pair.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        token  body      viewmodels.RefreshRequest  true  "Refresh token"
+// @Success      200    {object}  viewmodels.TokenResponse
+// @Failure      400    {object}  viewmodels.ErrorResponse
+// @Failure      401    {object}  viewmodels.ErrorResponse
+// @Router       /auth/refresh [post]
+func (ctl *AuthController) Refresh(c *gin.Context) {
+	var req viewmodels.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	tokens, err := ctl.service.Refresh(req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// CreateUser handles POST /users
+// @Summary      Create a user
+// @Description  Creates an admin, teacher or student account. Student accounts must reference an existing student. Admin only.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        user  body      viewmodels.CreateUserRequest  true  "Account details"
+// @Success      201   {object}  viewmodels.UserResponse
+// @Failure      400   {object}  viewmodels.ErrorResponse
+// @Failure      409   {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users [post]
+func (ctl *AuthController) CreateUser(c *gin.Context) {
+	var req viewmodels.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	user, err := ctl.service.CreateUser(req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
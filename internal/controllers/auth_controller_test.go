@@ -0,0 +1,194 @@
+package controllers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hrms_backend/internal/controllers"
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mock Service ---
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) Login(req viewmodels.LoginRequest) (*viewmodels.TokenResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.TokenResponse), args.Error(1)
+}
+
+func (m *MockAuthService) Refresh(req viewmodels.RefreshRequest) (*viewmodels.TokenResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.TokenResponse), args.Error(1)
+}
+
+func (m *MockAuthService) ParseAccessToken(token string) (*services.Principal, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.Principal), args.Error(1)
+}
+
+func (m *MockAuthService) CreateUser(req viewmodels.CreateUserRequest) (*viewmodels.UserResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.UserResponse), args.Error(1)
+}
+
+func (m *MockAuthService) EnsureAdmin(email, password string) error {
+	args := m.Called(email, password)
+	return args.Error(0)
+}
+
+// --- Helpers ---
+
+// asRole stands in for middleware.Authenticate, so routes registered through
+// RegisterRoutes can be tested with a given caller
+func asRole(role string, studentID *uint) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		middleware.SetPrincipal(c, &services.Principal{UserID: 1, Role: role, StudentID: studentID})
+	}
+}
+
+// newRouter is gin.Default with the error handler main.go installs
+func newRouter() *gin.Engine {
+	r := gin.Default()
+	r.Use(middleware.ErrorHandler())
+	return r
+}
+
+// --- Tests ---
+
+func TestLoginController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAuthService)
+	ctl := controllers.NewAuthController(mockService)
+	r := newRouter()
+	ctl.RegisterRoutes(r.Group("/auth"))
+
+	// Case 1: Success
+	mockService.On("Login", viewmodels.LoginRequest{Email: "a@a.com", Password: "secret123"}).
+		Return(&viewmodels.TokenResponse{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer"}, nil).Once()
+	req, _ := http.NewRequest("POST", "/auth/login", bytes.NewBuffer([]byte(`{"email":"a@a.com","password":"secret123"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"access_token":"access"`)
+
+	// Case 2: Wrong credentials
+	mockService.On("Login", mock.Anything).Return(nil, services.ErrInvalidCredentials).Once()
+	req, _ = http.NewRequest("POST", "/auth/login", bytes.NewBuffer([]byte(`{"email":"a@a.com","password":"nope"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRefreshController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAuthService)
+	ctl := controllers.NewAuthController(mockService)
+	r := newRouter()
+	ctl.RegisterRoutes(r.Group("/auth"))
+
+	// Case 1: Success
+	mockService.On("Refresh", viewmodels.RefreshRequest{RefreshToken: "refresh"}).
+		Return(&viewmodels.TokenResponse{AccessToken: "new"}, nil).Once()
+	req, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBuffer([]byte(`{"refresh_token":"refresh"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Case 2: Expired or invalid
+	mockService.On("Refresh", mock.Anything).Return(nil, services.ErrInvalidToken).Once()
+	req, _ = http.NewRequest("POST", "/auth/refresh", bytes.NewBuffer([]byte(`{"refresh_token":"old"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestCreateUserController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAuthService)
+	ctl := controllers.NewAuthController(mockService)
+	body := []byte(`{"email":"t@t.com","password":"password1","role":"teacher"}`)
+
+	send := func(role string, body []byte) int {
+		r := newRouter()
+		ctl.RegisterUserRoutes(r.Group("/users", asRole(role, nil)))
+		req, _ := http.NewRequest("POST", "/users", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Case 1: Success
+	mockService.On("CreateUser", mock.Anything).Return(&viewmodels.UserResponse{ID: 2, Role: "teacher"}, nil).Once()
+	assert.Equal(t, http.StatusCreated, send(models.RoleAdmin, body))
+
+	// Case 2: Only admins may create users
+	assert.Equal(t, http.StatusForbidden, send(models.RoleTeacher, body))
+
+	// Case 3: Student account without a student
+	assert.Equal(t, http.StatusBadRequest, send(models.RoleAdmin, []byte(`{"email":"s@t.com","password":"password1","role":"student"}`)))
+
+	// Case 4: Email taken
+	mockService.On("CreateUser", mock.Anything).Return(nil, services.ErrUserExists).Once()
+	assert.Equal(t, http.StatusConflict, send(models.RoleAdmin, body))
+}
+
+func TestRouteRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStudents := new(MockStudentService)
+	mockAttendance := new(MockAttendanceService)
+	mockStudents.On("DeleteStudent", mock.Anything, mock.Anything).Return(nil)
+	mockStudents.On("GetStudentByID", mock.Anything).Return(&viewmodels.StudentResponse{ID: 7}, nil)
+	mockAttendance.On("MarkAttendance", mock.Anything, mock.Anything, mock.Anything).Return(&viewmodels.AttendanceResponse{}, true, nil)
+	studentID := uint(7)
+
+	send := func(role string, method, path, body string) int {
+		r := newRouter()
+		group := r.Group("", asRole(role, &studentID))
+		controllers.NewStudentController(mockStudents).RegisterRoutes(group.Group("/students"))
+		controllers.NewAttendanceController(mockAttendance).RegisterRoutes(group.Group("/attendance"))
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	mark := `{"student_id": 7, "date": "2025-12-12T09:00:00Z", "status": "present"}`
+
+	assert.Equal(t, http.StatusNoContent, send(models.RoleAdmin, "DELETE", "/students/1", ""))
+	assert.Equal(t, http.StatusForbidden, send(models.RoleTeacher, "DELETE", "/students/1", ""))
+	assert.Equal(t, http.StatusCreated, send(models.RoleTeacher, "POST", "/attendance/mark", mark))
+	assert.Equal(t, http.StatusForbidden, send(models.RoleStudent, "POST", "/attendance/mark", mark))
+	assert.Equal(t, http.StatusOK, send(models.RoleStudent, "GET", "/students/7", ""))
+	assert.Equal(t, http.StatusForbidden, send(models.RoleStudent, "GET", "/students/8", ""))
+	assert.Equal(t, http.StatusForbidden, send(models.RoleStudent, "GET", "/students", ""))
+}
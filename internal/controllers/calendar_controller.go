@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTP for the academic calendar: terms, holidays and closure days.
+type CalendarController struct {
+	service services.CalendarService
+}
+
+// Constructor
+func NewCalendarController(service services.CalendarService) *CalendarController {
+	return &CalendarController{service: service}
+}
+
+// Register routes under an authenticated router group (e.g., /calendar).
+// Staff can read, only admins can change the calendar.
+func (ctl *CalendarController) RegisterRoutes(rg *gin.RouterGroup) {
+	staff := middleware.RequireRoles(models.RoleAdmin, models.RoleTeacher)
+	admin := middleware.RequireRoles(models.RoleAdmin)
+
+	rg.GET("", staff, ctl.GetCalendar)
+	rg.GET("/terms", staff, ctl.ListTerms)
+	rg.POST("/terms", admin, ctl.CreateTerm)
+	rg.PUT("/terms/:id", admin, ctl.UpdateTerm)
+	rg.DELETE("/terms/:id", admin, ctl.DeleteTerm)
+	rg.GET("/closures", staff, ctl.ListClosures)
+	rg.POST("/closures", admin, ctl.CreateClosure)
+	rg.PUT("/closures/:id", admin, ctl.UpdateClosure)
+	rg.DELETE("/closures/:id", admin, ctl.DeleteClosure)
+}
+
+// GetCalendar handles GET /calendar
+// @Summary      School days of a date range
+// @Description  Lists every day of the range (less than a year) with whether classes take place and, if not, why: weekend, holiday, closure or outside term.
+// @Tags         Calendar
+// @Produce      json
+// @Param        from  query     string  true  "First day (YYYY-MM-DD, inclusive)"
+// @Param        to    query     string  true  "Last day (YYYY-MM-DD, inclusive)"
+// @Success      200   {array}   viewmodels.CalendarDayResponse
+// @Failure      400   {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /calendar [get]
+func (ctl *CalendarController) GetCalendar(c *gin.Context) {
+	var query viewmodels.CalendarQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	days, err := ctl.service.GetCalendar(query.From, query.To)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, days)
+}
+
+// ListTerms handles GET /calendar/terms
+// @Summary      List terms
+// @Description  Retrieves every term in chronological order.
+// @Tags         Calendar
+// @Produce      json
+// @Success      200  {array}   viewmodels.TermResponse
+// @Failure      500  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /calendar/terms [get]
+func (ctl *CalendarController) ListTerms(c *gin.Context) {
+	terms, err := ctl.service.ListTerms()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, terms)
+}
+
+// CreateTerm handles POST /calendar/terms
+// @Summary      Create a term
+// @Description  Adds a teaching period. Once a term exists, days outside every term are not school days.
+// @Tags         Calendar
+// @Accept       json
+// @Produce      json
+// @Param        term  body      viewmodels.TermRequest  true  "Term"
+// @Success      201   {object}  viewmodels.TermResponse
+// @Failure      400   {object}  viewmodels.ErrorResponse
+// @Failure      409   {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /calendar/terms [post]
+func (ctl *CalendarController) CreateTerm(c *gin.Context) {
+	var req viewmodels.TermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	term, err := ctl.service.CreateTerm(req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, term)
+}
+
+// UpdateTerm handles PUT /calendar/terms/:id
+// @Summary      Update a term
+// @Tags         Calendar
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                     true  "Term ID"
+// @Param        term  body      viewmodels.TermRequest  true  "Term"
+// @Success      200   {object}  viewmodels.TermResponse
+// @Failure      400   {object}  viewmodels.ErrorResponse
+// @Failure      404   {object}  viewmodels.ErrorResponse
+// @Failure      409   {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /calendar/terms/{id} [put]
+func (ctl *CalendarController) UpdateTerm(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	var req viewmodels.TermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	term, err := ctl.service.UpdateTerm(uint(id), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, term)
+}
+
+// DeleteTerm handles DELETE /calendar/terms/:id
+// @Summary      Delete a term
+// @Tags         Calendar
+// @Param        id   path  int  true  "Term ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /calendar/terms/{id} [delete]
+func (ctl *CalendarController) DeleteTerm(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	if err := ctl.service.DeleteTerm(uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListClosures handles GET /calendar/closures
+// @Summary      List holidays and closures
+// @Description  Retrieves the holidays and closure days overlapping the range, in chronological order. Both bounds are optional.
+// @Tags         Calendar
+// @Produce      json
+// @Param        from  query     string  false  "First day (YYYY-MM-DD, inclusive)"
+// @Param        to    query     string  false  "Last day (YYYY-MM-DD, inclusive)"
+// @Success      200   {array}   viewmodels.ClosureResponse
+// @Failure      400   {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /calendar/closures [get]
+func (ctl *CalendarController) ListClosures(c *gin.Context) {
+	var query viewmodels.ClosureQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	closures, err := ctl.service.ListClosures(query.From, query.To)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, closures)
+}
+
+// CreateClosure handles POST /calendar/closures
+// @Summary      Create a holiday or closure
+// @Description  Marks a range of days (both ends inclusive) as without classes. Attendance cannot be marked on them and reports leave them out.
+// @Tags         Calendar
+// @Accept       json
+// @Produce      json
+// @Param        closure  body      viewmodels.ClosureRequest  true  "Holiday or closure"
+// @Success      201      {object}  viewmodels.ClosureResponse
+// @Failure      400      {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /calendar/closures [post]
+func (ctl *CalendarController) CreateClosure(c *gin.Context) {
+	var req viewmodels.ClosureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	closure, err := ctl.service.CreateClosure(req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, closure)
+}
+
+// UpdateClosure handles PUT /calendar/closures/:id
+// @Summary      Update a holiday or closure
+// @Tags         Calendar
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                        true  "Closure ID"
+// @Param        closure  body      viewmodels.ClosureRequest  true  "Holiday or closure"
+// @Success      200      {object}  viewmodels.ClosureResponse
+// @Failure      400      {object}  viewmodels.ErrorResponse
+// @Failure      404      {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /calendar/closures/{id} [put]
+func (ctl *CalendarController) UpdateClosure(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	var req viewmodels.ClosureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	closure, err := ctl.service.UpdateClosure(uint(id), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, closure)
+}
+
+// DeleteClosure handles DELETE /calendar/closures/:id
+// @Summary      Delete a holiday or closure
+// @Tags         Calendar
+// @Param        id   path  int  true  "Closure ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /calendar/closures/{id} [delete]
+func (ctl *CalendarController) DeleteClosure(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	if err := ctl.service.DeleteClosure(uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
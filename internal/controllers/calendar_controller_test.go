@@ -0,0 +1,158 @@
+package controllers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hrms_backend/internal/controllers"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mock Service ---
+type MockCalendarService struct {
+	mock.Mock
+}
+
+func (m *MockCalendarService) NonSchoolDays(from, to time.Time) (map[time.Time]string, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[time.Time]string), args.Error(1)
+}
+
+func (m *MockCalendarService) CreateTerm(req viewmodels.TermRequest) (*viewmodels.TermResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.TermResponse), args.Error(1)
+}
+
+func (m *MockCalendarService) ListTerms() ([]viewmodels.TermResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]viewmodels.TermResponse), args.Error(1)
+}
+
+func (m *MockCalendarService) UpdateTerm(id uint, req viewmodels.TermRequest) (*viewmodels.TermResponse, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.TermResponse), args.Error(1)
+}
+
+func (m *MockCalendarService) DeleteTerm(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCalendarService) CreateClosure(req viewmodels.ClosureRequest) (*viewmodels.ClosureResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.ClosureResponse), args.Error(1)
+}
+
+func (m *MockCalendarService) ListClosures(from, to time.Time) ([]viewmodels.ClosureResponse, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]viewmodels.ClosureResponse), args.Error(1)
+}
+
+func (m *MockCalendarService) UpdateClosure(id uint, req viewmodels.ClosureRequest) (*viewmodels.ClosureResponse, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.ClosureResponse), args.Error(1)
+}
+
+func (m *MockCalendarService) DeleteClosure(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCalendarService) GetCalendar(from, to time.Time) ([]viewmodels.CalendarDayResponse, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]viewmodels.CalendarDayResponse), args.Error(1)
+}
+
+func calendarRouter(service *MockCalendarService, role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := newRouter()
+	controllers.NewCalendarController(service).RegisterRoutes(r.Group("/calendar", asRole(role, nil)))
+	return r
+}
+
+// --- Tests ---
+
+func TestCalendarController(t *testing.T) {
+	mockService := new(MockCalendarService)
+	send := func(role, method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		calendarRouter(mockService, role).ServeHTTP(w, req)
+		return w
+	}
+	christmas := time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	// Case 1: Days of a range, teachers included
+	mockService.On("GetCalendar", christmas, christmas).
+		Return([]viewmodels.CalendarDayResponse{{Date: christmas, Reason: "holiday: Christmas"}}, nil).Once()
+	w := send(models.RoleTeacher, "GET", "/calendar?from=2025-12-25&to=2025-12-25", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"school_day":false`)
+
+	// Case 2: Range missing
+	w = send(models.RoleTeacher, "GET", "/calendar", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Case 3: Create a holiday
+	req := viewmodels.ClosureRequest{Kind: "holiday", Name: "Christmas", StartDate: christmas, EndDate: christmas}
+	mockService.On("CreateClosure", req).Return(&viewmodels.ClosureResponse{ID: 1, Kind: "holiday", Name: "Christmas"}, nil).Once()
+	w = send(models.RoleAdmin, "POST", "/calendar/closures",
+		`{"kind":"holiday","name":"Christmas","start_date":"2025-12-25T00:00:00Z","end_date":"2025-12-25T00:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Case 4: Unknown kind
+	w = send(models.RoleAdmin, "POST", "/calendar/closures",
+		`{"kind":"party","name":"Christmas","start_date":"2025-12-25T00:00:00Z","end_date":"2025-12-25T00:00:00Z"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Case 5: Teachers cannot change the calendar
+	w = send(models.RoleTeacher, "DELETE", "/calendar/terms/1", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Case 6: Term name taken
+	mockService.On("UpdateTerm", uint(2), mock.Anything).Return(nil, services.ErrTermNameTaken).Once()
+	w = send(models.RoleAdmin, "PUT", "/calendar/terms/2",
+		`{"name":"Fall 2025","start_date":"2025-09-01T00:00:00Z","end_date":"2025-12-19T00:00:00Z"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Case 7: Unknown closure
+	mockService.On("DeleteClosure", uint(9)).Return(services.ErrClosureNotFound).Once()
+	w = send(models.RoleAdmin, "DELETE", "/calendar/closures/9", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockService.AssertExpectations(t)
+}
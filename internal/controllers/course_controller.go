@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTP for courses and the sections they are taught in.
+type CourseController struct {
+	service services.CourseService
+}
+
+// Constructor
+func NewCourseController(service services.CourseService) *CourseController {
+	return &CourseController{service: service}
+}
+
+// Register routes under an authenticated router group (e.g., /courses).
+// Staff can read, only admins can change courses and sections.
+func (ctl *CourseController) RegisterRoutes(rg *gin.RouterGroup) {
+	staff := middleware.RequireRoles(models.RoleAdmin, models.RoleTeacher)
+	admin := middleware.RequireRoles(models.RoleAdmin)
+
+	rg.GET("", staff, ctl.ListCourses)
+	rg.POST("", admin, ctl.CreateCourse)
+	rg.GET("/:id", staff, ctl.GetCourseByID)
+	rg.PUT("/:id", admin, ctl.UpdateCourse)
+	rg.DELETE("/:id", admin, ctl.DeleteCourse)
+	rg.GET("/:id/sections", staff, ctl.ListSections)
+	rg.POST("/:id/sections", admin, ctl.CreateSection)
+}
+
+// ListCourses handles GET /courses
+// @Summary      List courses
+// @Description  Retrieves every course, ordered by code.
+// @Tags         Courses
+// @Produce      json
+// @Success      200  {array}   viewmodels.CourseResponse
+// @Failure      500  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /courses [get]
+func (ctl *CourseController) ListCourses(c *gin.Context) {
+	courses, err := ctl.service.ListCourses()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, courses)
+}
+
+// CreateCourse handles POST /courses
+// @Summary      Create a course
+// @Description  Creates a course. Codes are unique, ignoring case.
+// @Tags         Courses
+// @Accept       json
+// @Produce      json
+// @Param        course  body      viewmodels.CourseRequest  true  "Course"
+// @Success      201     {object}  viewmodels.CourseResponse
+// @Failure      400     {object}  viewmodels.ErrorResponse
+// @Failure      409     {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /courses [post]
+func (ctl *CourseController) CreateCourse(c *gin.Context) {
+	var req viewmodels.CourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	course, err := ctl.service.CreateCourse(req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, course)
+}
+
+// GetCourseByID handles GET /courses/:id
+// @Summary      Get a course
+// @Tags         Courses
+// @Produce      json
+// @Param        id   path      int  true  "Course ID"
+// @Success      200  {object}  viewmodels.CourseResponse
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /courses/{id} [get]
+func (ctl *CourseController) GetCourseByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	course, err := ctl.service.GetCourseByID(uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, course)
+}
+
+// UpdateCourse handles PUT /courses/:id
+// @Summary      Update a course
+// @Tags         Courses
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                       true  "Course ID"
+// @Param        course  body      viewmodels.CourseRequest  true  "Course"
+// @Success      200     {object}  viewmodels.CourseResponse
+// @Failure      400     {object}  viewmodels.ErrorResponse
+// @Failure      404     {object}  viewmodels.ErrorResponse
+// @Failure      409     {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /courses/{id} [put]
+func (ctl *CourseController) UpdateCourse(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	var req viewmodels.CourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	course, err := ctl.service.UpdateCourse(uint(id), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, course)
+}
+
+// DeleteCourse handles DELETE /courses/:id
+// @Summary      Delete a course
+// @Description  Deletes a course without sections. Returns 409 while it still has sections.
+// @Tags         Courses
+// @Produce      json
+// @Param        id   path  int  true  "Course ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Failure      409  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /courses/{id} [delete]
+func (ctl *CourseController) DeleteCourse(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	if err := ctl.service.DeleteCourse(uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListSections handles GET /courses/:id/sections
+// @Summary      List the sections of a course
+// @Description  Retrieves the sections of a course, ordered by name, with their number of enrolled students.
+// @Tags         Courses
+// @Produce      json
+// @Param        id   path      int  true  "Course ID"
+// @Success      200  {array}   viewmodels.SectionResponse
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /courses/{id}/sections [get]
+func (ctl *CourseController) ListSections(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	sections, err := ctl.service.ListSections(uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sections)
+}
+
+// CreateSection handles POST /courses/:id/sections
+// @Summary      Create a section
+// @Description  Adds a section to a course. Section names are unique within a course.
+// @Tags         Courses
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                        true  "Course ID"
+// @Param        section  body      viewmodels.SectionRequest  true  "Section"
+// @Success      201      {object}  viewmodels.SectionResponse
+// @Failure      400      {object}  viewmodels.ErrorResponse
+// @Failure      404      {object}  viewmodels.ErrorResponse
+// @Failure      409      {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /courses/{id}/sections [post]
+func (ctl *CourseController) CreateSection(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	var req viewmodels.SectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	section, err := ctl.service.CreateSection(uint(id), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, section)
+}
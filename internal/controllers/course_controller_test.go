@@ -0,0 +1,206 @@
+package controllers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hrms_backend/internal/controllers"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mock Service ---
+type MockCourseService struct {
+	mock.Mock
+}
+
+func (m *MockCourseService) CreateCourse(req viewmodels.CourseRequest) (*viewmodels.CourseResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.CourseResponse), args.Error(1)
+}
+
+func (m *MockCourseService) ListCourses() ([]viewmodels.CourseResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]viewmodels.CourseResponse), args.Error(1)
+}
+
+func (m *MockCourseService) GetCourseByID(id uint) (*viewmodels.CourseResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.CourseResponse), args.Error(1)
+}
+
+func (m *MockCourseService) UpdateCourse(id uint, req viewmodels.CourseRequest) (*viewmodels.CourseResponse, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.CourseResponse), args.Error(1)
+}
+
+func (m *MockCourseService) DeleteCourse(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCourseService) CreateSection(courseID uint, req viewmodels.SectionRequest) (*viewmodels.SectionResponse, error) {
+	args := m.Called(courseID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.SectionResponse), args.Error(1)
+}
+
+func (m *MockCourseService) ListSections(courseID uint) ([]viewmodels.SectionResponse, error) {
+	args := m.Called(courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]viewmodels.SectionResponse), args.Error(1)
+}
+
+func (m *MockCourseService) GetSectionByID(id uint) (*viewmodels.SectionResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.SectionResponse), args.Error(1)
+}
+
+func (m *MockCourseService) UpdateSection(id uint, req viewmodels.SectionRequest) (*viewmodels.SectionResponse, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.SectionResponse), args.Error(1)
+}
+
+func (m *MockCourseService) DeleteSection(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCourseService) EnrollStudents(sectionID uint, req viewmodels.EnrollStudentsRequest) (*viewmodels.EnrollStudentsResponse, error) {
+	args := m.Called(sectionID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.EnrollStudentsResponse), args.Error(1)
+}
+
+func (m *MockCourseService) UnenrollStudent(sectionID, studentID uint) error {
+	args := m.Called(sectionID, studentID)
+	return args.Error(0)
+}
+
+func (m *MockCourseService) ListEnrolledStudents(sectionID uint) ([]viewmodels.StudentResponse, error) {
+	args := m.Called(sectionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]viewmodels.StudentResponse), args.Error(1)
+}
+
+func courseRouter(service *MockCourseService, role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := newRouter()
+	auth := asRole(role, nil)
+	controllers.NewCourseController(service).RegisterRoutes(r.Group("/courses", auth))
+	controllers.NewSectionController(service).RegisterRoutes(r.Group("/sections", auth))
+	return r
+}
+
+// --- Tests ---
+
+func TestCourseController(t *testing.T) {
+	mockService := new(MockCourseService)
+	send := func(role, method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		courseRouter(mockService, role).ServeHTTP(w, req)
+		return w
+	}
+
+	// Case 1: Create
+	mockService.On("CreateCourse", viewmodels.CourseRequest{Code: "CS101", Name: "Programming"}).
+		Return(&viewmodels.CourseResponse{ID: 1, Code: "CS101", Name: "Programming"}, nil).Once()
+	w := send(models.RoleAdmin, "POST", "/courses", `{"code":"CS101","name":"Programming"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Case 2: Teachers cannot create courses
+	w = send(models.RoleTeacher, "POST", "/courses", `{"code":"CS101","name":"Programming"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Case 3: Delete while sections remain
+	mockService.On("DeleteCourse", uint(1)).Return(services.ErrCourseInUse).Once()
+	w = send(models.RoleAdmin, "DELETE", "/courses/1", "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "course_in_use")
+
+	// Case 4: Sections of a course, teachers included
+	mockService.On("ListSections", uint(1)).Return([]viewmodels.SectionResponse{{ID: 3, CourseID: 1, Name: "A", Students: 25}}, nil).Once()
+	w = send(models.RoleTeacher, "GET", "/courses/1/sections", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"students":25`)
+
+	// Case 5: Section name missing
+	w = send(models.RoleAdmin, "POST", "/courses/1/sections", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSectionEnrollmentController(t *testing.T) {
+	mockService := new(MockCourseService)
+	send := func(role, method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		courseRouter(mockService, role).ServeHTTP(w, req)
+		return w
+	}
+
+	// Case 1: Enroll
+	mockService.On("EnrollStudents", uint(3), viewmodels.EnrollStudentsRequest{StudentIDs: []uint{1, 2}}).
+		Return(&viewmodels.EnrollStudentsResponse{Enrolled: 1, AlreadyEnrolled: 1}, nil).Once()
+	w := send(models.RoleAdmin, "POST", "/sections/3/enrollments", `{"student_ids":[1,2]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"already_enrolled":1`)
+
+	// Case 2: Empty list
+	w = send(models.RoleAdmin, "POST", "/sections/3/enrollments", `{"student_ids":[]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Case 3: Unenroll a student that is not enrolled
+	mockService.On("UnenrollStudent", uint(3), uint(4)).Return(services.ErrEnrollmentNotFound).Once()
+	w = send(models.RoleAdmin, "DELETE", "/sections/3/enrollments/4", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Case 4: Bad student ID
+	w = send(models.RoleAdmin, "DELETE", "/sections/3/enrollments/abc", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Case 5: Roster, teachers included
+	mockService.On("ListEnrolledStudents", uint(3)).Return([]viewmodels.StudentResponse{{ID: 1, Name: "Alice"}}, nil).Once()
+	w = send(models.RoleTeacher, "GET", "/sections/3/enrollments", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Alice")
+
+	mockService.AssertExpectations(t)
+}
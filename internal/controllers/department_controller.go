@@ -0,0 +1,273 @@
+package controllers
+
+import (
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTP for departments and their attendance.
+type DepartmentController struct {
+	service services.DepartmentService
+}
+
+// Constructor
+func NewDepartmentController(service services.DepartmentService) *DepartmentController {
+	return &DepartmentController{service: service}
+}
+
+// Register routes under an authenticated router group (e.g., /departments).
+// Staff can read, only admins can change departments.
+func (ctl *DepartmentController) RegisterRoutes(rg *gin.RouterGroup) {
+	staff := middleware.RequireRoles(models.RoleAdmin, models.RoleTeacher)
+	admin := middleware.RequireRoles(models.RoleAdmin)
+
+	rg.GET("", staff, ctl.ListDepartments)
+	rg.POST("", admin, ctl.CreateDepartment)
+	rg.GET("/attendance/summary", staff, ctl.GetAttendanceSummary)
+	rg.GET("/:id", staff, ctl.GetDepartmentByID)
+	rg.PUT("/:id", admin, ctl.UpdateDepartment)
+	rg.DELETE("/:id", admin, ctl.DeleteDepartment)
+	rg.POST("/:id/merge", admin, ctl.MergeDepartment)
+	rg.GET("/:id/attendance/summary", staff, ctl.GetDepartmentAttendanceSummary)
+}
+
+// ListDepartments handles GET /departments
+// @Summary      List departments
+// @Description  Retrieves every department, ordered by name, with its number of students.
+// @Tags         Departments
+// @Produce      json
+// @Success      200  {array}   viewmodels.DepartmentResponse
+// @Failure      500  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /departments [get]
+func (ctl *DepartmentController) ListDepartments(c *gin.Context) {
+	departments, err := ctl.service.ListDepartments()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, departments)
+}
+
+// CreateDepartment handles POST /departments
+// @Summary      Create a department
+// @Description  Creates a department. Names are unique, ignoring case.
+// @Tags         Departments
+// @Accept       json
+// @Produce      json
+// @Param        department  body      viewmodels.DepartmentRequest  true  "Department"
+// @Success      201         {object}  viewmodels.DepartmentResponse
+// @Failure      400         {object}  viewmodels.ErrorResponse
+// @Failure      409         {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /departments [post]
+func (ctl *DepartmentController) CreateDepartment(c *gin.Context) {
+	var req viewmodels.DepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	department, err := ctl.service.CreateDepartment(req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, department)
+}
+
+// GetDepartmentByID handles GET /departments/:id
+// @Summary      Get a department
+// @Tags         Departments
+// @Produce      json
+// @Param        id   path      int  true  "Department ID"
+// @Success      200  {object}  viewmodels.DepartmentResponse
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /departments/{id} [get]
+func (ctl *DepartmentController) GetDepartmentByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	department, err := ctl.service.GetDepartmentByID(uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, department)
+}
+
+// UpdateDepartment handles PUT /departments/:id
+// @Summary      Rename a department
+// @Tags         Departments
+// @Accept       json
+// @Produce      json
+// @Param        id          path      int                           true  "Department ID"
+// @Param        department  body      viewmodels.DepartmentRequest  true  "Department"
+// @Success      200         {object}  viewmodels.DepartmentResponse
+// @Failure      400         {object}  viewmodels.ErrorResponse
+// @Failure      404         {object}  viewmodels.ErrorResponse
+// @Failure      409         {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /departments/{id} [put]
+func (ctl *DepartmentController) UpdateDepartment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	var req viewmodels.DepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	department, err := ctl.service.UpdateDepartment(uint(id), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, department)
+}
+
+// DeleteDepartment handles DELETE /departments/:id
+// @Summary      Delete a department
+// @Description  Deletes a department without students. Returns 409 while students, soft deleted ones included, still belong to it.
+// @Tags         Departments
+// @Produce      json
+// @Param        id   path  int  true  "Department ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Failure      409  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /departments/{id} [delete]
+func (ctl *DepartmentController) DeleteDepartment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	if err := ctl.service.DeleteDepartment(uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// MergeDepartment handles POST /departments/:id/merge
+// @Summary      Merge a department into another
+// @Description  Moves every student of the department to into_id and deletes it, e.g. to fold "Computer Science" into "CS".
+// @Tags         Departments
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int                                true  "Department ID to merge away"
+// @Param        merge  body      viewmodels.MergeDepartmentRequest  true  "Target department"
+// @Success      200    {object}  viewmodels.MergeDepartmentResponse
+// @Failure      400    {object}  viewmodels.ErrorResponse
+// @Failure      404    {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /departments/{id}/merge [post]
+func (ctl *DepartmentController) MergeDepartment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	var req viewmodels.MergeDepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	resp, err := ctl.service.MergeDepartment(uint(id), req, middleware.CurrentActor(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetAttendanceSummary handles GET /departments/attendance/summary
+// @Summary      Attendance per department
+// @Description  Counts present, absent, late and excused records per department over a date range, with the attendance percentage (present + late over total).
+// @Tags         Departments
+// @Produce      json
+// @Param        from  query     string  true  "First day (YYYY-MM-DD, inclusive)"
+// @Param        to    query     string  true  "Last day (YYYY-MM-DD, inclusive)"
+// @Success      200   {array}   viewmodels.DepartmentAttendanceResponse
+// @Failure      400   {object}  viewmodels.ErrorResponse
+// @Failure      500   {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /departments/attendance/summary [get]
+func (ctl *DepartmentController) GetAttendanceSummary(c *gin.Context) {
+	query, ok := bindDepartmentAttendanceQuery(c)
+	if !ok {
+		return
+	}
+
+	rows, err := ctl.service.GetAttendanceSummary(query.From, query.To)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, rows)
+}
+
+// GetDepartmentAttendanceSummary handles GET /departments/:id/attendance/summary
+// @Summary      Attendance of one department
+// @Description  Counts present, absent, late and excused records of the department's students over a date range.
+// @Tags         Departments
+// @Produce      json
+// @Param        id    path      int     true  "Department ID"
+// @Param        from  query     string  true  "First day (YYYY-MM-DD, inclusive)"
+// @Param        to    query     string  true  "Last day (YYYY-MM-DD, inclusive)"
+// @Success      200   {object}  viewmodels.DepartmentAttendanceResponse
+// @Failure      400   {object}  viewmodels.ErrorResponse
+// @Failure      404   {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /departments/{id}/attendance/summary [get]
+func (ctl *DepartmentController) GetDepartmentAttendanceSummary(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+	query, ok := bindDepartmentAttendanceQuery(c)
+	if !ok {
+		return
+	}
+
+	row, err := ctl.service.GetDepartmentAttendanceSummary(uint(id), query.From, query.To)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, row)
+}
+
+func bindDepartmentAttendanceQuery(c *gin.Context) (viewmodels.DepartmentAttendanceQuery, bool) {
+	var query viewmodels.DepartmentAttendanceQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		bindError(c, err)
+		return query, false
+	}
+	if query.To.Before(query.From) {
+		_ = c.Error(services.ValidationError("to", "must not be before from"))
+		return query, false
+	}
+	return query, true
+}
@@ -0,0 +1,181 @@
+package controllers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hrms_backend/internal/controllers"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mock Service ---
+type MockDepartmentService struct {
+	mock.Mock
+}
+
+func (m *MockDepartmentService) CreateDepartment(req viewmodels.DepartmentRequest) (*viewmodels.DepartmentResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.DepartmentResponse), args.Error(1)
+}
+
+func (m *MockDepartmentService) ListDepartments() ([]viewmodels.DepartmentResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]viewmodels.DepartmentResponse), args.Error(1)
+}
+
+func (m *MockDepartmentService) GetDepartmentByID(id uint) (*viewmodels.DepartmentResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.DepartmentResponse), args.Error(1)
+}
+
+func (m *MockDepartmentService) UpdateDepartment(id uint, req viewmodels.DepartmentRequest) (*viewmodels.DepartmentResponse, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.DepartmentResponse), args.Error(1)
+}
+
+func (m *MockDepartmentService) DeleteDepartment(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockDepartmentService) MergeDepartment(id uint, req viewmodels.MergeDepartmentRequest, actor services.Actor) (*viewmodels.MergeDepartmentResponse, error) {
+	args := m.Called(id, req, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.MergeDepartmentResponse), args.Error(1)
+}
+
+func (m *MockDepartmentService) GetAttendanceSummary(from, to time.Time) ([]viewmodels.DepartmentAttendanceResponse, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]viewmodels.DepartmentAttendanceResponse), args.Error(1)
+}
+
+func (m *MockDepartmentService) GetDepartmentAttendanceSummary(id uint, from, to time.Time) (*viewmodels.DepartmentAttendanceResponse, error) {
+	args := m.Called(id, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.DepartmentAttendanceResponse), args.Error(1)
+}
+
+func departmentRouter(service *MockDepartmentService, role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := newRouter()
+	controllers.NewDepartmentController(service).RegisterRoutes(r.Group("/departments", asRole(role, nil)))
+	return r
+}
+
+// --- Tests ---
+
+func TestDepartmentCRUDController(t *testing.T) {
+	mockService := new(MockDepartmentService)
+	send := func(role, method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		departmentRouter(mockService, role).ServeHTTP(w, req)
+		return w
+	}
+
+	// Case 1: List, teachers included
+	mockService.On("ListDepartments").Return([]viewmodels.DepartmentResponse{{ID: 1, Name: "CS", Students: 3}}, nil).Once()
+	w := send(models.RoleTeacher, "GET", "/departments", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"students":3`)
+
+	// Case 2: Create
+	mockService.On("CreateDepartment", viewmodels.DepartmentRequest{Name: "CS"}).Return(&viewmodels.DepartmentResponse{ID: 1, Name: "CS"}, nil).Once()
+	w = send(models.RoleAdmin, "POST", "/departments", `{"name":"CS"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Case 3: Name missing
+	w = send(models.RoleAdmin, "POST", "/departments", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Case 4: Teachers cannot change departments
+	w = send(models.RoleTeacher, "POST", "/departments", `{"name":"CS"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Case 5: Name taken
+	mockService.On("UpdateDepartment", uint(2), viewmodels.DepartmentRequest{Name: "CS"}).Return(nil, services.ErrDepartmentNameTaken).Once()
+	w = send(models.RoleAdmin, "PUT", "/departments/2", `{"name":"CS"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Case 6: Delete while students remain
+	mockService.On("DeleteDepartment", uint(1)).Return(services.ErrDepartmentInUse).Once()
+	w = send(models.RoleAdmin, "DELETE", "/departments/1", "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "department_in_use")
+
+	// Case 7: Merge
+	mockService.On("MergeDepartment", uint(2), viewmodels.MergeDepartmentRequest{IntoID: 1}, mock.Anything).
+		Return(&viewmodels.MergeDepartmentResponse{Department: viewmodels.DepartmentResponse{ID: 1, Name: "CS"}, MovedStudents: 4}, nil).Once()
+	w = send(models.RoleAdmin, "POST", "/departments/2/merge", `{"into_id":1}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"moved_students":4`)
+
+	// Case 8: Not found
+	mockService.On("GetDepartmentByID", uint(9)).Return(nil, services.ErrDepartmentNotFound).Once()
+	w = send(models.RoleAdmin, "GET", "/departments/9", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestDepartmentAttendanceSummaryController(t *testing.T) {
+	mockService := new(MockDepartmentService)
+	r := departmentRouter(mockService, models.RoleTeacher)
+	get := func(url string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", url, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	// Case 1: All departments
+	mockService.On("GetAttendanceSummary", from, to).
+		Return([]viewmodels.DepartmentAttendanceResponse{{DepartmentID: 1, DepartmentName: "CS", Total: 4, Percentage: 50}}, nil).Once()
+	w := get("/departments/attendance/summary?from=2025-03-01&to=2025-03-31")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"department_name":"CS"`)
+
+	// Case 2: One department
+	mockService.On("GetDepartmentAttendanceSummary", uint(1), from, to).
+		Return(&viewmodels.DepartmentAttendanceResponse{DepartmentID: 1, Percentage: 75}, nil).Once()
+	w = get("/departments/1/attendance/summary?from=2025-03-01&to=2025-03-31")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"percentage":75`)
+
+	// Case 3: Range missing or reversed
+	assert.Equal(t, http.StatusBadRequest, get("/departments/attendance/summary").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/departments/attendance/summary?from=2025-03-31&to=2025-03-01").Code)
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	"hrms_backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handlers do not write error responses themselves: they attach the error to the context
+// and return, and middleware.ErrorHandler renders it as a viewmodels.ErrorResponse.
+
+// bindError reports a request body or query that could not be parsed or failed validation (400)
+func bindError(c *gin.Context, err error) {
+	_ = c.Error(err).SetType(gin.ErrorTypeBind)
+}
+
+// invalidParam reports a path parameter that is not a positive integer (400)
+func invalidParam(c *gin.Context, name string) {
+	_ = c.Error(services.ValidationError(name, "must be a positive integer"))
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"hrms_backend/internal/cronJob"
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTP for the scheduled background jobs.
+type JobController struct {
+	registry *cronJob.Registry
+}
+
+// Constructor
+func NewJobController(registry *cronJob.Registry) *JobController {
+	return &JobController{registry: registry}
+}
+
+// Register routes under an authenticated router group (e.g., /jobs); admin only
+func (ctl *JobController) RegisterRoutes(rg *gin.RouterGroup) {
+	admin := middleware.RequireRoles(models.RoleAdmin)
+	rg.GET("", admin, ctl.GetJobs)
+	rg.POST("/:name/run", admin, ctl.RunJob)
+}
+
+// GetJobs handles GET /jobs
+// @Summary      List scheduled jobs
+// @Description  Lists the background jobs with their schedule, whether they are enabled, and the time, status and duration of their last run.
+// @Tags         Jobs
+// @Produce      json
+// @Success      200  {array}  viewmodels.JobStatusResponse
+// @Security     BearerAuth
+// @Router       /jobs [get]
+func (ctl *JobController) GetJobs(c *gin.Context) {
+	c.JSON(http.StatusOK, ctl.registry.Statuses())
+}
+
+// RunJob handles POST /jobs/:name/run
+// @Summary      Run a job now
+// @Description  Starts a job right away in the background, outside its schedule and even if it is disabled, and returns its status without waiting. GET /jobs shows the outcome once it finished.
+// @Tags         Jobs
+// @Produce      json
+// @Param        name  path      string  true  "Job name, e.g. weekly_report"
+// @Success      202   {object}  viewmodels.JobStatusResponse
+// @Failure      404   {object}  viewmodels.ErrorResponse
+// @Failure      409   {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /jobs/{name}/run [post]
+func (ctl *JobController) RunJob(c *gin.Context) {
+	name := c.Param("name")
+	// the job's own failure is recorded in its status
+	if _, err := ctl.registry.RunNow(name); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	status, err := ctl.registry.Status(name)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusAccepted, status)
+}
@@ -0,0 +1,80 @@
+package controllers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hrms_backend/internal/controllers"
+	"hrms_backend/internal/cronJob"
+	"hrms_backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetJobsController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := cronJob.NewRegistry(cron.New())
+	_ = registry.Register(cronJob.Job{Name: "weekly_report", Schedule: "@weekly", Enabled: true, Run: func() error { return nil }})
+	done, _ := registry.RunNow("weekly_report")
+	<-done
+
+	ctl := controllers.NewJobController(registry)
+	r := newRouter()
+	ctl.RegisterRoutes(r.Group("/jobs", asRole(models.RoleAdmin, nil)))
+
+	req, _ := http.NewRequest("GET", "/jobs", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"weekly_report"`)
+	assert.Contains(t, w.Body.String(), `"last_status":"success"`)
+}
+
+func TestRunJobController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := cronJob.NewRegistry(cron.New())
+	release := make(chan struct{})
+	_ = registry.Register(cronJob.Job{Name: "weekly_report", Schedule: "@weekly", Run: func() error {
+		<-release
+		return errors.New("smtp down")
+	}})
+	ctl := controllers.NewJobController(registry)
+	send := func(role, url string) *httptest.ResponseRecorder {
+		r := newRouter()
+		ctl.RegisterRoutes(r.Group("/jobs", asRole(role, nil)))
+		req, _ := http.NewRequest("POST", url, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Case 1: A (disabled) job starts without the request waiting for it
+	w := send(models.RoleAdmin, "/jobs/weekly_report/run")
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), `"running":true`)
+
+	// Case 2: Not twice at the same time; the outcome shows in its status once done
+	w = send(models.RoleAdmin, "/jobs/weekly_report/run")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "job_running")
+	close(release)
+	assert.Eventually(t, func() bool {
+		status, _ := registry.Status("weekly_report")
+		return status.LastError == "smtp down"
+	}, time.Second, 10*time.Millisecond)
+
+	// Case 3: Unknown job
+	w = send(models.RoleAdmin, "/jobs/nightly/run")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "job_not_found")
+
+	// Case 4: Admin only
+	w = send(models.RoleTeacher, "/jobs/weekly_report/run")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
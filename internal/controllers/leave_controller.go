@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTP for reviewing leave requests. Students submit their own under /me/leave-requests.
+type LeaveController struct {
+	service services.LeaveService
+}
+
+// Constructor
+func NewLeaveController(service services.LeaveService) *LeaveController {
+	return &LeaveController{service: service}
+}
+
+// Register routes under an authenticated router group (e.g., /leave-requests); staff only
+func (ctl *LeaveController) RegisterRoutes(rg *gin.RouterGroup) {
+	staff := middleware.RequireRoles(models.RoleAdmin, models.RoleTeacher)
+
+	rg.GET("", staff, ctl.ListLeave)
+	rg.POST("", staff, ctl.SubmitLeave)
+	rg.GET("/:id", staff, ctl.GetLeave)
+	rg.POST("/:id/approve", staff, ctl.ApproveLeave)
+	rg.POST("/:id/reject", staff, ctl.RejectLeave)
+	rg.POST("/:id/cancel", staff, ctl.CancelLeave)
+}
+
+// ListLeave handles GET /leave-requests
+// @Summary      List leave requests
+// @Description  Retrieves leave requests, newest first, optionally of one student or in one state.
+// @Tags         Leave
+// @Produce      json
+// @Param        student_id  query     int     false  "Student ID"
+// @Param        status      query     string  false  "pending, approved, rejected or cancelled"
+// @Success      200         {array}   viewmodels.LeaveResponse
+// @Failure      400         {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /leave-requests [get]
+func (ctl *LeaveController) ListLeave(c *gin.Context) {
+	var query viewmodels.LeaveQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	leaves, err := ctl.service.ListLeave(query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, leaves)
+}
+
+// SubmitLeave handles POST /leave-requests
+// @Summary      Submit a leave request for a student
+// @Description  Files a pending leave request on behalf of a student. Fails with 409 leave_overlap if a pending or approved request already covers some of the days.
+// @Tags         Leave
+// @Accept       json
+// @Produce      json
+// @Param        leave  body      viewmodels.CreateLeaveRequest  true  "Leave request"
+// @Success      201    {object}  viewmodels.LeaveResponse
+// @Failure      400    {object}  viewmodels.ErrorResponse
+// @Failure      404    {object}  viewmodels.ErrorResponse
+// @Failure      409    {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /leave-requests [post]
+func (ctl *LeaveController) SubmitLeave(c *gin.Context) {
+	var req viewmodels.CreateLeaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	leave, err := ctl.service.SubmitLeave(req.StudentID, req.LeaveRequestRequest, middleware.CurrentActor(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, leave)
+}
+
+// GetLeave handles GET /leave-requests/:id
+// @Summary      Get a leave request
+// @Tags         Leave
+// @Produce      json
+// @Param        id   path      int  true  "Leave request ID"
+// @Success      200  {object}  viewmodels.LeaveResponse
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /leave-requests/{id} [get]
+func (ctl *LeaveController) GetLeave(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	leave, err := ctl.service.GetLeave(uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, leave)
+}
+
+// ApproveLeave handles POST /leave-requests/:id/approve
+// @Summary      Approve a leave request
+// @Description  Approves a pending request and marks the student "excused" on every school day it covers: existing records are converted (with a correction entry) and a whole day record is created where there was none. Unmarked class sessions on those days count as excused. Fails with 409 leave_not_pending once decided or cancelled.
+// @Tags         Leave
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                            true   "Leave request ID"
+// @Param        review  body      viewmodels.ReviewLeaveRequest  false  "Note to the student"
+// @Success      200     {object}  viewmodels.LeaveResponse
+// @Failure      400     {object}  viewmodels.ErrorResponse
+// @Failure      404     {object}  viewmodels.ErrorResponse
+// @Failure      409     {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /leave-requests/{id}/approve [post]
+func (ctl *LeaveController) ApproveLeave(c *gin.Context) {
+	ctl.review(c, ctl.service.ApproveLeave)
+}
+
+// RejectLeave handles POST /leave-requests/:id/reject
+// @Summary      Reject a leave request
+// @Description  Rejects a pending request; attendance is left as it is. Fails with 409 leave_not_pending once decided or cancelled.
+// @Tags         Leave
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                            true   "Leave request ID"
+// @Param        review  body      viewmodels.ReviewLeaveRequest  false  "Note to the student"
+// @Success      200     {object}  viewmodels.LeaveResponse
+// @Failure      400     {object}  viewmodels.ErrorResponse
+// @Failure      404     {object}  viewmodels.ErrorResponse
+// @Failure      409     {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /leave-requests/{id}/reject [post]
+func (ctl *LeaveController) RejectLeave(c *gin.Context) {
+	ctl.review(c, ctl.service.RejectLeave)
+}
+
+// CancelLeave handles POST /leave-requests/:id/cancel
+// @Summary      Cancel a leave request
+// @Description  Withdraws a pending request. Fails with 409 leave_not_pending once decided.
+// @Tags         Leave
+// @Produce      json
+// @Param        id   path      int  true  "Leave request ID"
+// @Success      200  {object}  viewmodels.LeaveResponse
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Failure      409  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /leave-requests/{id}/cancel [post]
+func (ctl *LeaveController) CancelLeave(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	leave, err := ctl.service.CancelLeave(uint(id), 0)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, leave)
+}
+
+// review runs an approval or rejection; the body (a note) is optional
+func (ctl *LeaveController) review(c *gin.Context, decide func(uint, viewmodels.ReviewLeaveRequest, services.Actor) (*viewmodels.LeaveResponse, error)) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	var req viewmodels.ReviewLeaveRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			bindError(c, err)
+			return
+		}
+	}
+
+	leave, err := decide(uint(id), req, middleware.CurrentActor(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, leave)
+}
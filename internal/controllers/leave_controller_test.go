@@ -0,0 +1,179 @@
+package controllers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hrms_backend/internal/controllers"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mock Service ---
+type MockLeaveService struct {
+	mock.Mock
+}
+
+func (m *MockLeaveService) SubmitLeave(studentID uint, req viewmodels.LeaveRequestRequest, actor services.Actor) (*viewmodels.LeaveResponse, error) {
+	args := m.Called(studentID, req, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.LeaveResponse), args.Error(1)
+}
+
+func (m *MockLeaveService) ListLeave(query viewmodels.LeaveQuery) ([]viewmodels.LeaveResponse, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]viewmodels.LeaveResponse), args.Error(1)
+}
+
+func (m *MockLeaveService) GetLeave(id uint) (*viewmodels.LeaveResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.LeaveResponse), args.Error(1)
+}
+
+func (m *MockLeaveService) ApproveLeave(id uint, req viewmodels.ReviewLeaveRequest, actor services.Actor) (*viewmodels.LeaveResponse, error) {
+	args := m.Called(id, req, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.LeaveResponse), args.Error(1)
+}
+
+func (m *MockLeaveService) RejectLeave(id uint, req viewmodels.ReviewLeaveRequest, actor services.Actor) (*viewmodels.LeaveResponse, error) {
+	args := m.Called(id, req, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.LeaveResponse), args.Error(1)
+}
+
+func (m *MockLeaveService) CancelLeave(id, studentID uint) (*viewmodels.LeaveResponse, error) {
+	args := m.Called(id, studentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.LeaveResponse), args.Error(1)
+}
+
+func leaveRouter(service *MockLeaveService, role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := newRouter()
+	controllers.NewLeaveController(service).RegisterRoutes(r.Group("/leave-requests", asRole(role, nil)))
+	return r
+}
+
+func meLeaveRouter(service *MockLeaveService, studentID *uint) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := newRouter()
+	ctl := controllers.NewMeController(new(MockStudentService), new(MockAttendanceService), service)
+	ctl.RegisterRoutes(r.Group("/me", asRole(models.RoleStudent, studentID)))
+	return r
+}
+
+// --- Tests ---
+
+func TestLeaveController(t *testing.T) {
+	mockService := new(MockLeaveService)
+	send := func(role, method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		leaveRouter(mockService, role).ServeHTTP(w, req)
+		return w
+	}
+
+	// Case 1: Pending requests
+	mockService.On("ListLeave", viewmodels.LeaveQuery{Status: "pending"}).
+		Return([]viewmodels.LeaveResponse{{ID: 1, StudentID: 7, Status: "pending"}}, nil).Once()
+	w := send(models.RoleTeacher, "GET", "/leave-requests?status=pending", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Case 2: Unknown status
+	w = send(models.RoleTeacher, "GET", "/leave-requests?status=maybe", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Case 3: Approve with a note
+	mockService.On("ApproveLeave", uint(1), viewmodels.ReviewLeaveRequest{Note: "ok"}, mock.Anything).
+		Return(&viewmodels.LeaveResponse{ID: 1, Status: "approved", ExcusedDays: 3}, nil).Once()
+	w = send(models.RoleTeacher, "POST", "/leave-requests/1/approve", `{"note":"ok"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"excused_days":3`)
+
+	// Case 4: Reject without a body, already decided
+	mockService.On("RejectLeave", uint(1), viewmodels.ReviewLeaveRequest{}, mock.Anything).Return(nil, services.ErrLeaveNotPending).Once()
+	w = send(models.RoleAdmin, "POST", "/leave-requests/1/reject", "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "leave_not_pending")
+
+	// Case 5: Students review nothing
+	w = send(models.RoleStudent, "POST", "/leave-requests/1/approve", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Case 6: Submitted by staff for a student
+	mockService.On("SubmitLeave", uint(7), mock.Anything, mock.Anything).Return(&viewmodels.LeaveResponse{ID: 2, StudentID: 7}, nil).Once()
+	w = send(models.RoleTeacher, "POST", "/leave-requests",
+		`{"student_id":7,"start_date":"2025-12-08T00:00:00Z","end_date":"2025-12-10T00:00:00Z","reason":"Flu"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Case 7: Unknown request
+	mockService.On("CancelLeave", uint(9), uint(0)).Return(nil, services.ErrLeaveNotFound).Once()
+	w = send(models.RoleTeacher, "POST", "/leave-requests/9/cancel", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestMyLeaveController(t *testing.T) {
+	mockService := new(MockLeaveService)
+	sid := uint(7)
+	r := meLeaveRouter(mockService, &sid)
+	start := time.Date(2025, 12, 8, 0, 0, 0, 0, time.UTC)
+
+	// Case 1: Submit for oneself
+	req := viewmodels.LeaveRequestRequest{StartDate: start, EndDate: start, Reason: "Dentist"}
+	mockService.On("SubmitLeave", uint(7), req, mock.Anything).Return(&viewmodels.LeaveResponse{ID: 1, StudentID: 7, Status: "pending"}, nil).Once()
+	httpReq, _ := http.NewRequest("POST", "/me/leave-requests",
+		bytes.NewBufferString(`{"start_date":"2025-12-08T00:00:00Z","end_date":"2025-12-08T00:00:00Z","reason":"Dentist"}`))
+	httpReq.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httpReq)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Case 2: Reason missing
+	httpReq, _ = http.NewRequest("POST", "/me/leave-requests",
+		bytes.NewBufferString(`{"start_date":"2025-12-08T00:00:00Z","end_date":"2025-12-08T00:00:00Z"}`))
+	httpReq.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httpReq)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Case 3: Only own requests are listed and cancelled
+	mockService.On("ListLeave", viewmodels.LeaveQuery{StudentID: 7}).Return([]viewmodels.LeaveResponse{}, nil).Once()
+	httpReq, _ = http.NewRequest("GET", "/me/leave-requests", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httpReq)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mockService.On("CancelLeave", uint(3), uint(7)).Return(&viewmodels.LeaveResponse{ID: 3, Status: "cancelled"}, nil).Once()
+	httpReq, _ = http.NewRequest("POST", "/me/leave-requests/3/cancel", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httpReq)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mockService.AssertExpectations(t)
+}
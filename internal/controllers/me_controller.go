@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTP for the logged-in student's own data. The student is always taken
+// from the access token, never from the request, so no other student is reachable.
+type MeController struct {
+	students   services.StudentService
+	attendance services.AttendanceService
+	leave      services.LeaveService
+}
+
+// Constructor
+func NewMeController(students services.StudentService, attendance services.AttendanceService, leave services.LeaveService) *MeController {
+	return &MeController{students: students, attendance: attendance, leave: leave}
+}
+
+// Register routes under an authenticated router group (e.g., /me); student accounts only
+func (ctl *MeController) RegisterRoutes(rg *gin.RouterGroup) {
+	student := middleware.RequireRoles(models.RoleStudent)
+
+	rg.GET("", student, ctl.GetMe)
+	rg.GET("/attendance", student, ctl.GetMyAttendance)
+	rg.GET("/attendance/summary", student, ctl.GetMyAttendanceSummary)
+	rg.GET("/leave-requests", student, ctl.GetMyLeave)
+	rg.POST("/leave-requests", student, ctl.SubmitMyLeave)
+	rg.POST("/leave-requests/:id/cancel", student, ctl.CancelMyLeave)
+}
+
+var errNotAStudent = &services.Error{Kind: services.ErrForbidden, Code: "not_a_student", Message: "account is not linked to a student"}
+
+// currentStudentID resolves the caller's student, aborting with 403 for accounts without one
+func currentStudentID(c *gin.Context) (uint, bool) {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok || principal.StudentID == nil {
+		_ = c.Error(errNotAStudent)
+		return 0, false
+	}
+	return *principal.StudentID, true
+}
+
+// GetMe handles GET /me
+// @Summary      Get my student record
+// @Description  Retrieves the student record of the logged-in student.
+// @Tags         Me
+// @Produce      json
+// @Success      200  {object}  viewmodels.StudentResponse
+// @Failure      403  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /me [get]
+func (ctl *MeController) GetMe(c *gin.Context) {
+	id, ok := currentStudentID(c)
+	if !ok {
+		return
+	}
+
+	student, err := ctl.students.GetStudentByID(id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, student)
+}
+
+// GetMyAttendance handles GET /me/attendance
+// @Summary      Get my attendance
+// @Description  Retrieves a paginated list of the logged-in student's attendance records, filtered and paged like GET /attendance.
+// @Tags         Me
+// @Produce      json
+// @Param        from        query     string  false  "Earliest date (YYYY-MM-DD, inclusive)"
+// @Param        to          query     string  false  "Latest date (YYYY-MM-DD, inclusive)"
+// @Param        status      query     string  false  "Attendance status"  Enums(present, absent, late, excused)
+// @Param        section_id  query     int     false  "Section ID"
+// @Param        page        query     int     false  "Page number for pagination"  minimum(1)
+// @Param        limit       query     int     false  "Number of items per page"    minimum(1)  maximum(100)
+// @Param        sort        query     string  false  "Sort key, prefix with - for descending (default -date)"  Enums(date, -date, status, -status, created_at, -created_at)
+// @Param        cursor      query     string  false  "next_cursor of the previous page; replaces page"
+// @Success      200         {object}  viewmodels.AttendanceListResponse
+// @Failure      400         {object}  viewmodels.ErrorResponse
+// @Failure      403         {object}  viewmodels.ErrorResponse
+// @Failure      404         {object}  viewmodels.ErrorResponse
+// @Failure      500         {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/attendance [get]
+func (ctl *MeController) GetMyAttendance(c *gin.Context) {
+	id, ok := currentStudentID(c)
+	if !ok {
+		return
+	}
+	var query viewmodels.AttendanceListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	resp, err := ctl.attendance.GetAttendanceByStudentID(id, query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetMyAttendanceSummary handles GET /me/attendance/summary
+// @Summary      Get my attendance summary
+// @Description  Counts the logged-in student's present, absent, late and excused days and the attendance percentage, optionally within a date range.
+// @Tags         Me
+// @Produce      json
+// @Param        from  query     string  false  "First day (YYYY-MM-DD, inclusive)"
+// @Param        to    query     string  false  "Last day (YYYY-MM-DD, inclusive)"
+// @Success      200   {object}  viewmodels.ReportRowResponse
+// @Failure      400   {object}  viewmodels.ErrorResponse
+// @Failure      403   {object}  viewmodels.ErrorResponse
+// @Failure      500   {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/attendance/summary [get]
+func (ctl *MeController) GetMyAttendanceSummary(c *gin.Context) {
+	id, ok := currentStudentID(c)
+	if !ok {
+		return
+	}
+
+	var query viewmodels.AttendanceSummaryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	summary, err := ctl.attendance.GetAttendanceSummaryByStudentID(id, query.From, query.To)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetMyLeave handles GET /me/leave-requests
+// @Summary      Get my leave requests
+// @Description  Retrieves the logged-in student's leave requests, newest first.
+// @Tags         Me
+// @Produce      json
+// @Success      200  {array}   viewmodels.LeaveResponse
+// @Failure      403  {object}  viewmodels.ErrorResponse
+// @Failure      500  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/leave-requests [get]
+func (ctl *MeController) GetMyLeave(c *gin.Context) {
+	id, ok := currentStudentID(c)
+	if !ok {
+		return
+	}
+
+	leaves, err := ctl.leave.ListLeave(viewmodels.LeaveQuery{StudentID: id})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, leaves)
+}
+
+// SubmitMyLeave handles POST /me/leave-requests
+// @Summary      Request leave
+// @Description  Files a pending leave request for the logged-in student. Once a teacher or admin approves it, the school days it covers are marked excused. Fails with 409 leave_overlap if a pending or approved request already covers some of the days.
+// @Tags         Me
+// @Accept       json
+// @Produce      json
+// @Param        leave  body      viewmodels.LeaveRequestRequest  true  "Leave request"
+// @Success      201    {object}  viewmodels.LeaveResponse
+// @Failure      400    {object}  viewmodels.ErrorResponse
+// @Failure      403    {object}  viewmodels.ErrorResponse
+// @Failure      409    {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/leave-requests [post]
+func (ctl *MeController) SubmitMyLeave(c *gin.Context) {
+	id, ok := currentStudentID(c)
+	if !ok {
+		return
+	}
+
+	var req viewmodels.LeaveRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	leave, err := ctl.leave.SubmitLeave(id, req, middleware.CurrentActor(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, leave)
+}
+
+// CancelMyLeave handles POST /me/leave-requests/:id/cancel
+// @Summary      Cancel my leave request
+// @Description  Withdraws one of the logged-in student's requests while it is pending. Fails with 409 leave_not_pending once decided.
+// @Tags         Me
+// @Produce      json
+// @Param        id   path      int  true  "Leave request ID"
+// @Success      200  {object}  viewmodels.LeaveResponse
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      403  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Failure      409  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/leave-requests/{id}/cancel [post]
+func (ctl *MeController) CancelMyLeave(c *gin.Context) {
+	studentID, ok := currentStudentID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	leave, err := ctl.leave.CancelLeave(uint(id), studentID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, leave)
+}
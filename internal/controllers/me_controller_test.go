@@ -0,0 +1,106 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hrms_backend/internal/controllers"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// --- Helper to setup router ---
+func setupMeRouter(students *MockStudentService, attendance *MockAttendanceService, role string, studentID *uint) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	ctl := controllers.NewMeController(students, attendance, new(MockLeaveService))
+	r := newRouter()
+	ctl.RegisterRoutes(r.Group("/me", asRole(role, studentID)))
+	return r
+}
+
+// --- Tests ---
+
+func TestGetMeController(t *testing.T) {
+	mockStudents := new(MockStudentService)
+	mockAttendance := new(MockAttendanceService)
+	sid := uint(7)
+
+	// Case 1: Success
+	r := setupMeRouter(mockStudents, mockAttendance, models.RoleStudent, &sid)
+	mockStudents.On("GetStudentByID", uint(7)).Return(&viewmodels.StudentResponse{ID: 7, Name: "Alice"}, nil).Once()
+	req, _ := http.NewRequest("GET", "/me", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Alice")
+
+	// Case 2: Student account without a linked student
+	r = setupMeRouter(mockStudents, mockAttendance, models.RoleStudent, nil)
+	req, _ = http.NewRequest("GET", "/me", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Case 3: Staff have no /me
+	r = setupMeRouter(mockStudents, mockAttendance, models.RoleTeacher, nil)
+	req, _ = http.NewRequest("GET", "/me", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	mockStudents.AssertExpectations(t)
+}
+
+func TestGetMyAttendanceController(t *testing.T) {
+	mockStudents := new(MockStudentService)
+	mockAttendance := new(MockAttendanceService)
+	sid := uint(7)
+	r := setupMeRouter(mockStudents, mockAttendance, models.RoleStudent, &sid)
+
+	mockAttendance.On("GetAttendanceByStudentID", uint(7), viewmodels.AttendanceListQuery{Status: "absent", Limit: 10}).
+		Return(&viewmodels.AttendanceListResponse{Data: []viewmodels.AttendanceResponse{{ID: 1, StudentID: 7}}, Total: 1, Limit: 10}, nil).Once()
+	req, _ := http.NewRequest("GET", "/me/attendance?status=absent&limit=10", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockAttendance.AssertExpectations(t)
+}
+
+func TestGetMyAttendanceSummaryController(t *testing.T) {
+	mockStudents := new(MockStudentService)
+	mockAttendance := new(MockAttendanceService)
+	sid := uint(7)
+	r := setupMeRouter(mockStudents, mockAttendance, models.RoleStudent, &sid)
+
+	// Case 1: Success with a date range
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	mockAttendance.On("GetAttendanceSummaryByStudentID", uint(7), from, to).
+		Return(&viewmodels.ReportRowResponse{StudentID: 7, Present: 3, Total: 4, Percentage: 75}, nil).Once()
+	req, _ := http.NewRequest("GET", "/me/attendance/summary?from=2024-01-01&to=2024-01-31", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"percentage":75`)
+
+	// Case 2: Without a range every record counts
+	mockAttendance.On("GetAttendanceSummaryByStudentID", uint(7), time.Time{}, time.Time{}).
+		Return(&viewmodels.ReportRowResponse{StudentID: 7}, nil).Once()
+	req, _ = http.NewRequest("GET", "/me/attendance/summary", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Case 3: Invalid date
+	req, _ = http.NewRequest("GET", "/me/attendance/summary?from=01-01-2024", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockAttendance.AssertExpectations(t)
+}
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"fmt"
+	"hrms_backend/internal/export"
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTP for stored attendance reports.
+type ReportController struct {
+	service services.ReportService
+}
+
+// Constructor
+func NewReportController(service services.ReportService) *ReportController {
+	return &ReportController{service: service}
+}
+
+// Register routes under an authenticated router group (e.g., /reports); staff only
+func (ctl *ReportController) RegisterRoutes(rg *gin.RouterGroup) {
+	staff := middleware.RequireRoles(models.RoleAdmin, models.RoleTeacher)
+
+	rg.GET("", staff, ctl.GetAllReports)
+	rg.GET("/attendance", staff, ctl.ExportAttendance)
+	rg.GET("/:id", staff, ctl.GetReportByID)
+}
+
+// GetAllReports handles GET /reports
+// @Summary      List attendance reports
+// @Description  Retrieves a paginated list of generated attendance reports, newest first. Rows are not included.
+// @Tags         Reports
+// @Produce      json
+// @Param        page   query     int  false  "Page number for pagination"  minimum(1)
+// @Param        limit  query     int  false  "Number of items per page"    minimum(1)
+// @Success      200    {array}   viewmodels.ReportResponse
+// @Failure      500    {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /reports [get]
+func (ctl *ReportController) GetAllReports(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	reports, err := ctl.service.GetAllReports(page, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+// ExportAttendance handles GET /reports/attendance
+// @Summary      Export an attendance summary
+// @Description  Downloads a per-student attendance summary (name, department, present, late, absent, excused, percentage) for a date range as CSV, XLSX or PDF.
+// @Tags         Reports
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce      application/pdf
+// @Param        from    query     string  true   "First day (YYYY-MM-DD, inclusive)"
+// @Param        to      query     string  true   "Last day (YYYY-MM-DD, inclusive)"
+// @Param        format  query     string  false  "File format (default csv)"  Enums(csv, xlsx, pdf)
+// @Success      200     {file}    file
+// @Failure      400     {object}  viewmodels.ErrorResponse
+// @Failure      500     {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /reports/attendance [get]
+func (ctl *ReportController) ExportAttendance(c *gin.Context) {
+	var query viewmodels.AttendanceExportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		bindError(c, err)
+		return
+	}
+	if query.To.Before(query.From) {
+		_ = c.Error(services.ValidationError("to", "must not be before from"))
+		return
+	}
+	if query.Format == "" {
+		query.Format = "csv"
+	}
+
+	rows, err := ctl.service.GetAttendanceSummary(query.From, query.To)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	from, to := query.From.Format("2006-01-02"), query.To.Format("2006-01-02")
+	format := export.Formats[query.Format]
+	filename := fmt.Sprintf("attendance_%s_%s.%s", from, to, format.Extension)
+
+	c.Header("Content-Type", format.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+	// headers are sent at this point, a failure can only be logged
+	if err := export.Write(c.Writer, query.Format, fmt.Sprintf("Attendance %s to %s", from, to), rows); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// GetReportByID handles GET /reports/:id
+// @Summary      Get an attendance report
+// @Description  Retrieves a generated attendance report with the per-student rows.
+// @Tags         Reports
+// @Produce      json
+// @Param        id   path      int  true  "Report ID"
+// @Success      200  {object}  viewmodels.ReportResponse
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Failure      500  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /reports/{id} [get]
+func (ctl *ReportController) GetReportByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	report, err := ctl.service.GetReportByID(uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
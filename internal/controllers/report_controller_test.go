@@ -0,0 +1,177 @@
+package controllers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hrms_backend/internal/controllers"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mock Service ---
+type MockReportService struct {
+	mock.Mock
+}
+
+func (m *MockReportService) GenerateWeeklyReport() (*viewmodels.ReportResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.ReportResponse), args.Error(1)
+}
+
+func (m *MockReportService) GenerateMonthlyReport() (*viewmodels.ReportResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.ReportResponse), args.Error(1)
+}
+
+func (m *MockReportService) GetAttendanceSummary(from, to time.Time) ([]viewmodels.ReportRowResponse, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]viewmodels.ReportRowResponse), args.Error(1)
+}
+
+func (m *MockReportService) GetAllReports(page, limit int) ([]viewmodels.ReportResponse, error) {
+	args := m.Called(page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]viewmodels.ReportResponse), args.Error(1)
+}
+
+func (m *MockReportService) GetReportByID(id uint) (*viewmodels.ReportResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.ReportResponse), args.Error(1)
+}
+
+// --- Helper to setup router ---
+func setupReportRouter(service *MockReportService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	ctl := controllers.NewReportController(service)
+	r := newRouter()
+	ctl.RegisterRoutes(r.Group("/reports", asRole(models.RoleTeacher, nil)))
+	return r
+}
+
+// --- Tests ---
+
+func TestGetAllReportsController(t *testing.T) {
+	mockService := new(MockReportService)
+	r := setupReportRouter(mockService)
+
+	// Case 1: Success
+	mockService.On("GetAllReports", 1, 10).Return([]viewmodels.ReportResponse{{ID: 1, Type: "weekly"}}, nil).Once()
+	req, _ := http.NewRequest("GET", "/reports", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "weekly")
+
+	// Case 2: Service Error
+	mockService.On("GetAllReports", 2, 5).Return(nil, errors.New("db error")).Once()
+	req, _ = http.NewRequest("GET", "/reports?page=2&limit=5", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestGetReportByIDController(t *testing.T) {
+	mockService := new(MockReportService)
+	r := setupReportRouter(mockService)
+
+	// Case 1: Success
+	expected := &viewmodels.ReportResponse{ID: 1, Rows: []viewmodels.ReportRowResponse{{StudentName: "Alice", Percentage: 80}}}
+	mockService.On("GetReportByID", uint(1)).Return(expected, nil).Once()
+	req, _ := http.NewRequest("GET", "/reports/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Alice")
+
+	// Case 2: Not Found
+	mockService.On("GetReportByID", uint(99)).Return(nil, services.ErrReportNotFound).Once()
+	req, _ = http.NewRequest("GET", "/reports/99", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Case 3: Invalid ID
+	req, _ = http.NewRequest("GET", "/reports/abc", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestExportAttendanceController(t *testing.T) {
+	mockService := new(MockReportService)
+	r := setupReportRouter(mockService)
+
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC)
+	rows := []viewmodels.ReportRowResponse{{StudentName: "Alice", Department: "IT", Present: 4, Absent: 1, Total: 5, Percentage: 80}}
+
+	// Case 1: CSV by default
+	mockService.On("GetAttendanceSummary", from, to).Return(rows, nil).Once()
+	req, _ := http.NewRequest("GET", "/reports/attendance?from=2025-12-01&to=2025-12-07", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="attendance_2025-12-01_2025-12-07.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Contains(t, w.Body.String(), "Alice,IT,4,0,0,1,0,80.00")
+
+	// Case 2: XLSX and PDF
+	for format, contentType := range map[string]string{
+		"xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		"pdf":  "application/pdf",
+	} {
+		mockService.On("GetAttendanceSummary", from, to).Return(rows, nil).Once()
+		req, _ = http.NewRequest("GET", "/reports/attendance?from=2025-12-01&to=2025-12-07&format="+format, nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, contentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "."+format)
+		assert.NotZero(t, w.Body.Len())
+	}
+
+	// Case 3: Invalid queries
+	for _, q := range []string{"to=2025-12-07", "from=2025-12-01&to=2025-12-07&format=docx", "from=2025-12-07&to=2025-12-01"} {
+		req, _ = http.NewRequest("GET", "/reports/attendance?"+q, nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, q)
+	}
+
+	// Case 4: Service Error
+	mockService.On("GetAttendanceSummary", from, to).Return(nil, errors.New("db error")).Once()
+	req, _ = http.NewRequest("GET", "/reports/attendance?from=2025-12-01&to=2025-12-07", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,213 @@
+package controllers
+
+import (
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTP for class sections and their enrollments. Sections are created under their course,
+// see CourseController.
+type SectionController struct {
+	service services.CourseService
+}
+
+// Constructor
+func NewSectionController(service services.CourseService) *SectionController {
+	return &SectionController{service: service}
+}
+
+// Register routes under an authenticated router group (e.g., /sections).
+// Staff can read, only admins can change sections and enrollments.
+func (ctl *SectionController) RegisterRoutes(rg *gin.RouterGroup) {
+	staff := middleware.RequireRoles(models.RoleAdmin, models.RoleTeacher)
+	admin := middleware.RequireRoles(models.RoleAdmin)
+
+	rg.GET("/:id", staff, ctl.GetSectionByID)
+	rg.PUT("/:id", admin, ctl.UpdateSection)
+	rg.DELETE("/:id", admin, ctl.DeleteSection)
+	rg.GET("/:id/enrollments", staff, ctl.ListEnrolledStudents)
+	rg.POST("/:id/enrollments", admin, ctl.EnrollStudents)
+	rg.DELETE("/:id/enrollments/:student_id", admin, ctl.UnenrollStudent)
+}
+
+// GetSectionByID handles GET /sections/:id
+// @Summary      Get a section
+// @Tags         Sections
+// @Produce      json
+// @Param        id   path      int  true  "Section ID"
+// @Success      200  {object}  viewmodels.SectionResponse
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /sections/{id} [get]
+func (ctl *SectionController) GetSectionByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	section, err := ctl.service.GetSectionByID(uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, section)
+}
+
+// UpdateSection handles PUT /sections/:id
+// @Summary      Rename a section
+// @Tags         Sections
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                        true  "Section ID"
+// @Param        section  body      viewmodels.SectionRequest  true  "Section"
+// @Success      200      {object}  viewmodels.SectionResponse
+// @Failure      400      {object}  viewmodels.ErrorResponse
+// @Failure      404      {object}  viewmodels.ErrorResponse
+// @Failure      409      {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /sections/{id} [put]
+func (ctl *SectionController) UpdateSection(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	var req viewmodels.SectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	section, err := ctl.service.UpdateSection(uint(id), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, section)
+}
+
+// DeleteSection handles DELETE /sections/:id
+// @Summary      Delete a section
+// @Description  Deletes a section and its enrollments. Returns 409 once attendance was recorded in it.
+// @Tags         Sections
+// @Produce      json
+// @Param        id   path  int  true  "Section ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Failure      409  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /sections/{id} [delete]
+func (ctl *SectionController) DeleteSection(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	if err := ctl.service.DeleteSection(uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListEnrolledStudents handles GET /sections/:id/enrollments
+// @Summary      List the students of a section
+// @Description  Retrieves the students enrolled in a section, ordered by name.
+// @Tags         Sections
+// @Produce      json
+// @Param        id   path      int  true  "Section ID"
+// @Success      200  {array}   viewmodels.StudentResponse
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /sections/{id}/enrollments [get]
+func (ctl *SectionController) ListEnrolledStudents(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	students, err := ctl.service.ListEnrolledStudents(uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, students)
+}
+
+// EnrollStudents handles POST /sections/:id/enrollments
+// @Summary      Enroll students in a section
+// @Description  Enrolls the students, leaving those already enrolled as they are. Fails as a whole if a student does not exist.
+// @Tags         Sections
+// @Accept       json
+// @Produce      json
+// @Param        id           path      int                               true  "Section ID"
+// @Param        enrollments  body      viewmodels.EnrollStudentsRequest  true  "Students to enroll"
+// @Success      200          {object}  viewmodels.EnrollStudentsResponse
+// @Failure      400          {object}  viewmodels.ErrorResponse
+// @Failure      404          {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /sections/{id}/enrollments [post]
+func (ctl *SectionController) EnrollStudents(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	var req viewmodels.EnrollStudentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	resp, err := ctl.service.EnrollStudents(uint(id), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// UnenrollStudent handles DELETE /sections/:id/enrollments/:student_id
+// @Summary      Remove a student from a section
+// @Description  Unenrolls the student. Attendance already recorded in the section is kept.
+// @Tags         Sections
+// @Produce      json
+// @Param        id          path  int  true  "Section ID"
+// @Param        student_id  path  int  true  "Student ID"
+// @Success      204         "No Content"
+// @Failure      400         {object}  viewmodels.ErrorResponse
+// @Failure      404         {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /sections/{id}/enrollments/{student_id} [delete]
+func (ctl *SectionController) UnenrollStudent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+	studentID, err := strconv.ParseUint(c.Param("student_id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "student_id")
+		return
+	}
+
+	if err := ctl.service.UnenrollStudent(uint(id), uint(studentID)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -1,12 +1,18 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/models"
 	"hrms_backend/internal/services"
 	"hrms_backend/internal/viewmodels"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 // HTTP for students.
@@ -19,13 +25,19 @@ func NewStudentController(svc services.StudentService) *StudentController {
 	return &StudentController{service: svc}
 }
 
-// Register routes under a router group (e.g., /students)
+// Register routes under a router group (e.g., /students).
+// The group must be authenticated; each route checks the caller's role.
 func (ctl *StudentController) RegisterRoutes(rg *gin.RouterGroup) {
-	rg.POST("", ctl.CreateStudent)
-	rg.GET("", ctl.GetAllStudents)
-	rg.GET("/:id", ctl.GetStudentByID)
-	rg.PUT("/:id", ctl.UpdateStudent)
-	rg.DELETE("/:id", ctl.DeleteStudent)
+	staff := middleware.RequireRoles(models.RoleAdmin, models.RoleTeacher)
+	admin := middleware.RequireRoles(models.RoleAdmin)
+
+	rg.POST("", admin, ctl.CreateStudent)
+	rg.POST("/import", admin, ctl.ImportStudents)
+	rg.GET("", staff, ctl.ListStudents)
+	rg.GET("/:id", middleware.RequireRolesOrOwnStudent("id", models.RoleAdmin, models.RoleTeacher), ctl.GetStudentByID)
+	rg.PUT("/:id", admin, ctl.UpdateStudent)
+	rg.DELETE("/:id", admin, ctl.DeleteStudent)
+	rg.POST("/:id/restore", admin, ctl.RestoreStudent)
 }
 
 // CreateStudent handles POST /students
@@ -37,45 +49,120 @@ func (ctl *StudentController) RegisterRoutes(rg *gin.RouterGroup) {
 // @Param        student  body      viewmodels.CreateStudentRequest  true  "Student details"
 // @Success      201      {object}  viewmodels.StudentResponse
 // @Failure      400      {object}  viewmodels.ErrorResponse
+// @Failure      409      {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
 // @Router       /students [post]
 func (ctl *StudentController) CreateStudent(c *gin.Context) {
 	var req viewmodels.CreateStudentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(c, err)
 		return
 	}
 
-	resp, err := ctl.service.CreateStudent(req)
+	resp, err := ctl.service.CreateStudent(req, middleware.CurrentActor(c))
 	if err != nil {
-		// service returned an error (e.g., DB error, validation error)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, resp)
 }
 
-// GetAllStudents handles GET /students
-// @Summary      Get all students
-// @Description  Retrieves a paginated list of all students.
+// largest accepted import file
+const maxImportBytes = 5 << 20
+
+// ImportStudents handles POST /students/import
+// @Summary      Import students from CSV
+// @Description  Creates students from a CSV file whose header row names the name, email and department columns.
+// @Description  Departments are given by name (case insensitive) and must already exist.
+// @Description  The file is sent as the "file" field of a multipart form, or as the raw request body (Content-Type: text/csv).
+// @Description  Every row is validated and checked for emails repeated in the file or already in use. With dry_run=true nothing is
+// @Description  stored; otherwise all valid rows are inserted in one transaction. Invalid rows are reported per line and skipped.
+// @Tags         Students
+// @Accept       mpfd
+// @Accept       plain
+// @Produce      json
+// @Param        file     formData  file  false  "CSV file"
+// @Param        dry_run  query     bool  false  "Only validate"
+// @Success      200      {object}  viewmodels.StudentImportResponse
+// @Failure      400      {object}  viewmodels.ErrorResponse
+// @Failure      409      {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /students/import [post]
+func (ctl *StudentController) ImportStudents(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+
+	var file io.Reader = c.Request.Body
+	if c.ContentType() == binding.MIMEMultipartPOSTForm {
+		header, err := c.FormFile("file")
+		if err != nil {
+			_ = c.Error(importFileError(err))
+			return
+		}
+		f, err := header.Open()
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		defer f.Close()
+		file = f
+	}
+
+	report, err := ctl.service.ImportStudents(file, dryRun, middleware.CurrentActor(c))
+	if err != nil {
+		_ = c.Error(importFileError(err))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// importFileError turns a missing or oversized upload into a validation error
+func importFileError(err error) error {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return services.ValidationError("file", fmt.Sprintf("must be at most %d MB", maxImportBytes>>20))
+	case errors.Is(err, http.ErrMissingFile):
+		return services.ValidationError("file", "is required")
+	}
+	return err
+}
+
+// ListStudents handles GET /students
+// @Summary      List students
+// @Description  Retrieves a paginated list of students filtered by name/email, department and creation date.
+// @Description  Soft deleted students are left out unless include_deleted is set.
+// @Description  Follow next_cursor for pages that stay consistent while students are added or removed.
 // @Tags         Students
 // @Produce      json
-// @Param        page   query     int  false  "Page number for pagination"  minimum(1)
-// @Param        limit  query     int  false  "Number of items per page"    minimum(1)
-// @Success      200    {array}   viewmodels.StudentResponse
-// @Failure      500    {object}  viewmodels.ErrorResponse
+// @Param        q                query     string  false  "Substring of the name or email"
+// @Param        department_id    query     int     false  "Department ID"
+// @Param        created_from     query     string  false  "Created on or after (YYYY-MM-DD)"
+// @Param        created_to       query     string  false  "Created on or before (YYYY-MM-DD)"
+// @Param        include_deleted  query     bool    false  "Also list soft deleted students"
+// @Param        page             query     int     false  "Page number for pagination"  minimum(1)
+// @Param        limit            query     int     false  "Number of items per page"    minimum(1)  maximum(100)
+// @Param        sort             query     string  false  "Sort key, prefix with - for descending (default id)"  Enums(id, -id, name, -name, email, -email, department_id, -department_id, created_at, -created_at)
+// @Param        cursor           query     string  false  "next_cursor of the previous page; replaces page"
+// @Success      200              {object}  viewmodels.StudentListResponse
+// @Failure      400              {object}  viewmodels.ErrorResponse
+// @Failure      500              {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
 // @Router       /students [get]
-func (ctl *StudentController) GetAllStudents(c *gin.Context) {
-	// Parse Query Params with defaults
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+func (ctl *StudentController) ListStudents(c *gin.Context) {
+	var query viewmodels.StudentListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		bindError(c, err)
+		return
+	}
 
-	responses, err := ctl.service.GetAllStudents(page, limit)
+	resp, err := ctl.service.ListStudents(query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, responses)
+	c.JSON(http.StatusOK, resp)
 }
 
 // GetStudentByID handles GET /students/:id
@@ -87,19 +174,19 @@ func (ctl *StudentController) GetAllStudents(c *gin.Context) {
 // @Success      200  {object}  viewmodels.StudentResponse
 // @Failure      400  {object}  viewmodels.ErrorResponse
 // @Failure      404  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
 // @Router       /students/{id} [get]
 func (ctl *StudentController) GetStudentByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		invalidParam(c, "id")
 		return
 	}
 
 	student, err := ctl.service.GetStudentByID(uint(id))
 	if err != nil {
-		// Distinguish not-found vs other errors in service if desired.
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -116,26 +203,28 @@ func (ctl *StudentController) GetStudentByID(c *gin.Context) {
 // @Param        student  body      viewmodels.UpdateStudentRequest  true  "Updated student details"
 // @Success      200      {object}  viewmodels.StudentResponse
 // @Failure      400      {object}  viewmodels.ErrorResponse
+// @Failure      404      {object}  viewmodels.ErrorResponse
+// @Failure      409      {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
 // @Router       /students/{id} [put]
 func (ctl *StudentController) UpdateStudent(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		invalidParam(c, "id")
 		return
 	}
 
 	var req viewmodels.UpdateStudentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		bindError(c, err)
 		return
 	}
 
 	// Call service to update. Service should return updated DTO or error.
-	updated, err := ctl.service.UpdateStudent(uint(id), req)
+	updated, err := ctl.service.UpdateStudent(uint(id), req, middleware.CurrentActor(c))
 	if err != nil {
-		// service may return not-found or validation/db error
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -144,27 +233,62 @@ func (ctl *StudentController) UpdateStudent(c *gin.Context) {
 
 // DeleteStudent handles DELETE /students/:id
 // @Summary      Delete a student
-// @Description  Deletes a student from the database by their ID.
+// @Description  Soft deletes a student by their ID; it can be restored later. With hard=true the student, its attendance and correction history are removed permanently, even if already soft deleted.
 // @Tags         Students
 // @Produce      json
-// @Param        id  path  int  true  "Student ID"
+// @Param        id    path   int   true   "Student ID"
+// @Param        hard  query  bool  false  "Delete permanently"
 // @Success      204 "No Content"
 // @Failure      400 {object} viewmodels.ErrorResponse
+// @Failure      404 {object} viewmodels.ErrorResponse
+// @Security     BearerAuth
 // @Router       /students/{id} [delete]
 func (ctl *StudentController) DeleteStudent(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		invalidParam(c, "id")
 		return
 	}
 
-	if err := ctl.service.DeleteStudent(uint(id)); err != nil {
-		// service error (not found, DB error, etc.)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	hard, _ := strconv.ParseBool(c.Query("hard"))
+	if hard {
+		err = ctl.service.PurgeStudent(uint(id), middleware.CurrentActor(c))
+	} else {
+		err = ctl.service.DeleteStudent(uint(id), middleware.CurrentActor(c))
+	}
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
 	// 204 No Content is common for successful delete with no body
 	c.Status(http.StatusNoContent)
 }
+
+// RestoreStudent handles POST /students/:id/restore
+// @Summary      Restore a deleted student
+// @Description  Undoes the soft delete of a student. Fails with 409 if the student is not deleted or another student now uses the same email.
+// @Tags         Students
+// @Produce      json
+// @Param        id   path      int  true  "Student ID"
+// @Success      200  {object}  viewmodels.StudentResponse
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Failure      409  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /students/{id}/restore [post]
+func (ctl *StudentController) RestoreStudent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	student, err := ctl.service.RestoreStudent(uint(id), middleware.CurrentActor(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, student)
+}
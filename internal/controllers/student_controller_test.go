@@ -3,11 +3,16 @@ package controllers_test
 import (
 	"bytes"
 	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"hrms_backend/internal/controllers"
+	"hrms_backend/internal/services"
 	"hrms_backend/internal/viewmodels"
 
 	"github.com/gin-gonic/gin"
@@ -20,20 +25,20 @@ type MockStudentService struct {
 	mock.Mock
 }
 
-func (m *MockStudentService) CreateStudent(req viewmodels.CreateStudentRequest) (*viewmodels.StudentResponse, error) {
-	args := m.Called(req)
+func (m *MockStudentService) CreateStudent(req viewmodels.CreateStudentRequest, actor services.Actor) (*viewmodels.StudentResponse, error) {
+	args := m.Called(req, actor)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*viewmodels.StudentResponse), args.Error(1)
 }
 
-func (m *MockStudentService) GetAllStudents(page, limit int) ([]viewmodels.StudentResponse, error) {
-	args := m.Called(page, limit)
+func (m *MockStudentService) ListStudents(query viewmodels.StudentListQuery) (*viewmodels.StudentListResponse, error) {
+	args := m.Called(query)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]viewmodels.StudentResponse), args.Error(1)
+	return args.Get(0).(*viewmodels.StudentListResponse), args.Error(1)
 }
 
 func (m *MockStudentService) GetStudentByID(id uint) (*viewmodels.StudentResponse, error) {
@@ -44,16 +49,38 @@ func (m *MockStudentService) GetStudentByID(id uint) (*viewmodels.StudentRespons
 	return args.Get(0).(*viewmodels.StudentResponse), args.Error(1)
 }
 
-func (m *MockStudentService) UpdateStudent(id uint, req viewmodels.UpdateStudentRequest) (*viewmodels.StudentResponse, error) {
-	args := m.Called(id, req)
+func (m *MockStudentService) UpdateStudent(id uint, req viewmodels.UpdateStudentRequest, actor services.Actor) (*viewmodels.StudentResponse, error) {
+	args := m.Called(id, req, actor)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*viewmodels.StudentResponse), args.Error(1)
 }
 
-func (m *MockStudentService) DeleteStudent(id uint) error {
-	args := m.Called(id)
+func (m *MockStudentService) DeleteStudent(id uint, actor services.Actor) error {
+	args := m.Called(id, actor)
+	return args.Error(0)
+}
+
+func (m *MockStudentService) RestoreStudent(id uint, actor services.Actor) (*viewmodels.StudentResponse, error) {
+	args := m.Called(id, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.StudentResponse), args.Error(1)
+}
+
+func (m *MockStudentService) ImportStudents(file io.Reader, dryRun bool, actor services.Actor) (*viewmodels.StudentImportResponse, error) {
+	content, _ := io.ReadAll(file)
+	args := m.Called(string(content), dryRun, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.StudentImportResponse), args.Error(1)
+}
+
+func (m *MockStudentService) PurgeStudent(id uint, actor services.Actor) error {
+	args := m.Called(id, actor)
 	return args.Error(0)
 }
 
@@ -61,13 +88,15 @@ func (m *MockStudentService) DeleteStudent(id uint) error {
 func setupRouter(service *MockStudentService) (*controllers.StudentController, *gin.Engine) {
 	gin.SetMode(gin.TestMode)
 	ctl := controllers.NewStudentController(service)
-	r := gin.Default()
+	r := newRouter()
 	// Register routes manually for testing
 	r.POST("/students", ctl.CreateStudent)
-	r.GET("/students", ctl.GetAllStudents)
+	r.POST("/students/import", ctl.ImportStudents)
+	r.GET("/students", ctl.ListStudents)
 	r.GET("/students/:id", ctl.GetStudentByID)
 	r.PUT("/students/:id", ctl.UpdateStudent)
 	r.DELETE("/students/:id", ctl.DeleteStudent)
+	r.POST("/students/:id/restore", ctl.RestoreStudent)
 	return ctl, r
 }
 
@@ -79,9 +108,9 @@ func TestCreateStudentController(t *testing.T) {
 
 	// Case 1: Success
 	expected := &viewmodels.StudentResponse{ID: 1, Name: "Alice"}
-	mockService.On("CreateStudent", mock.Anything).Return(expected, nil).Once()
+	mockService.On("CreateStudent", mock.Anything, mock.Anything).Return(expected, nil).Once()
 
-	reqBody := []byte(`{"name":"Alice","email":"a@a.com","department":"IT"}`)
+	reqBody := []byte(`{"name":"Alice","email":"a@a.com","department_id":2}`)
 	req, _ := http.NewRequest("POST", "/students", bytes.NewBuffer(reqBody))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
@@ -99,20 +128,37 @@ func TestCreateStudentController(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
-func TestGetAllStudentsController(t *testing.T) {
+func TestListStudentsController(t *testing.T) {
 	mockService := new(MockStudentService)
 	_, r := setupRouter(mockService)
 
-	// Case 1: Success
-	expected := []viewmodels.StudentResponse{{ID: 1, Name: "Alice"}}
-	mockService.On("GetAllStudents", 1, 10).Return(expected, nil).Once()
-
-	req, _ := http.NewRequest("GET", "/students?page=1&limit=10", nil)
+	// Case 1: Success, query parameters are bound
+	expected := &viewmodels.StudentListResponse{Data: []viewmodels.StudentResponse{{ID: 1, Name: "Alice"}}, Total: 1, Page: 1, Limit: 10}
+	mockService.On("ListStudents", viewmodels.StudentListQuery{
+		Q:              "ali",
+		DepartmentID:   3,
+		CreatedFrom:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		IncludeDeleted: true,
+		Page:           1,
+		Limit:          10,
+		Sort:           "-name",
+	}).Return(expected, nil).Once()
+
+	req, _ := http.NewRequest("GET", "/students?q=ali&department_id=3&created_from=2024-01-01&include_deleted=true&page=1&limit=10&sort=-name", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Contains(t, w.Body.String(), "Alice")
+	assert.Contains(t, w.Body.String(), `"next":null`)
+
+	// Case 2: Unknown sort key
+	req, _ = http.NewRequest("GET", "/students?sort=password", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
 }
 
 func TestGetStudentByIDController(t *testing.T) {
@@ -130,7 +176,7 @@ func TestGetStudentByIDController(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 
 	// Case 2: Not Found
-	mockService.On("GetStudentByID", uint(99)).Return(nil, errors.New("not found")).Once()
+	mockService.On("GetStudentByID", uint(99)).Return(nil, services.ErrStudentNotFound).Once()
 	req, _ = http.NewRequest("GET", "/students/99", nil)
 	w = httptest.NewRecorder()
 	r.ServeHTTP(w, req)
@@ -150,7 +196,7 @@ func TestUpdateStudentController(t *testing.T) {
 
 	// Case 1: Success
 	expected := &viewmodels.StudentResponse{ID: 1, Name: "Updated"}
-	mockService.On("UpdateStudent", uint(1), mock.Anything).Return(expected, nil).Once()
+	mockService.On("UpdateStudent", uint(1), mock.Anything, mock.Anything).Return(expected, nil).Once()
 
 	reqBody := []byte(`{"name":"Updated"}`)
 	req, _ := http.NewRequest("PUT", "/students/1", bytes.NewBuffer(reqBody))
@@ -172,18 +218,119 @@ func TestDeleteStudentController(t *testing.T) {
 	_, r := setupRouter(mockService)
 
 	// Case 1: Success
-	mockService.On("DeleteStudent", uint(1)).Return(nil).Once()
+	mockService.On("DeleteStudent", uint(1), mock.Anything).Return(nil).Once()
 	req, _ := http.NewRequest("DELETE", "/students/1", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusNoContent, w.Code)
 
-	// Case 2: Service Error
-	mockService.On("DeleteStudent", uint(99)).Return(errors.New("failed")).Once()
+	// Case 2: Not Found
+	mockService.On("DeleteStudent", uint(99), mock.Anything).Return(services.ErrStudentNotFound).Once()
 	req, _ = http.NewRequest("DELETE", "/students/99", nil)
 	w = httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Case 3: Unexpected error is a 500 that does not leak details
+	mockService.On("DeleteStudent", uint(98), mock.Anything).Return(errors.New("connection refused")).Once()
+	req, _ = http.NewRequest("DELETE", "/students/98", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "connection refused")
+
+	// Case 4: Permanent delete
+	mockService.On("PurgeStudent", uint(1), mock.Anything).Return(nil).Once()
+	req, _ = http.NewRequest("DELETE", "/students/1?hard=true", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRestoreStudentController(t *testing.T) {
+	mockService := new(MockStudentService)
+	_, r := setupRouter(mockService)
+
+	// Case 1: Success
+	mockService.On("RestoreStudent", uint(1), mock.Anything).Return(&viewmodels.StudentResponse{ID: 1, Name: "Alice"}, nil).Once()
+	req, _ := http.NewRequest("POST", "/students/1/restore", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Alice")
+
+	// Case 2: Not deleted
+	mockService.On("RestoreStudent", uint(2), mock.Anything).Return(nil, services.ErrStudentNotDeleted).Once()
+	req, _ = http.NewRequest("POST", "/students/2/restore", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestImportStudentsController(t *testing.T) {
+	mockService := new(MockStudentService)
+	_, r := setupRouter(mockService)
+	file := "name,email,department\nAlice,a@a.com,CS\n"
+	report := &viewmodels.StudentImportResponse{DryRun: true, Total: 1, Valid: 1}
+
+	// Case 1: Raw CSV body, dry run
+	mockService.On("ImportStudents", file, true, mock.Anything).Return(report, nil).Once()
+	req, _ := http.NewRequest("POST", "/students/import?dry_run=true", bytes.NewBufferString(file))
+	req.Header.Set("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"valid":1`)
+
+	// Case 2: Multipart upload
+	mockService.On("ImportStudents", file, false, mock.Anything).Return(&viewmodels.StudentImportResponse{Total: 1, Valid: 1, Created: 1}, nil).Once()
+	body := new(bytes.Buffer)
+	form := multipart.NewWriter(body)
+	part, _ := form.CreateFormFile("file", "students.csv")
+	_, _ = part.Write([]byte(file))
+	_ = form.Close()
+	req, _ = http.NewRequest("POST", "/students/import", body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"created":1`)
+
+	// Case 3: Multipart form without the file
+	body = new(bytes.Buffer)
+	form = multipart.NewWriter(body)
+	_ = form.WriteField("other", "x")
+	_ = form.Close()
+	req, _ = http.NewRequest("POST", "/students/import", body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"file"`)
+
+	// Case 4: File too large
+	big := strings.Repeat("x", 6<<20)
+	body = new(bytes.Buffer)
+	form = multipart.NewWriter(body)
+	part, _ = form.CreateFormFile("file", "students.csv")
+	_, _ = part.Write([]byte(big))
+	_ = form.Close()
+	req, _ = http.NewRequest("POST", "/students/import", body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
 	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "at most 5 MB")
+	mockService.AssertExpectations(t)
 }
@@ -0,0 +1,244 @@
+package controllers
+
+import (
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTP for the weekly timetable and the class sessions generated from it.
+type TimetableController struct {
+	service services.TimetableService
+}
+
+// Constructor
+func NewTimetableController(service services.TimetableService) *TimetableController {
+	return &TimetableController{service: service}
+}
+
+// Register routes under an authenticated router group (e.g., /timetable).
+// Staff can read, only admins can change the timetable.
+func (ctl *TimetableController) RegisterRoutes(rg *gin.RouterGroup) {
+	staff := middleware.RequireRoles(models.RoleAdmin, models.RoleTeacher)
+	admin := middleware.RequireRoles(models.RoleAdmin)
+
+	rg.GET("/slots", staff, ctl.ListSlots)
+	rg.POST("/slots", admin, ctl.CreateSlot)
+	rg.PUT("/slots/:id", admin, ctl.UpdateSlot)
+	rg.DELETE("/slots/:id", admin, ctl.DeleteSlot)
+	rg.GET("/sessions", staff, ctl.ListSessions)
+	rg.POST("/sessions/generate", admin, ctl.GenerateSessions)
+	rg.PUT("/sessions/:id", admin, ctl.UpdateSession)
+}
+
+// ListSlots handles GET /timetable/slots
+// @Summary      List timetable slots
+// @Description  Retrieves the weekly slots of a section, or of every section, ordered by weekday and start time.
+// @Tags         Timetable
+// @Produce      json
+// @Param        section_id  query     int  false  "Section ID"
+// @Success      200         {array}   viewmodels.TimetableSlotResponse
+// @Failure      400         {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /timetable/slots [get]
+func (ctl *TimetableController) ListSlots(c *gin.Context) {
+	var sectionID uint64
+	if raw := c.Query("section_id"); raw != "" {
+		var err error
+		if sectionID, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			invalidParam(c, "section_id")
+			return
+		}
+	}
+
+	slots, err := ctl.service.ListSlots(uint(sectionID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, slots)
+}
+
+// CreateSlot handles POST /timetable/slots
+// @Summary      Create a timetable slot
+// @Description  Adds a weekly recurring class to a section and generates its sessions for the next two weeks.
+// @Tags         Timetable
+// @Accept       json
+// @Produce      json
+// @Param        slot  body      viewmodels.CreateTimetableSlotRequest  true  "Slot"
+// @Success      201   {object}  viewmodels.TimetableSlotResponse
+// @Failure      400   {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /timetable/slots [post]
+func (ctl *TimetableController) CreateSlot(c *gin.Context) {
+	var req viewmodels.CreateTimetableSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	slot, err := ctl.service.CreateSlot(req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, slot)
+}
+
+// UpdateSlot handles PUT /timetable/slots/:id
+// @Summary      Change a timetable slot
+// @Description  Changes the schedule of a slot. Its sessions from today on are generated again, except cancelled ones; past sessions are kept.
+// @Tags         Timetable
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                              true  "Slot ID"
+// @Param        slot  body      viewmodels.TimetableSlotRequest  true  "Schedule"
+// @Success      200   {object}  viewmodels.TimetableSlotResponse
+// @Failure      400   {object}  viewmodels.ErrorResponse
+// @Failure      404   {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /timetable/slots/{id} [put]
+func (ctl *TimetableController) UpdateSlot(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	var req viewmodels.TimetableSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	slot, err := ctl.service.UpdateSlot(uint(id), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, slot)
+}
+
+// DeleteSlot handles DELETE /timetable/slots/:id
+// @Summary      Delete a timetable slot
+// @Description  Deletes a slot and its sessions from today on. Past sessions are kept.
+// @Tags         Timetable
+// @Produce      json
+// @Param        id   path  int  true  "Slot ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /timetable/slots/{id} [delete]
+func (ctl *TimetableController) DeleteSlot(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	if err := ctl.service.DeleteSlot(uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListSessions handles GET /timetable/sessions
+// @Summary      List class sessions
+// @Description  Retrieves the class sessions of a date range in chronological order.
+// @Tags         Timetable
+// @Produce      json
+// @Param        from               query     string  true   "First day (YYYY-MM-DD, inclusive)"
+// @Param        to                 query     string  true   "Last day (YYYY-MM-DD, inclusive)"
+// @Param        section_id         query     int     false  "Section ID"
+// @Param        teacher_id         query     int     false  "Teacher user ID"
+// @Param        include_cancelled  query     bool    false  "Include cancelled sessions"
+// @Success      200                {array}   viewmodels.ClassSessionResponse
+// @Failure      400                {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /timetable/sessions [get]
+func (ctl *TimetableController) ListSessions(c *gin.Context) {
+	var query viewmodels.ClassSessionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		bindError(c, err)
+		return
+	}
+	if query.To.Before(query.From) {
+		_ = c.Error(services.ValidationError("to", "must not be before from"))
+		return
+	}
+
+	sessions, err := ctl.service.ListSessions(query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// GenerateSessions handles POST /timetable/sessions/generate
+// @Summary      Generate class sessions
+// @Description  Creates the sessions of every slot within a date range (less than a year). Existing sessions, cancelled ones included, are left alone.
+// @Description  A daily job already generates the next two weeks.
+// @Tags         Timetable
+// @Accept       json
+// @Produce      json
+// @Param        range  body      viewmodels.GenerateSessionsRequest  true  "Date range"
+// @Success      200    {object}  viewmodels.GenerateSessionsResponse
+// @Failure      400    {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /timetable/sessions/generate [post]
+func (ctl *TimetableController) GenerateSessions(c *gin.Context) {
+	var req viewmodels.GenerateSessionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	resp, err := ctl.service.GenerateSessions(req.From, req.To)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateSession handles PUT /timetable/sessions/:id
+// @Summary      Cancel or restore a class session
+// @Description  Students are not expected at a cancelled session, so it never counts as an absence.
+// @Tags         Timetable
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                                   true  "Session ID"
+// @Param        session  body      viewmodels.UpdateClassSessionRequest  true  "Cancelled or not"
+// @Success      200      {object}  viewmodels.ClassSessionResponse
+// @Failure      400      {object}  viewmodels.ErrorResponse
+// @Failure      404      {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /timetable/sessions/{id} [put]
+func (ctl *TimetableController) UpdateSession(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	var req viewmodels.UpdateClassSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	session, err := ctl.service.SetSessionCancelled(uint(id), *req.Cancelled)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
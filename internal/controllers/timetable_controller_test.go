@@ -0,0 +1,149 @@
+package controllers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hrms_backend/internal/controllers"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mock Service ---
+type MockTimetableService struct {
+	mock.Mock
+}
+
+func (m *MockTimetableService) CreateSlot(req viewmodels.CreateTimetableSlotRequest) (*viewmodels.TimetableSlotResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.TimetableSlotResponse), args.Error(1)
+}
+
+func (m *MockTimetableService) ListSlots(sectionID uint) ([]viewmodels.TimetableSlotResponse, error) {
+	args := m.Called(sectionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]viewmodels.TimetableSlotResponse), args.Error(1)
+}
+
+func (m *MockTimetableService) UpdateSlot(id uint, req viewmodels.TimetableSlotRequest) (*viewmodels.TimetableSlotResponse, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.TimetableSlotResponse), args.Error(1)
+}
+
+func (m *MockTimetableService) DeleteSlot(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTimetableService) GenerateSessions(from, to time.Time) (*viewmodels.GenerateSessionsResponse, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.GenerateSessionsResponse), args.Error(1)
+}
+
+func (m *MockTimetableService) GenerateUpcomingSessions() (*viewmodels.GenerateSessionsResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.GenerateSessionsResponse), args.Error(1)
+}
+
+func (m *MockTimetableService) ListSessions(query viewmodels.ClassSessionQuery) ([]viewmodels.ClassSessionResponse, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]viewmodels.ClassSessionResponse), args.Error(1)
+}
+
+func (m *MockTimetableService) SetSessionCancelled(id uint, cancelled bool) (*viewmodels.ClassSessionResponse, error) {
+	args := m.Called(id, cancelled)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.ClassSessionResponse), args.Error(1)
+}
+
+func timetableRouter(service *MockTimetableService, role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := newRouter()
+	controllers.NewTimetableController(service).RegisterRoutes(r.Group("/timetable", asRole(role, nil)))
+	return r
+}
+
+// --- Tests ---
+
+func TestTimetableController(t *testing.T) {
+	mockService := new(MockTimetableService)
+	send := func(role, method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		timetableRouter(mockService, role).ServeHTTP(w, req)
+		return w
+	}
+
+	// Case 1: Create a slot
+	mockService.On("CreateSlot", mock.MatchedBy(func(req viewmodels.CreateTimetableSlotRequest) bool {
+		return req.SectionID == 3 && req.Weekday == "monday" && req.StartTime == "09:00"
+	})).Return(&viewmodels.TimetableSlotResponse{ID: 1, SectionID: 3, Weekday: "monday"}, nil).Once()
+	w := send(models.RoleAdmin, "POST", "/timetable/slots",
+		`{"section_id":3,"weekday":"monday","start_time":"09:00","end_time":"10:30","valid_from":"2025-09-01T00:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Case 2: Invalid weekday and time
+	w = send(models.RoleAdmin, "POST", "/timetable/slots",
+		`{"section_id":3,"weekday":"mon","start_time":"9am","end_time":"10:30","valid_from":"2025-09-01T00:00:00Z"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "weekday")
+	assert.Contains(t, w.Body.String(), "start_time")
+
+	// Case 3: Teachers read but cannot change the timetable
+	mockService.On("ListSlots", uint(3)).Return([]viewmodels.TimetableSlotResponse{{ID: 1}}, nil).Once()
+	w = send(models.RoleTeacher, "GET", "/timetable/slots?section_id=3", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = send(models.RoleTeacher, "DELETE", "/timetable/slots/1", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Case 4: Sessions of a week
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC)
+	mockService.On("ListSessions", viewmodels.ClassSessionQuery{From: from, To: to, SectionID: 3}).
+		Return([]viewmodels.ClassSessionResponse{{ID: 8, Date: from, StartTime: "09:00"}}, nil).Once()
+	w = send(models.RoleTeacher, "GET", "/timetable/sessions?from=2025-12-01&to=2025-12-07&section_id=3", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"start_time":"09:00"`)
+
+	// Case 5: Cancel a session; the flag is required
+	mockService.On("SetSessionCancelled", uint(8), true).Return(&viewmodels.ClassSessionResponse{ID: 8, Cancelled: true}, nil).Once()
+	w = send(models.RoleAdmin, "PUT", "/timetable/sessions/8", `{"cancelled":true}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = send(models.RoleAdmin, "PUT", "/timetable/sessions/8", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Case 6: Generate over a range
+	mockService.On("GenerateSessions", from, to).Return(nil, services.ValidationError("to", "must be less than a year after from")).Once()
+	w = send(models.RoleAdmin, "POST", "/timetable/sessions/generate", `{"from":"2025-12-01T00:00:00Z","to":"2025-12-07T00:00:00Z"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTP for outbound webhook subscriptions and their delivery log.
+type WebhookController struct {
+	service services.WebhookService
+}
+
+// Constructor
+func NewWebhookController(service services.WebhookService) *WebhookController {
+	return &WebhookController{service: service}
+}
+
+// Register routes under an authenticated router group (e.g., /webhooks); admin only
+func (ctl *WebhookController) RegisterRoutes(rg *gin.RouterGroup) {
+	admin := middleware.RequireRoles(models.RoleAdmin)
+
+	rg.GET("", admin, ctl.ListWebhooks)
+	rg.POST("", admin, ctl.CreateWebhook)
+	rg.GET("/:id", admin, ctl.GetWebhook)
+	rg.PUT("/:id", admin, ctl.UpdateWebhook)
+	rg.DELETE("/:id", admin, ctl.DeleteWebhook)
+	rg.GET("/:id/deliveries", admin, ctl.ListDeliveries)
+	rg.POST("/deliveries/:id/retry", admin, ctl.RetryDelivery)
+}
+
+// ListWebhooks handles GET /webhooks
+// @Summary      List webhook subscriptions
+// @Description  Retrieves every subscription, without its secret.
+// @Tags         Webhooks
+// @Produce      json
+// @Success      200  {array}   viewmodels.WebhookResponse
+// @Failure      500  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /webhooks [get]
+func (ctl *WebhookController) ListWebhooks(c *gin.Context) {
+	webhooks, err := ctl.service.ListWebhooks()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// CreateWebhook handles POST /webhooks
+// @Summary      Subscribe a URL to events
+// @Description  Student and attendance events are POSTed to the URL as JSON, signed in X-Webhook-Signature with HMAC-SHA256 of the secret. The secret is generated when left out and only returned in this response.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        webhook  body      viewmodels.WebhookRequest  true  "Subscription"
+// @Success      201      {object}  viewmodels.WebhookResponse
+// @Failure      400      {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /webhooks [post]
+func (ctl *WebhookController) CreateWebhook(c *gin.Context) {
+	var req viewmodels.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	webhook, err := ctl.service.CreateWebhook(req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// GetWebhook handles GET /webhooks/:id
+// @Summary      Get a webhook subscription
+// @Tags         Webhooks
+// @Produce      json
+// @Param        id   path      int  true  "Subscription ID"
+// @Success      200  {object}  viewmodels.WebhookResponse
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /webhooks/{id} [get]
+func (ctl *WebhookController) GetWebhook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	webhook, err := ctl.service.GetWebhook(uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, webhook)
+}
+
+// UpdateWebhook handles PUT /webhooks/:id
+// @Summary      Update a webhook subscription
+// @Description  Replaces the URL and events. The secret and active flag only change when given.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                        true  "Subscription ID"
+// @Param        webhook  body      viewmodels.WebhookRequest  true  "Subscription"
+// @Success      200      {object}  viewmodels.WebhookResponse
+// @Failure      400      {object}  viewmodels.ErrorResponse
+// @Failure      404      {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /webhooks/{id} [put]
+func (ctl *WebhookController) UpdateWebhook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	var req viewmodels.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	webhook, err := ctl.service.UpdateWebhook(uint(id), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook handles DELETE /webhooks/:id
+// @Summary      Delete a webhook subscription
+// @Description  Deletes the subscription together with its delivery log; queued deliveries are not sent.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        id   path  int  true  "Subscription ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /webhooks/{id} [delete]
+func (ctl *WebhookController) DeleteWebhook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	if err := ctl.service.DeleteWebhook(uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /webhooks/:id/deliveries
+// @Summary      List the deliveries of a webhook subscription
+// @Description  Retrieves a paginated delivery log, newest first: the payload sent, the number of attempts, the last response status or error, and when the next attempt is due.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        id      path      int     true   "Subscription ID"
+// @Param        status  query     string  false  "Delivery status"  Enums(pending, succeeded, failed)
+// @Param        event   query     string  false  "Event type, e.g. attendance.marked"
+// @Param        page    query     int     false  "Page number"      minimum(1)
+// @Param        limit   query     int     false  "Items per page"   minimum(1)  maximum(100)
+// @Success      200     {object}  viewmodels.WebhookDeliveryListResponse
+// @Failure      400     {object}  viewmodels.ErrorResponse
+// @Failure      404     {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /webhooks/{id}/deliveries [get]
+func (ctl *WebhookController) ListDeliveries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	var query viewmodels.WebhookDeliveryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	resp, err := ctl.service.ListDeliveries(uint(id), query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RetryDelivery handles POST /webhooks/deliveries/:id/retry
+// @Summary      Retry a webhook delivery now
+// @Description  Makes one more attempt at a pending or failed delivery and returns its outcome. Returns 409 if it already succeeded.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        id   path      int  true  "Delivery ID"
+// @Success      200  {object}  viewmodels.WebhookDeliveryResponse
+// @Failure      400  {object}  viewmodels.ErrorResponse
+// @Failure      404  {object}  viewmodels.ErrorResponse
+// @Failure      409  {object}  viewmodels.ErrorResponse
+// @Security     BearerAuth
+// @Router       /webhooks/deliveries/{id}/retry [post]
+func (ctl *WebhookController) RetryDelivery(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "id")
+		return
+	}
+
+	delivery, err := ctl.service.RetryDelivery(uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
@@ -0,0 +1,144 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hrms_backend/internal/controllers"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mock Service ---
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) Publish(event services.WebhookEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) CreateWebhook(req viewmodels.WebhookRequest) (*viewmodels.WebhookResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) ListWebhooks() ([]viewmodels.WebhookResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]viewmodels.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) GetWebhook(id uint) (*viewmodels.WebhookResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) UpdateWebhook(id uint, req viewmodels.WebhookRequest) (*viewmodels.WebhookResponse, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) DeleteWebhook(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) ListDeliveries(subscriptionID uint, query viewmodels.WebhookDeliveryQuery) (*viewmodels.WebhookDeliveryListResponse, error) {
+	args := m.Called(subscriptionID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.WebhookDeliveryListResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) RetryDelivery(id uint) (*viewmodels.WebhookDeliveryResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.WebhookDeliveryResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) DeliverDue() (*viewmodels.WebhookRunResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.WebhookRunResponse), args.Error(1)
+}
+
+// --- Tests ---
+
+func TestWebhookController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockWebhookService)
+	ctl := controllers.NewWebhookController(mockService)
+	send := func(role, method, url, body string) *httptest.ResponseRecorder {
+		r := newRouter()
+		ctl.RegisterRoutes(r.Group("/webhooks", asRole(role, nil)))
+		req, _ := http.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Case 1: Create
+	req := viewmodels.WebhookRequest{URL: "https://lms.test/hook", Events: []string{"attendance.marked"}}
+	mockService.On("CreateWebhook", req).Return(&viewmodels.WebhookResponse{ID: 1, URL: req.URL, Secret: "generated"}, nil).Once()
+	w := send(models.RoleAdmin, "POST", "/webhooks", `{"url":"https://lms.test/hook","events":["attendance.marked"]}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"secret":"generated"`)
+
+	// Case 2: Unknown event, not a URL
+	w = send(models.RoleAdmin, "POST", "/webhooks", `{"url":"https://lms.test/hook","events":["student.graduated"]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = send(models.RoleAdmin, "POST", "/webhooks", `{"url":"lms","events":["student.created"]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Case 3: Admin only
+	w = send(models.RoleTeacher, "GET", "/webhooks", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Case 4: Delivery log with filters
+	mockService.On("ListDeliveries", uint(1), viewmodels.WebhookDeliveryQuery{Status: "failed", Page: 2}).Return(&viewmodels.WebhookDeliveryListResponse{
+		Data: []viewmodels.WebhookDeliveryResponse{{ID: 7, Status: "failed", Attempts: 10}}, Total: 21, Page: 2, Limit: 20,
+	}, nil).Once()
+	w = send(models.RoleAdmin, "GET", "/webhooks/1/deliveries?status=failed&page=2", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"attempts":10`)
+	w = send(models.RoleAdmin, "GET", "/webhooks/1/deliveries?status=lost", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Case 5: Retrying a delivered event
+	mockService.On("RetryDelivery", uint(7)).Return(nil, services.ErrDeliverySucceeded).Once()
+	w = send(models.RoleAdmin, "POST", "/webhooks/deliveries/7/retry", "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Case 6: Delete unknown
+	mockService.On("DeleteWebhook", uint(9)).Return(services.ErrWebhookNotFound).Once()
+	w = send(models.RoleAdmin, "DELETE", "/webhooks/9", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockService.AssertExpectations(t)
+}
@@ -1,60 +1,102 @@
 package cronJob
 
 import (
-	"fmt"
 	"hrms_backend/internal/services"
 	"log"
 )
 
 type AttendanceCron struct {
-	service services.AttendanceService
+	service services.ReportService
 }
 
-func NewAttendanceCron(service services.AttendanceService) *AttendanceCron {
+func NewAttendanceCron(service services.ReportService) *AttendanceCron {
 	return &AttendanceCron{service: service}
 }
 
-// RunWeeklyReport generates and prints the attendance stats
-func (j *AttendanceCron) RunWeeklyReport() {
+// RunWeeklyReport generates the weekly attendance stats and stores them as a report
+func (j *AttendanceCron) RunWeeklyReport() error {
 	log.Println("------ 🗓️ Starting Weekly Attendance Report ------")
 
-	records, err := j.service.GetWeeklyAttendance()
+	report, err := j.service.GenerateWeeklyReport()
 	if err != nil {
-		log.Printf("❌ Error fetching weekly attendance: %v\n", err)
-		return
+		return err
 	}
 
-	if len(records) == 0 {
+	if len(report.Rows) == 0 {
 		log.Println("No attendance records found for the last 7 days.")
-		return
 	}
+	log.Printf("------ ✅ Weekly Report #%d saved (%s to %s, %d students) ------\n",
+		report.ID, report.PeriodStart.Format("2006-01-02"), report.PeriodEnd.Format("2006-01-02"), len(report.Rows))
+	return nil
+}
+
+// RunMonthlyReport stores the attendance stats of the previous calendar month
+func (j *AttendanceCron) RunMonthlyReport() error {
+	log.Println("------ 🗓️ Starting Monthly Attendance Report ------")
 
-	// Aggregation Logic: Map[StudentID] -> Stats
-	type Stats struct {
-		Name    string
-		Present int
-		Total   int
+	report, err := j.service.GenerateMonthlyReport()
+	if err != nil {
+		return err
 	}
-	report := make(map[uint]*Stats)
-
-	for _, rec := range records {
-		if _, exists := report[rec.StudentID]; !exists {
-			report[rec.StudentID] = &Stats{Name: rec.StudentName, Present: 0, Total: 0}
-		}
-
-		stats := report[rec.StudentID]
-		stats.Total++
-		if rec.Status == "present" {
-			stats.Present++
-		}
+
+	log.Printf("------ ✅ Monthly Report #%d saved (%s to %s, %d students) ------\n",
+		report.ID, report.PeriodStart.Format("2006-01-02"), report.PeriodEnd.Format("2006-01-02"), len(report.Rows))
+	return nil
+}
+
+type AlertCron struct {
+	service services.AlertService
+}
+
+func NewAlertCron(service services.AlertService) *AlertCron {
+	return &AlertCron{service: service}
+}
+
+// RunAbsenceAlerts notifies about the students who newly crossed an attendance threshold.
+// Alerts that could not be sent fail the run and are retried on the next one.
+func (j *AlertCron) RunAbsenceAlerts() error {
+	resp, err := j.service.CheckAbsences()
+	if resp != nil {
+		log.Printf("🔔 Absence alerts (%s to %s): %d students checked, %d sent, %d failed, %d resolved\n",
+			resp.From.Format("2006-01-02"), resp.To.Format("2006-01-02"), resp.Checked, resp.Sent, resp.Failed, resp.Resolved)
 	}
+	return err
+}
 
-	// Print Summary
-	for id, stats := range report {
-		// e.g. "Student Alice (ID: 1) was present 4/5 days"
-		fmt.Printf("🎓 Student %s (ID: %d) was present %d/%d days this week.\n",
-			stats.Name, id, stats.Present, stats.Total)
+type TimetableCron struct {
+	service services.TimetableService
+}
+
+func NewTimetableCron(service services.TimetableService) *TimetableCron {
+	return &TimetableCron{service: service}
+}
+
+// RunGenerateSessions creates the class sessions of the next two weeks from the timetable,
+// so students are expected at them
+func (j *TimetableCron) RunGenerateSessions() error {
+	resp, err := j.service.GenerateUpcomingSessions()
+	if err != nil {
+		return err
 	}
+	log.Printf("🗓️ Generated %d class sessions (%s to %s)\n", resp.Created, resp.From.Format("2006-01-02"), resp.To.Format("2006-01-02"))
+	return nil
+}
 
-	log.Println("------ ✅ Weekly Report Completed ------")
+type WebhookCron struct {
+	service services.WebhookService
+}
+
+func NewWebhookCron(service services.WebhookService) *WebhookCron {
+	return &WebhookCron{service: service}
+}
+
+// RunDeliveries sends the webhook deliveries that are due. Receivers failing do not fail the run:
+// their deliveries are retried later with exponential backoff.
+func (j *WebhookCron) RunDeliveries() error {
+	resp, err := j.service.DeliverDue()
+	if resp != nil && resp.Attempted > 0 {
+		log.Printf("📮 Webhook deliveries: %d attempted, %d succeeded, %d retrying, %d failed\n",
+			resp.Attempted, resp.Succeeded, resp.Retrying, resp.Failed)
+	}
+	return err
 }
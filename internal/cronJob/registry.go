@@ -0,0 +1,200 @@
+package cronJob
+
+import (
+	"fmt"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	// ErrJobNotFound is returned when running a job that is not registered.
+	ErrJobNotFound = &services.Error{Kind: services.ErrNotFound, Code: "job_not_found", Message: "job not found"}
+	// ErrJobRunning is returned when running a job whose previous run has not finished.
+	ErrJobRunning = &services.Error{Kind: services.ErrConflict, Code: "job_running", Message: "job is already running"}
+)
+
+// Job is a named task run on a cron schedule.
+type Job struct {
+	Name     string
+	Schedule string // any spec accepted by robfig/cron, e.g. "@weekly" or "0 0 * * 0"
+	Enabled  bool
+	Run      func() error
+}
+
+// JobFromEnv builds a job whose schedule and enabled flag can be overridden with
+// JOB_<NAME>_SCHEDULE and JOB_<NAME>_ENABLED (name upper-cased, e.g. JOB_WEEKLY_REPORT_SCHEDULE).
+func JobFromEnv(name, defaultSchedule string, defaultEnabled bool, run func() error) Job {
+	prefix := "JOB_" + strings.ToUpper(name) + "_"
+
+	job := Job{Name: name, Schedule: defaultSchedule, Enabled: defaultEnabled, Run: run}
+	if schedule := os.Getenv(prefix + "SCHEDULE"); schedule != "" {
+		job.Schedule = schedule
+	}
+	if enabled := os.Getenv(prefix + "ENABLED"); enabled != "" {
+		if v, err := strconv.ParseBool(enabled); err == nil {
+			job.Enabled = v
+		} else {
+			log.Printf("⚠️ Ignoring invalid %sENABLED=%q\n", prefix, enabled)
+		}
+	}
+	return job
+}
+
+// Registry schedules jobs on a cron instance and keeps the outcome of their last run.
+type Registry struct {
+	cron *cron.Cron
+
+	mu    sync.Mutex
+	jobs  map[string]*entry
+	order []string // registration order, for stable listings
+}
+
+type entry struct {
+	job     Job
+	cronID  cron.EntryID
+	running bool
+
+	lastRunAt    time.Time
+	lastStatus   string // "success" or "failed"; empty until the first run
+	lastError    string
+	lastDuration time.Duration
+}
+
+func NewRegistry(c *cron.Cron) *Registry {
+	return &Registry{cron: c, jobs: make(map[string]*entry)}
+}
+
+// Register adds a job; disabled jobs are listed but never scheduled.
+func (r *Registry) Register(job Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.jobs[job.Name]; exists {
+		return fmt.Errorf("job %q already registered", job.Name)
+	}
+
+	e := &entry{job: job}
+	if job.Enabled {
+		id, err := r.cron.AddFunc(job.Schedule, func() { r.run(e) })
+		if err != nil {
+			return fmt.Errorf("job %q: invalid schedule %q: %w", job.Name, job.Schedule, err)
+		}
+		e.cronID = id
+	}
+
+	r.jobs[job.Name] = e
+	r.order = append(r.order, job.Name)
+	return nil
+}
+
+// RunNow starts a job immediately, outside its schedule (even if disabled), in the background.
+// It fails with ErrJobNotFound or ErrJobRunning without starting it. The job's own error is
+// recorded in its status and sent on the returned channel once it finishes.
+func (r *Registry) RunNow(name string) (<-chan error, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.jobs[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	if e.running {
+		return nil, ErrJobRunning
+	}
+	e.running = true
+
+	done := make(chan error, 1)
+	go func() { done <- r.execute(e) }()
+	return done, nil
+}
+
+// run executes a scheduled job unless a previous run is still in progress
+func (r *Registry) run(e *entry) {
+	r.mu.Lock()
+	if e.running {
+		r.mu.Unlock()
+		log.Printf("⏭️ Job %s skipped: previous run still in progress\n", e.job.Name)
+		return
+	}
+	e.running = true
+	r.mu.Unlock()
+	r.execute(e)
+}
+
+// execute runs a job already marked running, records the outcome and clears the mark
+func (r *Registry) execute(e *entry) error {
+	start := time.Now()
+	err := e.job.Run()
+	duration := time.Since(start)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e.running = false
+	e.lastRunAt = start
+	e.lastDuration = duration
+	if err != nil {
+		e.lastStatus = "failed"
+		e.lastError = err.Error()
+		log.Printf("❌ Job %s failed after %s: %v\n", e.job.Name, duration, err)
+	} else {
+		e.lastStatus = "success"
+		e.lastError = ""
+		log.Printf("✅ Job %s finished in %s\n", e.job.Name, duration)
+	}
+	return err
+}
+
+// Statuses reports every registered job, in registration order
+func (r *Registry) Statuses() []viewmodels.JobStatusResponse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := make([]viewmodels.JobStatusResponse, 0, len(r.order))
+	for _, name := range r.order {
+		statuses = append(statuses, r.status(r.jobs[name]))
+	}
+	return statuses
+}
+
+// Status reports one job, or fails with ErrJobNotFound
+func (r *Registry) Status(name string) (*viewmodels.JobStatusResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.jobs[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	status := r.status(e)
+	return &status, nil
+}
+
+// status describes e; r.mu must be held
+func (r *Registry) status(e *entry) viewmodels.JobStatusResponse {
+	status := viewmodels.JobStatusResponse{
+		Name:       e.job.Name,
+		Schedule:   e.job.Schedule,
+		Enabled:    e.job.Enabled,
+		Running:    e.running,
+		LastStatus: e.lastStatus,
+		LastError:  e.lastError,
+	}
+	if !e.lastRunAt.IsZero() {
+		lastRun := e.lastRunAt
+		status.LastRunAt = &lastRun
+		status.LastDurationMs = e.lastDuration.Milliseconds()
+	}
+	if e.job.Enabled {
+		if next := r.cron.Entry(e.cronID).Next; !next.IsZero() {
+			status.NextRunAt = &next
+		}
+	}
+	return status
+}
@@ -0,0 +1,91 @@
+package cronJob_test
+
+import (
+	"errors"
+	"testing"
+
+	"hrms_backend/internal/cronJob"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobFromEnv(t *testing.T) {
+	run := func() error { return nil }
+
+	// Case 1: Defaults
+	job := cronJob.JobFromEnv("weekly_report", "@weekly", true, run)
+	assert.Equal(t, "@weekly", job.Schedule)
+	assert.True(t, job.Enabled)
+
+	// Case 2: Overridden from env
+	t.Setenv("JOB_WEEKLY_REPORT_SCHEDULE", "0 0 * * 0")
+	t.Setenv("JOB_WEEKLY_REPORT_ENABLED", "false")
+	job = cronJob.JobFromEnv("weekly_report", "@weekly", true, run)
+	assert.Equal(t, "0 0 * * 0", job.Schedule)
+	assert.False(t, job.Enabled)
+
+	// Case 3: Invalid flag keeps the default
+	t.Setenv("JOB_WEEKLY_REPORT_ENABLED", "maybe")
+	job = cronJob.JobFromEnv("weekly_report", "@weekly", true, run)
+	assert.True(t, job.Enabled)
+}
+
+func TestRegistry(t *testing.T) {
+	registry := cronJob.NewRegistry(cron.New())
+
+	calls := 0
+	assert.NoError(t, registry.Register(cronJob.Job{Name: "ok", Schedule: "@daily", Enabled: true, Run: func() error {
+		calls++
+		return nil
+	}}))
+	assert.NoError(t, registry.Register(cronJob.Job{Name: "broken", Schedule: "@hourly", Enabled: false, Run: func() error {
+		return errors.New("boom")
+	}}))
+
+	// Case 1: Duplicate name and invalid schedule are rejected
+	assert.Error(t, registry.Register(cronJob.Job{Name: "ok", Schedule: "@daily", Enabled: true}))
+	assert.Error(t, registry.Register(cronJob.Job{Name: "bad", Schedule: "every now and then", Enabled: true}))
+
+	// Case 2: Nothing has run yet
+	statuses := registry.Statuses()
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "ok", statuses[0].Name)
+	assert.Nil(t, statuses[0].LastRunAt)
+	assert.False(t, statuses[1].Enabled)
+
+	// Case 3: Outcome of the last run is recorded
+	done, err := registry.RunNow("ok")
+	assert.NoError(t, err)
+	assert.NoError(t, <-done)
+	done, err = registry.RunNow("broken")
+	assert.NoError(t, err)
+	assert.Error(t, <-done)
+	_, err = registry.RunNow("missing")
+	assert.ErrorIs(t, err, cronJob.ErrJobNotFound)
+
+	statuses = registry.Statuses()
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "success", statuses[0].LastStatus)
+	assert.NotNil(t, statuses[0].LastRunAt)
+	assert.Equal(t, "failed", statuses[1].LastStatus)
+	assert.Equal(t, "boom", statuses[1].LastError)
+
+	// Case 4: A job is not run twice at the same time
+	started, release := make(chan struct{}), make(chan struct{})
+	assert.NoError(t, registry.Register(cronJob.Job{Name: "slow", Schedule: "@daily", Run: func() error {
+		close(started)
+		<-release
+		return nil
+	}}))
+	done, err = registry.RunNow("slow")
+	assert.NoError(t, err)
+	<-started
+	_, err = registry.RunNow("slow")
+	assert.ErrorIs(t, err, cronJob.ErrJobRunning)
+	status, err := registry.Status("slow")
+	assert.NoError(t, err)
+	assert.True(t, status.Running)
+	close(release)
+	assert.NoError(t, <-done)
+}
@@ -0,0 +1,21 @@
+package export
+
+import (
+	"encoding/csv"
+	"hrms_backend/internal/viewmodels"
+	"io"
+)
+
+func writeCSV(w io.Writer, _ string, rows []viewmodels.ReportRowResponse) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := cw.Write(record(row)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
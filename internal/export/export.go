@@ -0,0 +1,49 @@
+// Package export renders attendance summaries as downloadable files.
+package export
+
+import (
+	"fmt"
+	"hrms_backend/internal/viewmodels"
+	"io"
+	"strconv"
+)
+
+// Format describes an export file type
+type Format struct {
+	ContentType string
+	Extension   string
+	write       func(w io.Writer, title string, rows []viewmodels.ReportRowResponse) error
+}
+
+// Formats supported by Write, keyed by the value of the ?format= query parameter
+var Formats = map[string]Format{
+	"csv":  {ContentType: "text/csv; charset=utf-8", Extension: "csv", write: writeCSV},
+	"xlsx": {ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", Extension: "xlsx", write: writeXLSX},
+	"pdf":  {ContentType: "application/pdf", Extension: "pdf", write: writePDF},
+}
+
+// column headers shared by every format
+var header = []string{"Name", "Department", "Present", "Late", "Late (min)", "Absent", "Excused", "Percentage"}
+
+// Write renders rows in the given format to w. title is used where the format has room for one (XLSX sheet, PDF heading).
+func Write(w io.Writer, format, title string, rows []viewmodels.ReportRowResponse) error {
+	f, ok := Formats[format]
+	if !ok {
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+	return f.write(w, title, rows)
+}
+
+// record flattens a row in header order
+func record(row viewmodels.ReportRowResponse) []string {
+	return []string{
+		row.StudentName,
+		row.Department,
+		strconv.Itoa(row.Present),
+		strconv.Itoa(row.Late),
+		strconv.Itoa(row.LateMinutes),
+		strconv.Itoa(row.Absent),
+		strconv.Itoa(row.Excused),
+		strconv.FormatFloat(row.Percentage, 'f', 2, 64),
+	}
+}
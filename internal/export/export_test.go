@@ -0,0 +1,51 @@
+package export_test
+
+import (
+	"bytes"
+	"testing"
+
+	"hrms_backend/internal/export"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+var rows = []viewmodels.ReportRowResponse{
+	{StudentName: "Alice", Department: "IT", Present: 3, Late: 1, LateMinutes: 12, Absent: 1, Excused: 0, Total: 5, Percentage: 80},
+	{StudentName: "Zoë", Department: "HR", Absent: 2, Total: 2},
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, export.Write(&buf, "csv", "Attendance", rows))
+	assert.Equal(t, "Name,Department,Present,Late,Late (min),Absent,Excused,Percentage\n"+
+		"Alice,IT,3,1,12,1,0,80.00\n"+
+		"Zoë,HR,0,0,0,2,0,0.00\n", buf.String())
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, export.Write(&buf, "xlsx", "Attendance 2025-12-01 to 2025-12-07", rows))
+
+	f, err := excelize.OpenReader(&buf)
+	assert.NoError(t, err)
+	sheet := f.GetSheetName(0)
+	assert.Equal(t, "Attendance 2025-12-01 to 2025-1", sheet)
+
+	got, err := f.GetRows(sheet)
+	assert.NoError(t, err)
+	assert.Len(t, got, 3)
+	assert.Equal(t, []string{"Alice", "IT", "3", "1", "12", "1", "0", "80"}, got[1])
+}
+
+func TestWritePDF(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, export.Write(&buf, "pdf", "Attendance", rows))
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+}
+
+func TestWriteUnsupported(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, export.Write(&buf, "docx", "Attendance", rows))
+}
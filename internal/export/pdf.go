@@ -0,0 +1,54 @@
+package export
+
+import (
+	"hrms_backend/internal/viewmodels"
+	"io"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// column widths in mm, matching header (A4 portrait has 190mm between the default margins)
+var pdfWidths = []float64{44, 34, 17, 15, 22, 17, 17, 24}
+
+func writePDF(w io.Writer, title string, rows []viewmodels.ReportRowResponse) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	// core fonts are cp1252, translate so accented names render
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(0, 10, tr(title), "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+
+	tableHeader := func() {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.SetFillColor(230, 230, 230)
+		for i, h := range header {
+			pdf.CellFormat(pdfWidths[i], 7, h, "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 10)
+	}
+
+	tableHeader()
+	for _, row := range rows {
+		// repeat the header on every page
+		_, pageHeight := pdf.GetPageSize()
+		_, _, _, bottom := pdf.GetMargins()
+		if pdf.GetY()+7 > pageHeight-bottom {
+			pdf.AddPage()
+			tableHeader()
+		}
+
+		for i, value := range record(row) {
+			align := "R"
+			if i < 2 {
+				align = "L"
+			}
+			pdf.CellFormat(pdfWidths[i], 7, tr(value), "1", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	return pdf.Output(w)
+}
@@ -0,0 +1,50 @@
+package export
+
+import (
+	"hrms_backend/internal/viewmodels"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// writeXLSX builds a single sheet workbook; counts are written as numbers so they can be summed
+func writeXLSX(w io.Writer, title string, rows []viewmodels.ReportRowResponse) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	// sheet names are limited to 31 characters
+	sheet := title
+	if len(sheet) > 31 {
+		sheet = sheet[:31]
+	}
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		return err
+	}
+
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	if err := f.SetSheetRow(sheet, "A1", &header); err != nil {
+		return err
+	}
+	if err := f.SetCellStyle(sheet, "A1", "H1", bold); err != nil {
+		return err
+	}
+
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+		values := []interface{}{row.StudentName, row.Department, row.Present, row.Late, row.LateMinutes, row.Absent, row.Excused, row.Percentage}
+		if err := f.SetSheetRow(sheet, cell, &values); err != nil {
+			return err
+		}
+	}
+	if err := f.SetColWidth(sheet, "A", "B", 25); err != nil {
+		return err
+	}
+
+	return f.Write(w)
+}
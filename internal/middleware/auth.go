@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"hrms_backend/internal/services"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// key of the authenticated caller in the gin context
+const principalKey = "principal"
+
+var (
+	errMissingToken            = &services.Error{Kind: services.ErrUnauthorized, Code: "missing_token", Message: "missing bearer token"}
+	errAuthenticationRequired  = &services.Error{Kind: services.ErrUnauthorized, Code: "authentication_required", Message: "authentication required"}
+	errInsufficientPermissions = &services.Error{Kind: services.ErrForbidden, Code: "insufficient_permissions", Message: "insufficient permissions"}
+)
+
+// Authenticate requires a valid "Authorization: Bearer <access token>" header
+// and stores the caller in the context for the handlers and role checks that follow.
+func Authenticate(auth services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			abortWithError(c, errMissingToken)
+			return
+		}
+
+		principal, err := auth.ParseAccessToken(token)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		SetPrincipal(c, principal)
+		c.Next()
+	}
+}
+
+// SetPrincipal stores the authenticated caller in the context
+func SetPrincipal(c *gin.Context, principal *services.Principal) {
+	c.Set(principalKey, principal)
+}
+
+// CurrentPrincipal returns the caller stored by Authenticate
+func CurrentPrincipal(c *gin.Context) (*services.Principal, bool) {
+	value, ok := c.Get(principalKey)
+	if !ok {
+		return nil, false
+	}
+	principal, ok := value.(*services.Principal)
+	return principal, ok
+}
+
+// CurrentActor describes the caller for the audit log: user, request ID and client IP
+func CurrentActor(c *gin.Context) services.Actor {
+	actor := services.Actor{RequestID: CurrentRequestID(c), IP: c.ClientIP()}
+	if principal, ok := CurrentPrincipal(c); ok {
+		actor.UserID = principal.UserID
+	}
+	return actor
+}
+
+// RequireRoles lets the request through only if the caller has one of the roles
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := CurrentPrincipal(c)
+		if !ok {
+			abortWithError(c, errAuthenticationRequired)
+			return
+		}
+		if !slices.Contains(roles, principal.Role) {
+			abortWithError(c, errInsufficientPermissions)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireRolesOrOwnStudent is RequireRoles that also admits a student account
+// whose own student ID equals the path parameter param.
+func RequireRolesOrOwnStudent(param string, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := CurrentPrincipal(c)
+		if !ok {
+			abortWithError(c, errAuthenticationRequired)
+			return
+		}
+		if slices.Contains(roles, principal.Role) {
+			c.Next()
+			return
+		}
+		if principal.StudentID != nil && c.Param(param) == strconv.FormatUint(uint64(*principal.StudentID), 10) {
+			c.Next()
+			return
+		}
+		abortWithError(c, errInsufficientPermissions)
+	}
+}
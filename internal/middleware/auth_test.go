@@ -0,0 +1,134 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mock Auth Service ---
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) Login(req viewmodels.LoginRequest) (*viewmodels.TokenResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.TokenResponse), args.Error(1)
+}
+
+func (m *MockAuthService) Refresh(req viewmodels.RefreshRequest) (*viewmodels.TokenResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.TokenResponse), args.Error(1)
+}
+
+func (m *MockAuthService) ParseAccessToken(token string) (*services.Principal, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.Principal), args.Error(1)
+}
+
+func (m *MockAuthService) CreateUser(req viewmodels.CreateUserRequest) (*viewmodels.UserResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*viewmodels.UserResponse), args.Error(1)
+}
+
+func (m *MockAuthService) EnsureAdmin(email, password string) error {
+	args := m.Called(email, password)
+	return args.Error(0)
+}
+
+// --- Helpers ---
+
+func serve(r *gin.Engine, path, token string) int {
+	req, _ := http.NewRequest("GET", path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func ok(c *gin.Context) { c.Status(http.StatusOK) }
+
+// --- Tests ---
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockAuth := new(MockAuthService)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.GET("/me", middleware.Authenticate(mockAuth), func(c *gin.Context) {
+		principal, _ := middleware.CurrentPrincipal(c)
+		c.String(http.StatusOK, principal.Role)
+	})
+
+	// Case 1: Valid token
+	mockAuth.On("ParseAccessToken", "good").Return(&services.Principal{UserID: 1, Role: models.RoleTeacher}, nil).Once()
+	assert.Equal(t, http.StatusOK, serve(r, "/me", "good"))
+
+	// Case 2: Missing header
+	assert.Equal(t, http.StatusUnauthorized, serve(r, "/me", ""))
+
+	// Case 3: Invalid token
+	mockAuth.On("ParseAccessToken", "bad").Return(nil, services.ErrInvalidToken).Once()
+	assert.Equal(t, http.StatusUnauthorized, serve(r, "/me", "bad"))
+}
+
+func TestRequireRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	studentID := uint(7)
+	as := func(p *services.Principal) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			if p != nil {
+				middleware.SetPrincipal(c, p)
+			}
+		}
+	}
+	admin := &services.Principal{UserID: 1, Role: models.RoleAdmin}
+	teacher := &services.Principal{UserID: 2, Role: models.RoleTeacher}
+	student := &services.Principal{UserID: 3, Role: models.RoleStudent, StudentID: &studentID}
+
+	cases := []struct {
+		name      string
+		principal *services.Principal
+		path      string
+		want      int
+	}{
+		{"admin may delete", admin, "/admin", http.StatusOK},
+		{"teacher may not delete", teacher, "/admin", http.StatusForbidden},
+		{"anonymous", nil, "/admin", http.StatusUnauthorized},
+		{"teacher reads any student", teacher, "/students/1", http.StatusOK},
+		{"student reads own record", student, "/students/7", http.StatusOK},
+		{"student reads someone else", student, "/students/8", http.StatusForbidden},
+		{"anonymous read", nil, "/students/7", http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		r := gin.New()
+		r.Use(middleware.ErrorHandler())
+		r.Use(as(tc.principal))
+		r.GET("/admin", middleware.RequireRoles(models.RoleAdmin), ok)
+		r.GET("/students/:id", middleware.RequireRolesOrOwnStudent("id", models.RoleAdmin, models.RoleTeacher), ok)
+		assert.Equal(t, tc.want, serve(r, tc.path, ""), tc.name)
+	}
+}
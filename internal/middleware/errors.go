@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+	"log"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// status code for each kind of domain error; anything else is a 500
+var errorStatus = []struct {
+	kind   error
+	status int
+}{
+	{services.ErrValidation, http.StatusBadRequest},
+	{services.ErrUnauthorized, http.StatusUnauthorized},
+	{services.ErrForbidden, http.StatusForbidden},
+	{services.ErrNotFound, http.StatusNotFound},
+	{services.ErrConflict, http.StatusConflict},
+}
+
+var registerFieldNames sync.Once
+
+// ErrorHandler renders the last error a handler attached with c.Error as a viewmodels.ErrorResponse,
+// unless a response has already been written:
+//   - errors attached with type gin.ErrorTypeBind (request binding) are 400s, with one detail per invalid field
+//   - *services.Error values get the status of their kind, their code, message and fields
+//   - anything else is logged and reported as a bare 500, without leaking its text
+func ErrorHandler() gin.HandlerFunc {
+	// report fields by their json/form names rather than Go names
+	registerFieldNames.Do(func() {
+		if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+			v.RegisterTagNameFunc(fieldName)
+		}
+	})
+
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		status, resp := errorResponse(c.Errors.Last())
+		if status == http.StatusInternalServerError {
+			log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, c.Errors.Last().Err)
+		}
+		c.JSON(status, resp)
+	}
+}
+
+// abortWithError stops the chain with err, rendered by ErrorHandler
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+func errorResponse(ginErr *gin.Error) (int, viewmodels.ErrorResponse) {
+	err := ginErr.Err
+
+	if ginErr.IsType(gin.ErrorTypeBind) {
+		resp := viewmodels.ErrorResponse{Code: "invalid_request", Message: err.Error()}
+		var invalid validator.ValidationErrors
+		if errors.As(err, &invalid) {
+			resp.Code = "validation_failed"
+			resp.Message = "request validation failed"
+			for _, fe := range invalid {
+				resp.Details = append(resp.Details, viewmodels.FieldError{Field: fieldPath(fe), Message: validationMessage(fe)})
+			}
+		}
+		return http.StatusBadRequest, resp
+	}
+
+	var domainErr *services.Error
+	if errors.As(err, &domainErr) {
+		resp := viewmodels.ErrorResponse{Code: domainErr.Code, Message: domainErr.Message}
+		for _, field := range slices.Sorted(maps.Keys(domainErr.Fields)) {
+			resp.Details = append(resp.Details, viewmodels.FieldError{Field: field, Message: domainErr.Fields[field]})
+		}
+		for _, s := range errorStatus {
+			if errors.Is(err, s.kind) {
+				return s.status, resp
+			}
+		}
+	}
+
+	return http.StatusInternalServerError, viewmodels.ErrorResponse{Code: "internal_error", Message: "internal server error"}
+}
+
+// fieldName is the name a struct field has in requests: its json tag, else its form tag
+func fieldName(f reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// fieldPath drops the top level struct name: "records[1].status"
+func fieldPath(fe validator.FieldError) string {
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+	return path
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "min":
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	}
+	return fmt.Sprintf("failed the %q check", fe.Tag())
+}
@@ -0,0 +1,79 @@
+package middleware_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.POST("/bind", func(c *gin.Context) {
+		var req struct {
+			Status  string `json:"status" binding:"required,oneof=present absent"`
+			Student uint   `json:"student_id" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		}
+	})
+	r.GET("/error/:kind", func(c *gin.Context) {
+		switch c.Param("kind") {
+		case "not-found":
+			_ = c.Error(services.ErrStudentNotFound.Wrap(gorm.ErrRecordNotFound))
+		case "conflict":
+			_ = c.Error(services.ErrStudentEmailTaken)
+		case "validation":
+			_ = c.Error(services.ValidationError("to", "must not be before from"))
+		default:
+			_ = c.Error(errors.New("dial tcp: connection refused"))
+		}
+	})
+	send := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Case 1: Validation errors list each field by its json name
+	w := send("POST", "/bind", `{"status":"gone"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"code":"validation_failed","message":"request validation failed","details":[
+		{"field":"status","message":"must be one of: present, absent"},
+		{"field":"student_id","message":"is required"}]}`, w.Body.String())
+
+	// Case 2: Malformed body
+	w = send("POST", "/bind", `{`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_request"`)
+
+	// Case 3: Domain errors get the status of their kind
+	w = send("GET", "/error/not-found", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"code":"student_not_found","message":"student not found"}`, w.Body.String())
+
+	w = send("GET", "/error/conflict", "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"details":[{"field":"email","message":"already in use"}]`)
+
+	w = send("GET", "/error/validation", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Case 4: Anything else is an opaque 500
+	w = send("GET", "/error/other", "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"code":"internal_error","message":"internal server error"}`, w.Body.String())
+}
@@ -0,0 +1,40 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.RequestID())
+	var actor services.Actor
+	r.GET("/", func(c *gin.Context) {
+		middleware.SetPrincipal(c, &services.Principal{UserID: 4})
+		actor = middleware.CurrentActor(c)
+		c.Status(http.StatusOK)
+	})
+
+	// Case 1: The client's ID is kept
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set(middleware.RequestIDHeader, "abc-123")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "abc-123", w.Header().Get(middleware.RequestIDHeader))
+	assert.Equal(t, services.Actor{UserID: 4, RequestID: "abc-123", IP: actor.IP}, actor)
+
+	// Case 2: One is generated when missing
+	req, _ = http.NewRequest("GET", "/", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Len(t, w.Header().Get(middleware.RequestIDHeader), 32)
+	assert.Equal(t, w.Header().Get(middleware.RequestIDHeader), actor.RequestID)
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// absence alert kinds
+const (
+	AlertLowAttendance       = "low_attendance"       // attendance percentage under the threshold
+	AlertConsecutiveAbsences = "consecutive_absences" // too many school days missed in a row
+)
+
+// AbsenceAlert remembers the alerts sent about a student, so the alert job does not repeat
+// itself every run. It stays active while the threshold is still crossed and is resolved
+// once it no longer is; crossing it again sends a new alert.
+type AbsenceAlert struct {
+	ID        uint    `gorm:"primarykey"`
+	StudentID uint    `gorm:"not null;uniqueIndex:idx_absence_alert_student_kind"`
+	Student   Student `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Kind      string  `gorm:"type:varchar(30);not null;uniqueIndex:idx_absence_alert_student_kind"`
+	Active    bool    `gorm:"not null;default:false;index"`
+
+	// Values when last sent
+	Percentage          float64
+	ConsecutiveAbsences int
+	SentAt              time.Time
+	TimesSent           int `gorm:"not null;default:0"`
+	ResolvedAt          *time.Time
+	// Notifier channels the alert could not be delivered through yet; only those are retried
+	Pending []string `gorm:"type:json;serializer:json"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -9,10 +9,29 @@ import (
 type Attendance struct {
 	gorm.Model
 	// Foreign Key
-	StudentID uint `gorm:"index"` // Index for FK lookups
+	// (student_id, date, section) is unique so a student has at most one record per day,
+	// or per day and section when attendance is taken per class.
+	StudentID uint `gorm:"uniqueIndex:idx_attendance_student_date_section"`
 
 	Student Student `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 
-	Date   time.Time `gorm:"not null"`
+	// Stored as a calendar day (no time component)
+	Date   time.Time `gorm:"type:date;not null;uniqueIndex:idx_attendance_student_date_section"`
 	Status string    `gorm:"type:varchar(20);default:'present'"`
+
+	// Class the record belongs to; nil for a whole day record
+	SectionID *uint   `gorm:"index"`
+	Section   Section `gorm:"constraint:OnDelete:RESTRICT;"`
+	// NULLs never collide in a unique index, so this generated column (0 for whole day records)
+	// carries it instead of section_id. Read only: the database maintains it.
+	SectionKey uint `gorm:"->;type:bigint unsigned GENERATED ALWAYS AS (IFNULL(section_id, 0)) STORED;uniqueIndex:idx_attendance_student_date_section"`
+
+	// When the student arrived and left, if recorded
+	CheckInAt  *time.Time
+	CheckOutAt *time.Time
+	// Minutes after the start of the class session the student checked in; only set for late records
+	LateMinutes int `gorm:"not null;default:0"`
+
+	// Optional client supplied key, lets retried requests resolve to the same row
+	IdempotencyKey *string `gorm:"type:varchar(100);uniqueIndex"`
 }
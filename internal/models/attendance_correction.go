@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AttendanceCorrection keeps the history of changes made to an attendance record.
+// Student and date are copied so the trail survives the record being deleted.
+type AttendanceCorrection struct {
+	gorm.Model
+	AttendanceID uint      `gorm:"index;not null"`
+	StudentID    uint      `gorm:"not null"`
+	Date         time.Time `gorm:"type:date;not null"`
+
+	Action         string `gorm:"type:varchar(20);not null"` // "update" or "delete"
+	PreviousStatus string `gorm:"type:varchar(20);not null"`
+	NewStatus      string `gorm:"type:varchar(20)"` // empty for deletions
+	Reason         string `gorm:"type:varchar(255);not null"`
+}
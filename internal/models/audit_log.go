@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// audited entity types
+const (
+	AuditEntityStudent    = "student"
+	AuditEntityAttendance = "attendance"
+)
+
+// audited actions
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore" // undo of a soft delete
+	AuditActionPurge   = "purge"   // permanent delete
+)
+
+// AuditLog records one change to an entity: who made it, from where, and what changed.
+// Entries are append-only, so there is no UpdatedAt or soft delete.
+type AuditLog struct {
+	ID         uint `gorm:"primarykey"`
+	CreatedAt  time.Time
+	ActorID    *uint  `gorm:"index"` // user who made the change; nil for system jobs
+	Action     string `gorm:"type:varchar(20);not null"`
+	EntityType string `gorm:"type:varchar(50);not null;index:idx_audit_entity"`
+	EntityID   uint   `gorm:"not null;index:idx_audit_entity"`
+	// JSON objects with the changed columns only; Before is nil on create, After on delete
+	Before    *string `gorm:"type:json"`
+	After     *string `gorm:"type:json"`
+	RequestID string  `gorm:"type:varchar(64);index"`
+	IP        string  `gorm:"type:varchar(45)"`
+}
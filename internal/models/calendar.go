@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// closure kinds
+const (
+	ClosureHoliday = "holiday" // public holiday or school break
+	ClosureClosure = "closure" // unplanned closure, e.g. a strike or a storm
+)
+
+// Term is a teaching period, e.g. "Fall 2025". Once at least one term exists,
+// days outside every term are not school days.
+type Term struct {
+	ID        uint      `gorm:"primarykey"`
+	Name      string    `gorm:"type:varchar(100);not null;uniqueIndex"`
+	StartDate time.Time `gorm:"type:date;not null;index"`
+	EndDate   time.Time `gorm:"type:date;not null"` // inclusive
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Closure is a range of days without classes (both ends inclusive), e.g. a holiday or a closure day.
+type Closure struct {
+	ID        uint      `gorm:"primarykey"`
+	Kind      string    `gorm:"type:varchar(20);not null"`
+	Name      string    `gorm:"type:varchar(100);not null"`
+	StartDate time.Time `gorm:"type:date;not null;index"`
+	EndDate   time.Time `gorm:"type:date;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Course is a subject taught in one or more sections, e.g. "CS101 Introduction to Programming".
+// Codes are unique (case insensitively, following the column collation).
+// Like departments, courses are not soft deleted and cannot be deleted while they have sections.
+type Course struct {
+	ID        uint   `gorm:"primarykey"`
+	Code      string `gorm:"type:varchar(20);not null;uniqueIndex"`
+	Name      string `gorm:"type:varchar(150);not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
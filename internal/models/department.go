@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Department groups students. Names are unique (case insensitively, following the column collation).
+// Departments are not soft deleted: one that still has students cannot be deleted at all,
+// and an empty one has nothing worth keeping.
+type Department struct {
+	ID        uint   `gorm:"primarykey"`
+	Name      string `gorm:"type:varchar(100);not null;uniqueIndex"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Enrollment puts a student in a section. A student is enrolled at most once per section,
+// and enrollments go away with their section or student.
+type Enrollment struct {
+	ID        uint    `gorm:"primarykey"`
+	SectionID uint    `gorm:"not null;uniqueIndex:idx_enrollment_section_student"`
+	Section   Section `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	StudentID uint    `gorm:"not null;uniqueIndex:idx_enrollment_section_student;index"`
+	Student   Student `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	CreatedAt time.Time
+}
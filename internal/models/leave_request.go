@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// leave request states: a request starts pending and is decided once
+const (
+	LeavePending   = "pending"
+	LeaveApproved  = "approved"
+	LeaveRejected  = "rejected"
+	LeaveCancelled = "cancelled" // withdrawn before a decision
+)
+
+// LeaveRequest asks for a student to be excused over a range of days (both ends inclusive).
+// Approving it marks the covered school days as "excused".
+type LeaveRequest struct {
+	ID        uint      `gorm:"primarykey"`
+	StudentID uint      `gorm:"not null;index"`
+	Student   Student   `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	StartDate time.Time `gorm:"type:date;not null"`
+	EndDate   time.Time `gorm:"type:date;not null"`
+	Reason    string    `gorm:"type:varchar(500);not null"`
+	Status    string    `gorm:"type:varchar(20);not null;default:'pending';index"`
+
+	// Who submitted it: the student's own account or a staff member on their behalf
+	RequestedByID *uint `gorm:"index"`
+
+	// Set once approved or rejected
+	ReviewedByID *uint
+	ReviewedAt   *time.Time
+	ReviewNote   string `gorm:"type:varchar(255)"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
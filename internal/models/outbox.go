@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// OutboxEvent is a change to a student or attendance record waiting to be published. It is
+// written in the same transaction as the change, so one is never committed without the other,
+// and the outbox dispatcher publishes it afterwards, in ID order. Rows stay once published.
+type OutboxEvent struct {
+	ID         uint   `gorm:"primarykey"`
+	Event      string `gorm:"type:varchar(50);not null"` // e.g. EventStudentCreated
+	EntityType string `gorm:"type:varchar(50);not null"`
+	EntityID   uint   `gorm:"not null"`
+	// JSON of the entity as saved, or as it was before a delete
+	Payload     string `gorm:"type:json;not null"`
+	CreatedAt   time.Time
+	PublishedAt *time.Time `gorm:"index"` // nil while pending
+	// set while a dispatcher is publishing the event; once past, the claim is abandoned
+	ClaimedUntil *time.Time
+	Attempts     int    `gorm:"not null;default:0"` // failed attempts to publish
+	LastError    string `gorm:"type:varchar(500)"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Report is one run of an attendance report over a period (both ends inclusive).
+type Report struct {
+	gorm.Model
+	Type        string    `gorm:"type:varchar(20);not null;index"` // e.g. "weekly"
+	PeriodStart time.Time `gorm:"type:date;not null"`
+	PeriodEnd   time.Time `gorm:"type:date;not null"`
+
+	Rows []ReportRow `gorm:"constraint:OnDelete:CASCADE;"`
+}
+
+// ReportRow holds the attendance counts of one student within a Report.
+// Name and department are copied so the report reads the same after the student changes.
+type ReportRow struct {
+	gorm.Model
+	ReportID    uint   `gorm:"index;not null"`
+	StudentID   uint   `gorm:"index;not null"`
+	StudentName string `gorm:"type:varchar(100)"`
+	Department  string `gorm:"type:varchar(100)"`
+
+	Present    int
+	Absent     int
+	Late       int
+	Excused    int
+	Total      int
+	Percentage float64 // share of marked days attended (present or late), 0-100
+
+	// minutes late summed over the late records
+	LateMinutes int
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Section is one class of a course (e.g. "A", "Morning") with its own enrolled students.
+// Attendance can be recorded per section; a section with attendance cannot be deleted.
+type Section struct {
+	ID        uint   `gorm:"primarykey"`
+	CourseID  uint   `gorm:"not null;uniqueIndex:idx_section_course_name"`
+	Course    Course `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Name      string `gorm:"type:varchar(50);not null;uniqueIndex:idx_section_course_name"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
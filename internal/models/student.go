@@ -7,7 +7,14 @@ import "gorm.io/gorm"
 // Student maps to the `students` table.
 type Student struct {
 	gorm.Model
-	Name       string `gorm:"type:varchar(100);not null"`
-	Email      string `gorm:"type:varchar(150);unique;not null"`
-	Department string `gorm:"type:varchar(100)"`
+	Name  string `gorm:"type:varchar(100);not null"`
+	Email string `gorm:"type:varchar(150);not null;index"`
+	// Every student belongs to a department, which cannot be deleted while it has students
+	DepartmentID uint       `gorm:"not null;index"`
+	Department   Department `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+
+	// Email is only unique among students that are not soft deleted, so a deleted student can be
+	// enrolled again. MySQL has no partial indexes, hence this generated column (NULL once deleted)
+	// carries the unique index. Read only: the database maintains it.
+	ActiveEmail *string `gorm:"->;type:varchar(150) GENERATED ALWAYS AS (IF(deleted_at IS NULL, email, NULL)) STORED;uniqueIndex"`
 }
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// TimetableSlot is a weekly recurring class of a section, e.g. section 3 on Mondays from 09:00 to
+// 10:30 in room B12. Concrete ClassSessions are generated from it for the days it is valid.
+type TimetableSlot struct {
+	ID        uint    `gorm:"primarykey"`
+	SectionID uint    `gorm:"not null;index"`
+	Section   Section `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Weekday   int     `gorm:"type:tinyint;not null"` // as time.Weekday, 0 is Sunday
+	// Wall clock times, "15:04"
+	StartTime string `gorm:"type:char(5);not null"`
+	EndTime   string `gorm:"type:char(5);not null"`
+	Room      string `gorm:"type:varchar(50)"`
+	TeacherID *uint  `gorm:"index"`
+	Teacher   *User  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	// Grace period: checking in more than this many minutes after StartTime is late
+	LateAfterMinutes int `gorm:"not null;default:0"`
+	// First and last day (inclusive) sessions are generated for; no ValidUntil means open ended
+	ValidFrom  time.Time  `gorm:"type:date;not null"`
+	ValidUntil *time.Time `gorm:"type:date"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// ClassSession is one occurrence of a class: when it takes place and so when its enrolled
+// students are expected to attend. A section has at most one session starting at a given time.
+// Cancelled sessions are kept, so regenerating the timetable does not bring them back.
+type ClassSession struct {
+	ID        uint           `gorm:"primarykey"`
+	SectionID uint           `gorm:"not null;uniqueIndex:idx_session_section_start"`
+	Section   Section        `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	SlotID    *uint          `gorm:"index"` // slot it was generated from
+	Slot      *TimetableSlot `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Date      time.Time      `gorm:"type:date;not null;uniqueIndex:idx_session_section_start;index"`
+	StartTime string         `gorm:"type:char(5);not null;uniqueIndex:idx_session_section_start"`
+	EndTime   string         `gorm:"type:char(5);not null"`
+	Room      string         `gorm:"type:varchar(50)"`
+	TeacherID *uint          `gorm:"index"`
+	Teacher   *User          `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Cancelled bool           `gorm:"not null;default:false"`
+	// Grace period copied from the slot, see TimetableSlot.LateAfterMinutes
+	LateAfterMinutes int `gorm:"not null;default:0"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
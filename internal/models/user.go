@@ -0,0 +1,22 @@
+package models
+
+import "gorm.io/gorm"
+
+// Roles a user can have
+const (
+	RoleAdmin   = "admin"
+	RoleTeacher = "teacher"
+	RoleStudent = "student"
+)
+
+// User is an account allowed to call the API.
+type User struct {
+	gorm.Model
+	Email        string `gorm:"type:varchar(150);unique;not null"`
+	PasswordHash string `gorm:"type:varchar(255);not null"` // bcrypt, never the plain password
+	Role         string `gorm:"type:varchar(20);not null"`
+
+	// Set for student accounts: the student record the user may read
+	StudentID *uint    `gorm:"index"`
+	Student   *Student `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+}
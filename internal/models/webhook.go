@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// webhook event types
+const (
+	EventStudentCreated    = "student.created"
+	EventStudentUpdated    = "student.updated"
+	EventStudentDeleted    = "student.deleted"  // soft delete
+	EventStudentRestored   = "student.restored" // undo of a soft delete
+	EventStudentPurged     = "student.purged"   // permanent delete
+	EventAttendanceMarked  = "attendance.marked"
+	EventAttendanceUpdated = "attendance.updated" // status corrected or check-out recorded
+	EventAttendanceDeleted = "attendance.deleted"
+)
+
+// WebhookEvents lists every event a subscription can ask for
+var WebhookEvents = []string{
+	EventStudentCreated, EventStudentUpdated, EventStudentDeleted, EventStudentRestored, EventStudentPurged,
+	EventAttendanceMarked, EventAttendanceUpdated, EventAttendanceDeleted,
+}
+
+// webhook delivery states
+const (
+	DeliveryPending   = "pending" // waiting for its first or next attempt
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed" // out of attempts
+)
+
+// WebhookSubscription sends the events it lists to URL, signed with Secret
+type WebhookSubscription struct {
+	ID        uint     `gorm:"primarykey"`
+	URL       string   `gorm:"type:varchar(500);not null"`
+	Secret    string   `gorm:"type:varchar(100);not null"`
+	Events    []string `gorm:"type:json;serializer:json;not null"`
+	Active    bool     `gorm:"not null;default:true"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// WebhookDelivery is one event on its way to one subscription, kept as the delivery log.
+// Failed attempts are retried with exponential backoff until MaxAttempts.
+type WebhookDelivery struct {
+	ID             uint                `gorm:"primarykey"`
+	SubscriptionID uint                `gorm:"not null;uniqueIndex:idx_webhook_delivery_event"`
+	Subscription   WebhookSubscription `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	EventID        string              `gorm:"type:varchar(32);not null;uniqueIndex:idx_webhook_delivery_event"` // same for every subscription the event went to, queued once per subscription
+	Event          string              `gorm:"type:varchar(50);not null"`
+	Payload        string              `gorm:"type:json;not null"` // the body sent, as signed
+	Status         string              `gorm:"type:varchar(20);not null;default:pending;index:idx_webhook_delivery_due"`
+	Attempts       int                 `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time           `gorm:"index:idx_webhook_delivery_due"`
+	ResponseStatus int                 // HTTP status of the last attempt, 0 if there was no response
+	LastError      string              `gorm:"type:varchar(500)"`
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
@@ -0,0 +1,109 @@
+// Package notify delivers attendance alerts to people outside the API: by email, to a webhook or to the log.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Alert tells that a student crossed an attendance threshold
+type Alert struct {
+	Kind         string `json:"kind"` // "low_attendance" or "consecutive_absences"
+	StudentID    uint   `json:"student_id"`
+	StudentName  string `json:"student_name"`
+	StudentEmail string `json:"student_email"`
+	Department   string `json:"department"`
+	// attendance percentage over the window, excused records left out
+	Percentage float64 `json:"percentage"`
+	// school days missed in a row, up to the end of the window
+	ConsecutiveAbsences int       `json:"consecutive_absences"`
+	From                time.Time `json:"from"`
+	To                  time.Time `json:"to"`
+	Message             string    `json:"message"`
+}
+
+// Notifier sends an alert somewhere. Implementations must be safe to call again after an error:
+// an alert that could not be delivered is retried on the next run.
+type Notifier interface {
+	Notify(alert Alert) error
+}
+
+// Channel is a notifier with the name its deliveries are tracked under, e.g. "email"
+type Channel struct {
+	Name string
+	Notifier
+}
+
+// Multi sends every alert through each channel, even when one of them fails
+type Multi []Channel
+
+func (m Multi) Notify(alert Alert) error {
+	_, err := m.NotifyOnly(alert, nil)
+	return err
+}
+
+// NotifyOnly sends the alert through the named channels, or through all of them when names is nil,
+// and returns the names of the channels that failed together with their errors.
+// Names of channels no longer configured are ignored.
+func (m Multi) NotifyOnly(alert Alert, names []string) ([]string, error) {
+	var failed []string
+	var errs []error
+	for _, c := range m {
+		if names != nil && !slices.Contains(names, c.Name) {
+			continue
+		}
+		if err := c.Notify(alert); err != nil {
+			failed = append(failed, c.Name)
+			errs = append(errs, fmt.Errorf("%s: %w", c.Name, err))
+		}
+	}
+	return failed, errors.Join(errs...)
+}
+
+// LogNotifier writes alerts to the application log; used when nothing else is configured
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(alert Alert) error {
+	log.Printf("⚠️ %s\n", alert.Message)
+	return nil
+}
+
+// FromEnv builds the notifiers configured in the environment:
+//   - ALERT_SMTP_ADDR (host:port), ALERT_EMAIL_FROM and ALERT_EMAIL_TO (comma separated) send emails,
+//     authenticated with ALERT_SMTP_USERNAME and ALERT_SMTP_PASSWORD when set
+//   - ALERT_WEBHOOK_URL posts every alert as JSON
+//
+// Without either, alerts are only logged.
+func FromEnv() (Multi, error) {
+	var notifiers Multi
+	if addr := os.Getenv("ALERT_SMTP_ADDR"); addr != "" {
+		from, to := os.Getenv("ALERT_EMAIL_FROM"), splitList(os.Getenv("ALERT_EMAIL_TO"))
+		if from == "" || len(to) == 0 {
+			return nil, fmt.Errorf("ALERT_SMTP_ADDR needs ALERT_EMAIL_FROM and ALERT_EMAIL_TO")
+		}
+		notifiers = append(notifiers, Channel{"email", NewSMTPNotifier(addr, os.Getenv("ALERT_SMTP_USERNAME"), os.Getenv("ALERT_SMTP_PASSWORD"), from, to)})
+	}
+	if url := os.Getenv("ALERT_WEBHOOK_URL"); url != "" {
+		notifiers = append(notifiers, Channel{"webhook", NewWebhookNotifier(url)})
+	}
+
+	if len(notifiers) == 0 {
+		return Multi{{"log", LogNotifier{}}}, nil
+	}
+	return notifiers, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,175 @@
+package notify_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hrms_backend/internal/notify"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var alert = notify.Alert{
+	Kind:                "consecutive_absences",
+	StudentID:           7,
+	StudentName:         "Alice",
+	Department:          "IT",
+	ConsecutiveAbsences: 3,
+	From:                time.Date(2025, 11, 13, 0, 0, 0, 0, time.UTC),
+	To:                  time.Date(2025, 12, 12, 0, 0, 0, 0, time.UTC),
+	Message:             "Student Alice (ID: 7) was absent 3 school days in a row up to 2025-12-12.",
+}
+
+// smtpStandIn accepts one mail on a local port and hands back its envelope and data
+func smtpStandIn(t *testing.T) (string, <-chan []string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r, w := bufio.NewReader(conn), bufio.NewWriter(conn)
+		reply := func(line string) { w.WriteString(line + "\r\n"); w.Flush() }
+
+		var lines []string
+		reply("220 localhost ready")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RCPT"):
+				lines = append(lines, strings.TrimSpace(line))
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 end with .")
+				for {
+					data, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if data == ".\r\n" {
+						break
+					}
+					lines = append(lines, strings.TrimRight(data, "\r\n"))
+				}
+				reply("250 queued")
+				received <- lines
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+	return ln.Addr().String(), received
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	addr, received := smtpStandIn(t)
+	n := notify.NewSMTPNotifier(addr, "", "", "alerts@school.test", []string{"office@school.test"})
+
+	assert.NoError(t, n.Notify(alert))
+	lines := <-received
+	assert.Contains(t, lines, "MAIL FROM:<alerts@school.test>")
+	assert.Contains(t, lines, "RCPT TO:<office@school.test>")
+	assert.Contains(t, lines, "Subject: Attendance alert: Alice")
+	assert.Contains(t, lines, alert.Message)
+	assert.Contains(t, lines, "Consecutive absences: 3")
+
+	// A line break in the name stays inside the subject
+	addr, received = smtpStandIn(t)
+	n = notify.NewSMTPNotifier(addr, "", "", "alerts@school.test", []string{"office@school.test"})
+	injected := alert
+	injected.StudentName = "Eve\r\nBcc: everyone@school.test"
+	assert.NoError(t, n.Notify(injected))
+	lines = <-received
+	assert.Contains(t, lines, "Subject: Attendance alert: =?utf-8?q?Eve=0D=0ABcc:_everyone@school.test?=")
+	assert.NotContains(t, lines, "Bcc: everyone@school.test")
+
+	// Server gone
+	n = notify.NewSMTPNotifier("127.0.0.1:1", "", "", "alerts@school.test", []string{"office@school.test"})
+	assert.Error(t, n.Notify(alert))
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got notify.Alert
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	n := notify.NewWebhookNotifier(server.URL)
+
+	// Case 1: Delivered as JSON
+	assert.NoError(t, n.Notify(alert))
+	assert.Equal(t, alert, got)
+
+	// Case 2: Receiver refuses it
+	status = http.StatusInternalServerError
+	err := n.Notify(alert)
+	assert.ErrorContains(t, err, "500")
+}
+
+type failing struct{ calls *int }
+
+func (f failing) Notify(notify.Alert) error {
+	*f.calls++
+	return errors.New("down")
+}
+
+func TestMulti(t *testing.T) {
+	calls := 0
+	m := notify.Multi{{"email", failing{&calls}}, {"log", notify.LogNotifier{}}, {"webhook", failing{&calls}}}
+	assert.Error(t, m.Notify(alert))
+	assert.Equal(t, 2, calls)
+
+	// Only the named channels, reporting the ones that failed
+	failed, err := m.NotifyOnly(alert, []string{"log", "webhook", "sms"})
+	assert.ErrorContains(t, err, "webhook: down")
+	assert.Equal(t, []string{"webhook"}, failed)
+	assert.Equal(t, 3, calls)
+}
+
+func TestFromEnv(t *testing.T) {
+	// Nothing configured: log only
+	n, err := notify.FromEnv()
+	assert.NoError(t, err)
+	if assert.Len(t, n, 1) {
+		assert.Equal(t, "log", n[0].Name)
+	}
+
+	// Email without recipients
+	t.Setenv("ALERT_SMTP_ADDR", "localhost:25")
+	_, err = notify.FromEnv()
+	assert.Error(t, err)
+
+	// Email and webhook
+	t.Setenv("ALERT_EMAIL_FROM", "alerts@school.test")
+	t.Setenv("ALERT_EMAIL_TO", "office@school.test, head@school.test")
+	t.Setenv("ALERT_WEBHOOK_URL", "http://localhost/hook")
+	n, err = notify.FromEnv()
+	assert.NoError(t, err)
+	assert.Len(t, n, 2)
+}
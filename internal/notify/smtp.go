@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPNotifier emails every alert to a fixed list of recipients, e.g. the school office
+type SMTPNotifier struct {
+	addr string // host:port
+	auth smtp.Auth
+	from string
+	to   []string
+}
+
+// NewSMTPNotifier sends through the server at addr, logging in with PLAIN auth when username is set.
+// Go only sends PLAIN credentials over TLS or to localhost.
+func NewSMTPNotifier(addr, username, password, from string, to []string) *SMTPNotifier {
+	n := &SMTPNotifier{addr: addr, from: from, to: to}
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		n.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return n
+}
+
+func (n *SMTPNotifier) Notify(alert Alert) error {
+	if err := smtp.SendMail(n.addr, n.auth, n.from, n.to, n.message(alert)); err != nil {
+		return fmt.Errorf("email alert for student %d: %w", alert.StudentID, err)
+	}
+	return nil
+}
+
+// message builds a plain text email with CRLF line endings. The student's name goes into the
+// subject as an RFC 2047 encoded word when needed, so a line break in it cannot start a new header.
+func (n *SMTPNotifier) message(alert Alert) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&b, "Subject: Attendance alert: %s\r\n", mime.QEncoding.Encode("utf-8", alert.StudentName))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+
+	fmt.Fprintf(&b, "%s\r\n\r\n", alert.Message)
+	fmt.Fprintf(&b, "Student: %s (ID %d)\r\n", alert.StudentName, alert.StudentID)
+	if alert.StudentEmail != "" {
+		fmt.Fprintf(&b, "Email: %s\r\n", alert.StudentEmail)
+	}
+	if alert.Department != "" {
+		fmt.Fprintf(&b, "Department: %s\r\n", alert.Department)
+	}
+	fmt.Fprintf(&b, "Period: %s to %s\r\n", alert.From.Format("2006-01-02"), alert.To.Format("2006-01-02"))
+	fmt.Fprintf(&b, "Attendance: %.2f%%\r\n", alert.Percentage)
+	fmt.Fprintf(&b, "Consecutive absences: %d\r\n", alert.ConsecutiveAbsences)
+	return []byte(b.String())
+}
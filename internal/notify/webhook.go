@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// webhookTimeout bounds a single delivery, so a slow receiver cannot hold up the job
+const webhookTimeout = 10 * time.Second
+
+// WebhookNotifier posts every alert as a JSON object to a URL, e.g. a chat integration
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: &http.Client{Timeout: webhookTimeout}}
+}
+
+// Notify fails unless the receiver answers with a 2xx status
+func (n *WebhookNotifier) Notify(alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook alert for student %d: %w", alert.StudentID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook alert for student %d: receiver answered %s", alert.StudentID, resp.Status)
+	}
+	return nil
+}
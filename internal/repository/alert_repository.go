@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"hrms_backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AlertRepository interface {
+	GetActive() ([]models.AbsenceAlert, error)
+	Activate(alert *models.AbsenceAlert) error
+	SetPending(id uint, pending []string) error
+	Resolve(ids []uint, at time.Time) error
+}
+
+type alertRepo struct {
+	db *gorm.DB
+}
+
+func NewAlertRepository(db *gorm.DB) AlertRepository {
+	return &alertRepo{db: db}
+}
+
+// GetActive returns the alerts sent for a threshold the student is still over
+func (r *alertRepo) GetActive() ([]models.AbsenceAlert, error) {
+	var alerts []models.AbsenceAlert
+	err := r.db.Where("active = ?", true).Order("id").Find(&alerts).Error
+	return alerts, err
+}
+
+// Activate records that alert was sent, reusing the row of an earlier, resolved alert of the same
+// student and kind
+func (r *alertRepo) Activate(alert *models.AbsenceAlert) error {
+	alert.Active = true
+	alert.ResolvedAt = nil
+	updates := clause.Assignments(map[string]any{
+		"active":               true,
+		"percentage":           alert.Percentage,
+		"consecutive_absences": alert.ConsecutiveAbsences,
+		"sent_at":              alert.SentAt,
+		"times_sent":           gorm.Expr("times_sent + 1"),
+		"resolved_at":          nil,
+		"updated_at":           time.Now(),
+	})
+	// the inserted value, as serialized to JSON
+	updates = append(updates, clause.AssignmentColumns([]string{"pending"})...)
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "student_id"}, {Name: "kind"}},
+		DoUpdates: updates,
+	}).Create(alert).Error
+}
+
+// SetPending replaces the channels an active alert still has to be delivered through
+func (r *alertRepo) SetPending(id uint, pending []string) error {
+	return r.db.Model(&models.AbsenceAlert{ID: id}).Select("pending").Updates(&models.AbsenceAlert{Pending: pending}).Error
+}
+
+// Resolve marks the alerts as no longer active
+func (r *alertRepo) Resolve(ids []uint, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.AbsenceAlert{}).Where("id IN ?", ids).
+		Updates(map[string]any{"active": false, "resolved_at": at}).Error
+}
@@ -1,20 +1,63 @@
 package repository
 
 import (
+	"errors"
 	"hrms_backend/internal/models"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// AttendanceFilter narrows down List; zero values mean "no filter".
+type AttendanceFilter struct {
+	StudentID    uint
+	DepartmentID uint
+	SectionID    uint
+	Status       string
+	From         time.Time // inclusive
+	To           time.Time // inclusive
+	// Column to order by, "-" prefix for descending. Must be one of attendanceSortColumns.
+	Sort   string
+	Limit  int // 0 returns every match
+	Offset int
+	// Encoded cursor from AttendanceCursor: only records after it are returned and Offset is ignored
+	After string
+}
+
+// whitelisted sort keys -> SQL columns
+var attendanceSortColumns = map[string]sortColumn[models.Attendance]{
+	"date":       {"attendances.date", func(a *models.Attendance) any { return a.Date }},
+	"student_id": {"attendances.student_id", func(a *models.Attendance) any { return a.StudentID }},
+	"status":     {"attendances.status", func(a *models.Attendance) any { return a.Status }},
+	"created_at": {"attendances.created_at", func(a *models.Attendance) any { return a.CreatedAt }},
+}
+
+// AttendanceCursor returns the cursor to list the records following a in the given sort
+func AttendanceCursor(sort string, a *models.Attendance) string {
+	return cursorAfter(attendanceSortColumns, sort, "-date", a, a.ID)
+}
+
 type AttendanceRepository interface {
 	Create(attendance *models.Attendance) error
-	GetAttendanceByStudentID(studentID uint) ([]models.Attendance, error)
-	GetAttendanceSince(date time.Time) ([]models.Attendance, error)
+	CreateBatch(records []models.Attendance, audit *models.AuditLog) error
+	FirstOrCreate(attendance *models.Attendance, audit *models.AuditLog) (bool, error)
+	GetByIdempotencyKey(key string) (*models.Attendance, error)
+	GetByID(id uint) (*models.Attendance, error)
+	UpdateStatus(attendance *models.Attendance, correction *models.AttendanceCorrection, audit *models.AuditLog) error
+	SetCheckOut(attendance *models.Attendance, audit *models.AuditLog) error
+	Delete(attendance *models.Attendance, correction *models.AttendanceCorrection, audit *models.AuditLog) error
+	GetCorrections(attendanceID uint) ([]models.AttendanceCorrection, error)
+	List(filter AttendanceFilter) ([]models.Attendance, int64, error)
+	GetByStudentsAndDate(studentIDs []uint, date time.Time) ([]models.Attendance, error)
+	GetAttendanceBetween(from, to time.Time) ([]models.Attendance, error)
+	GetUnmarkedSessions(from, to time.Time) ([]models.Attendance, error)
+	GetSessionsOn(sectionID uint, date time.Time) ([]models.ClassSession, error)
 }
 
 type attendanceRepo struct {
-	db *gorm.DB
+	db   *gorm.DB
+	lock bool // lock the rows read, see Repos
 }
 
 func NewAttendanceRepository(db *gorm.DB) AttendanceRepository {
@@ -25,19 +68,267 @@ func (r *attendanceRepo) Create(attendance *models.Attendance) error {
 	return r.db.Create(attendance).Error
 }
 
-// GetAttendanceByStudentID fetches attendance and Preloads the Student details
-func (r *attendanceRepo) GetAttendanceByStudentID(studentID uint) ([]models.Attendance, error) {
-	var attendanceList []models.Attendance
+// CreateBatch inserts all records in a single transaction: either every row is stored or none is.
+// Each record gets its own audit entry, based on audit.
+func (r *attendanceRepo) CreateBatch(records []models.Attendance, audit *models.AuditLog) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(records, 100).Error; err != nil {
+			return err
+		}
+		for i := range records {
+			if err := writeAudit(tx, audit, records[i].ID, nil, &records[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FirstOrCreate inserts the record unless one already exists for the same student, day and section.
+// It reports whether a new row was created; otherwise attendance is replaced with the stored row.
+// Relies on the unique (student_id, date, section) index so concurrent calls cannot both insert.
+// The audit entry is only written when a row is created. If the insert only conflicts on the
+// idempotency key, i.e. the key is already used by a record of another student, day or section,
+// it fails with gorm.ErrDuplicatedKey.
+func (r *attendanceRepo) FirstOrCreate(attendance *models.Attendance, audit *models.AuditLog) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(attendance)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			created = true
+			return writeAudit(tx, audit, attendance.ID, nil, attendance)
+		}
+
+		var existing models.Attendance
+		// <=> also matches NULL, i.e. whole day records. A locking read sees the row committed by
+		// the concurrent insert we conflicted with, even if this transaction's snapshot predates it.
+		err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+			Where("student_id = ? AND date = ? AND section_id <=> ?", attendance.StudentID, attendance.Date, attendance.SectionID).
+			First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) && attendance.IdempotencyKey != nil {
+			return gorm.ErrDuplicatedKey
+		}
+		if err != nil {
+			return err
+		}
+		*attendance = existing
+		return nil
+	})
+	return created, err
+}
+
+// GetByIdempotencyKey finds the record created by a request carrying the given key
+func (r *attendanceRepo) GetByIdempotencyKey(key string) (*models.Attendance, error) {
+	var attendance models.Attendance
+	err := forUpdate(r.db, r.lock).Where("idempotency_key = ?", key).First(&attendance).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attendance, nil
+}
 
-	// Uses Preload to fetch the associated Student entity in an optimized way
-	err := r.db.Preload("Student").Where("student_id = ?", studentID).Find(&attendanceList).Error
+func (r *attendanceRepo) GetByID(id uint) (*models.Attendance, error) {
+	var attendance models.Attendance
+	err := forUpdate(r.db, r.lock).Preload("Student").First(&attendance, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attendance, nil
+}
 
-	return attendanceList, err
+// UpdateStatus saves the new status (and late minutes) of attendance and its correction and audit entries in one transaction
+func (r *attendanceRepo) UpdateStatus(attendance *models.Attendance, correction *models.AttendanceCorrection, audit *models.AuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var before models.Attendance
+		if err := tx.First(&before, attendance.ID).Error; err != nil {
+			return err
+		}
+		err := tx.Model(attendance).Updates(map[string]any{"status": attendance.Status, "late_minutes": attendance.LateMinutes}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(correction).Error; err != nil {
+			return err
+		}
+		after := before
+		after.Status, after.LateMinutes = attendance.Status, attendance.LateMinutes
+		return writeAudit(tx, audit, attendance.ID, &before, &after)
+	})
 }
 
-func (r *attendanceRepo) GetAttendanceSince(date time.Time) ([]models.Attendance, error) {
+// SetCheckOut saves the check-out time of attendance and its audit entry in one transaction
+func (r *attendanceRepo) SetCheckOut(attendance *models.Attendance, audit *models.AuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var before models.Attendance
+		if err := tx.First(&before, attendance.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(attendance).Update("check_out_at", attendance.CheckOutAt).Error; err != nil {
+			return err
+		}
+		after := before
+		after.CheckOutAt = attendance.CheckOutAt
+		return writeAudit(tx, audit, attendance.ID, &before, &after)
+	})
+}
+
+// Delete permanently removes attendance, recording the correction and audit entries in the same transaction.
+// The row is hard deleted so the (student_id, date) slot can be marked again;
+// the correction entry keeps what it used to be.
+func (r *attendanceRepo) Delete(attendance *models.Attendance, correction *models.AttendanceCorrection, audit *models.AuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var before models.Attendance
+		if err := tx.First(&before, attendance.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Delete(&models.Attendance{}, attendance.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(correction).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, audit, attendance.ID, &before, nil)
+	})
+}
+
+// GetCorrections returns the change history of a record, oldest first
+func (r *attendanceRepo) GetCorrections(attendanceID uint) ([]models.AttendanceCorrection, error) {
+	var corrections []models.AttendanceCorrection
+	err := r.db.Where("attendance_id = ?", attendanceID).Order("id").Find(&corrections).Error
+	return corrections, err
+}
+
+// List returns one page of records matching the filter plus the total number of matches
+func (r *attendanceRepo) List(filter AttendanceFilter) ([]models.Attendance, int64, error) {
+	query := r.db.Model(&models.Attendance{})
+	if filter.StudentID != 0 {
+		query = query.Where("attendances.student_id = ?", filter.StudentID)
+	}
+	if filter.SectionID != 0 {
+		query = query.Where("attendances.section_id = ?", filter.SectionID)
+	}
+	if filter.Status != "" {
+		query = query.Where("attendances.status = ?", filter.Status)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("attendances.date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("attendances.date <= ?", filter.To)
+	}
+	if filter.DepartmentID != 0 {
+		query = query.Joins("JOIN students ON students.id = attendances.student_id").
+			Where("students.department_id = ?", filter.DepartmentID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// default: newest first, id as tie breaker so pages are stable
+	query, err := orderByKeyset(query.Preload("Student"), attendanceSortColumns, filter.Sort, "-date", "attendances.id", filter.After)
+	if err != nil {
+		return nil, 0, err
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+		if filter.After == "" {
+			query = query.Offset(filter.Offset)
+		}
+	}
+
 	var records []models.Attendance
-	// Preload Student to get names for the report
-	err := r.db.Preload("Student").Where("date >= ?", date).Find(&records).Error
+	err = query.Find(&records).Error
+	return records, total, err
+}
+
+// GetByStudentsAndDate returns the whole day records (no section) already stored for the given students on one day
+func (r *attendanceRepo) GetByStudentsAndDate(studentIDs []uint, date time.Time) ([]models.Attendance, error) {
+	var records []models.Attendance
+	if len(studentIDs) == 0 {
+		return records, nil
+	}
+	err := forUpdate(r.db, r.lock).Where("student_id IN ? AND date = ? AND section_id IS NULL", studentIDs, date).Find(&records).Error
 	return records, err
 }
+
+// GetAttendanceBetween returns the records dated within [from, to], both inclusive
+func (r *attendanceRepo) GetAttendanceBetween(from, to time.Time) ([]models.Attendance, error) {
+	var records []models.Attendance
+	err := r.db.Preload("Student.Department").Where("date BETWEEN ? AND ?", from, to).Find(&records).Error
+	return records, err
+}
+
+// GetUnmarkedSessions returns the attendance that was expected within [from, to] but never marked:
+// one unsaved "absent" record (ID 0) per enrolled student, section and day with a session that
+// was not cancelled and no record for that section or the whole day, "excused" instead on the days of an approved
+// leave request. Students only count from the day they enrolled, and deleted students not at all.
+// Student (with Department) is loaded like in GetAttendanceBetween.
+func (r *attendanceRepo) GetUnmarkedSessions(from, to time.Time) ([]models.Attendance, error) {
+	var rows []struct {
+		StudentID uint
+		SectionID uint
+		Date      time.Time
+		OnLeave   bool
+	}
+	err := r.db.Table("class_sessions").
+		Select("DISTINCT enrollments.student_id, class_sessions.section_id, class_sessions.date, leave_requests.id IS NOT NULL AS on_leave").
+		Joins("JOIN enrollments ON enrollments.section_id = class_sessions.section_id AND DATE(enrollments.created_at) <= class_sessions.date").
+		Joins("JOIN students ON students.id = enrollments.student_id AND students.deleted_at IS NULL").
+		Joins(`LEFT JOIN attendances ON attendances.student_id = enrollments.student_id
+			AND attendances.date = class_sessions.date
+			AND (attendances.section_id = class_sessions.section_id OR attendances.section_id IS NULL)`).
+		Joins(`LEFT JOIN leave_requests ON leave_requests.student_id = enrollments.student_id
+			AND leave_requests.status = ? AND class_sessions.date BETWEEN leave_requests.start_date AND leave_requests.end_date`, models.LeaveApproved).
+		Where("class_sessions.date BETWEEN ? AND ? AND class_sessions.cancelled = ? AND attendances.id IS NULL", from, to, false).
+		Order("class_sessions.date, enrollments.student_id, class_sessions.section_id").
+		Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.StudentID)
+	}
+	var students []models.Student
+	if err := r.db.Preload("Department").Where("id IN ?", ids).Find(&students).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Student, len(students))
+	for _, st := range students {
+		byID[st.ID] = st
+	}
+
+	records := make([]models.Attendance, 0, len(rows))
+	for _, row := range rows {
+		sectionID := row.SectionID
+		status := "absent"
+		if row.OnLeave {
+			status = "excused"
+		}
+		records = append(records, models.Attendance{
+			StudentID: row.StudentID,
+			Student:   byID[row.StudentID],
+			Date:      row.Date,
+			Status:    status,
+			SectionID: &sectionID,
+		})
+	}
+	return records, nil
+}
+
+// GetSessionsOn returns the sessions of a section on one day that are not cancelled, by start time
+func (r *attendanceRepo) GetSessionsOn(sectionID uint, date time.Time) ([]models.ClassSession, error) {
+	var sessions []models.ClassSession
+	err := forUpdate(r.db, r.lock).Where("section_id = ? AND date = ? AND cancelled = ?", sectionID, date, false).
+		Order("start_time").Find(&sessions).Error
+	return sessions, err
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"encoding/json"
+	"hrms_backend/internal/models"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// AuditFilter narrows down List; zero values mean "no filter".
+type AuditFilter struct {
+	EntityType string
+	EntityID   uint
+	ActorID    uint
+	Limit      int
+	Offset     int
+}
+
+type AuditRepository interface {
+	List(filter AuditFilter) ([]models.AuditLog, int64, error)
+}
+
+type auditRepo struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepo{db: db}
+}
+
+// List returns one page of entries matching the filter, newest first, plus the total number of matches
+func (r *auditRepo) List(filter AuditFilter) ([]models.AuditLog, int64, error) {
+	query := r.db.Model(&models.AuditLog{})
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []models.AuditLog
+	err := query.Order("id DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&entries).Error
+	return entries, total, err
+}
+
+// fields left out of audit snapshots: bookkeeping columns and preloaded associations
+var auditIgnoredFields = map[string]bool{
+	"ID": true, "CreatedAt": true, "UpdatedAt": true, "DeletedAt": true, "Student": true, "Department": true, "ActiveEmail": true,
+	"Section": true, "SectionKey": true,
+}
+
+// writeAudit completes entry with the entity ID and the before/after state and stores it with tx,
+// so it commits or rolls back together with the change it describes, and queues the change in
+// the outbox the same way (see writeOutbox).
+// before is nil for creations and after is nil for deletions. A nil entry means "not audited"
+// and an update that changed nothing is not recorded.
+func writeAudit(tx *gorm.DB, entry *models.AuditLog, entityID uint, before, after any) error {
+	if entry == nil {
+		return nil
+	}
+
+	beforeFields, err := auditSnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterFields, err := auditSnapshot(after)
+	if err != nil {
+		return err
+	}
+	// on update keep only what changed
+	if beforeFields != nil && afterFields != nil {
+		for column, value := range beforeFields {
+			if reflect.DeepEqual(value, afterFields[column]) {
+				delete(beforeFields, column)
+				delete(afterFields, column)
+			}
+		}
+		if len(beforeFields) == 0 && len(afterFields) == 0 {
+			return nil
+		}
+	}
+
+	record := *entry
+	record.EntityID = entityID
+	if record.Before, err = auditJSON(beforeFields); err != nil {
+		return err
+	}
+	if record.After, err = auditJSON(afterFields); err != nil {
+		return err
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return err
+	}
+	if after == nil {
+		return writeOutbox(tx, record, before)
+	}
+	return writeOutbox(tx, record, after)
+}
+
+// auditSnapshot turns a model into column name -> value, leaving out auditIgnoredFields
+func auditSnapshot(model any) (map[string]any, error) {
+	if model == nil || reflect.ValueOf(model).IsNil() {
+		return nil, nil
+	}
+	raw, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	naming := schema.NamingStrategy{}
+	snapshot := make(map[string]any, len(fields))
+	for name, value := range fields {
+		if !auditIgnoredFields[name] {
+			snapshot[naming.ColumnName("", name)] = value
+		}
+	}
+	return snapshot, nil
+}
+
+func auditJSON(fields map[string]any) (*string, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	s := string(raw)
+	return &s, nil
+}
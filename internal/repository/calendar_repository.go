@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"hrms_backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CalendarRepository interface {
+	CreateTerm(term *models.Term) error
+	GetTerms() ([]models.Term, error)
+	GetTermByID(id uint) (*models.Term, error)
+	UpdateTerm(term *models.Term) error
+	DeleteTerm(id uint) error
+	GetTermsBetween(from, to time.Time) ([]models.Term, error)
+	CountTerms() (int64, error)
+
+	CreateClosure(closure *models.Closure) error
+	GetClosuresBetween(from, to time.Time) ([]models.Closure, error)
+	GetClosureByID(id uint) (*models.Closure, error)
+	UpdateClosure(closure *models.Closure) error
+	DeleteClosure(id uint) error
+}
+
+type calendarRepo struct {
+	db *gorm.DB
+}
+
+func NewCalendarRepository(db *gorm.DB) CalendarRepository {
+	return &calendarRepo{db: db}
+}
+
+func (r *calendarRepo) CreateTerm(term *models.Term) error {
+	return r.db.Create(term).Error
+}
+
+// GetTerms returns every term in chronological order
+func (r *calendarRepo) GetTerms() ([]models.Term, error) {
+	var terms []models.Term
+	err := r.db.Order("start_date, id").Find(&terms).Error
+	return terms, err
+}
+
+func (r *calendarRepo) GetTermByID(id uint) (*models.Term, error) {
+	var term models.Term
+	if err := r.db.First(&term, id).Error; err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+// UpdateTerm saves the term's name and dates
+func (r *calendarRepo) UpdateTerm(term *models.Term) error {
+	res := r.db.Model(term).Select("name", "start_date", "end_date").Updates(term)
+	if res.Error == nil && res.RowsAffected == 0 {
+		// MySQL reports 0 for unchanged values too, so tell the two apart
+		return r.db.Select("id").First(&models.Term{}, term.ID).Error
+	}
+	return res.Error
+}
+
+func (r *calendarRepo) DeleteTerm(id uint) error {
+	res := r.db.Delete(&models.Term{}, id)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return res.Error
+}
+
+// GetTermsBetween returns the terms overlapping [from, to]
+func (r *calendarRepo) GetTermsBetween(from, to time.Time) ([]models.Term, error) {
+	var terms []models.Term
+	err := r.db.Where("start_date <= ? AND end_date >= ?", to, from).Order("start_date, id").Find(&terms).Error
+	return terms, err
+}
+
+func (r *calendarRepo) CountTerms() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Term{}).Count(&count).Error
+	return count, err
+}
+
+func (r *calendarRepo) CreateClosure(closure *models.Closure) error {
+	return r.db.Create(closure).Error
+}
+
+// GetClosuresBetween returns the closures overlapping [from, to] in chronological order.
+// Zero bounds are open.
+func (r *calendarRepo) GetClosuresBetween(from, to time.Time) ([]models.Closure, error) {
+	query := r.db.Order("start_date, id")
+	if !to.IsZero() {
+		query = query.Where("start_date <= ?", to)
+	}
+	if !from.IsZero() {
+		query = query.Where("end_date >= ?", from)
+	}
+	var closures []models.Closure
+	err := query.Find(&closures).Error
+	return closures, err
+}
+
+func (r *calendarRepo) GetClosureByID(id uint) (*models.Closure, error) {
+	var closure models.Closure
+	if err := r.db.First(&closure, id).Error; err != nil {
+		return nil, err
+	}
+	return &closure, nil
+}
+
+// UpdateClosure saves the closure's kind, name and dates
+func (r *calendarRepo) UpdateClosure(closure *models.Closure) error {
+	res := r.db.Model(closure).Select("kind", "name", "start_date", "end_date").Updates(closure)
+	if res.Error == nil && res.RowsAffected == 0 {
+		// MySQL reports 0 for unchanged values too, so tell the two apart
+		return r.db.Select("id").First(&models.Closure{}, closure.ID).Error
+	}
+	return res.Error
+}
+
+func (r *calendarRepo) DeleteClosure(id uint) error {
+	res := r.db.Delete(&models.Closure{}, id)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return res.Error
+}
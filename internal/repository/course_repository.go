@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"hrms_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type CourseRepository interface {
+	Create(course *models.Course) error
+	GetAll() ([]models.Course, error)
+	GetByID(id uint) (*models.Course, error)
+	Update(course *models.Course) error
+	Delete(id uint) error
+}
+
+type courseRepo struct {
+	db *gorm.DB
+}
+
+func NewCourseRepository(db *gorm.DB) CourseRepository {
+	return &courseRepo{db: db}
+}
+
+func (r *courseRepo) Create(course *models.Course) error {
+	return r.db.Create(course).Error
+}
+
+// GetAll returns every course ordered by code
+func (r *courseRepo) GetAll() ([]models.Course, error) {
+	var courses []models.Course
+	err := r.db.Order("code").Find(&courses).Error
+	return courses, err
+}
+
+func (r *courseRepo) GetByID(id uint) (*models.Course, error) {
+	var course models.Course
+	if err := r.db.First(&course, id).Error; err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+// Update saves the course's code and name
+func (r *courseRepo) Update(course *models.Course) error {
+	res := r.db.Model(course).Select("code", "name").Updates(course)
+	if res.Error == nil && res.RowsAffected == 0 {
+		// MySQL reports 0 for unchanged values too, so tell the two apart
+		return r.db.Select("id").First(&models.Course{}, course.ID).Error
+	}
+	return res.Error
+}
+
+// Delete removes a course; the foreign key refuses it while it has sections
+func (r *courseRepo) Delete(id uint) error {
+	res := r.db.Delete(&models.Course{}, id)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return res.Error
+}
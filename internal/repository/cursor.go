@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCursor is returned by List when the cursor cannot be decoded or was issued for a different sort.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursor is a position in a keyset paginated listing: the sort it was issued for, the sort
+// column's value on the last row returned and that row's id, the tie breaker.
+// Clients only ever see it encoded, so its layout can change freely.
+type cursor struct {
+	Sort  string          `json:"s"`
+	Value json.RawMessage `json:"v"`
+	ID    uint            `json:"id"`
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (*cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// sortColumn is a column a listing can be ordered by; value reads it from a row to build cursors
+type sortColumn[T any] struct {
+	column string
+	value  func(*T) any
+}
+
+// resolveSort looks up a sort key ("-" prefix for descending), falling back to def for unknown keys
+func resolveSort[T any](columns map[string]sortColumn[T], sort, def string) (sortColumn[T], bool) {
+	col, ok := columns[strings.TrimPrefix(sort, "-")]
+	if !ok {
+		sort = def
+		col = columns[strings.TrimPrefix(def, "-")]
+	}
+	return col, strings.HasPrefix(sort, "-")
+}
+
+// orderByKeyset orders query by col, then by idColumn so the order is total, and with an encoded
+// cursor keeps only the rows after it. Unlike an offset this stays correct when rows are inserted
+// or removed between two pages, and the database can seek with an index instead of skipping rows.
+func orderByKeyset[T any](query *gorm.DB, columns map[string]sortColumn[T], sort, def, idColumn, after string) (*gorm.DB, error) {
+	col, desc := resolveSort(columns, sort, def)
+
+	if after != "" {
+		c, err := decodeCursor(after)
+		if err != nil {
+			return nil, err
+		}
+		if c.Sort != sort {
+			return nil, ErrInvalidCursor
+		}
+		value := reflect.New(reflect.TypeOf(col.value(new(T))))
+		if err := json.Unmarshal(c.Value, value.Interface()); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		v := value.Elem().Interface()
+		query = query.Where(fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND %[3]s > ?))", col.column, op, idColumn), v, v, c.ID)
+	}
+
+	order := col.column
+	if desc {
+		order += " DESC"
+	}
+	return query.Order(order).Order(idColumn), nil
+}
+
+// cursorAfter encodes the position right after row for the given sort
+func cursorAfter[T any](columns map[string]sortColumn[T], sort, def string, row *T, id uint) string {
+	col, _ := resolveSort(columns, sort, def)
+	value, _ := json.Marshal(col.value(row))
+	return encodeCursor(cursor{Sort: sort, Value: value, ID: id})
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"hrms_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type DepartmentRepository interface {
+	Create(department *models.Department) error
+	GetAll() ([]models.Department, error)
+	GetByID(id uint) (*models.Department, error)
+	Update(department *models.Department) error
+	Delete(id uint) error
+	CountStudents() (map[uint]int64, error)
+	Merge(fromID, intoID uint, audit *models.AuditLog) (int, error)
+}
+
+type departmentRepo struct {
+	db *gorm.DB
+}
+
+func NewDepartmentRepository(db *gorm.DB) DepartmentRepository {
+	return &departmentRepo{db: db}
+}
+
+func (r *departmentRepo) Create(department *models.Department) error {
+	return r.db.Create(department).Error
+}
+
+// GetAll returns every department ordered by name
+func (r *departmentRepo) GetAll() ([]models.Department, error) {
+	var departments []models.Department
+	err := r.db.Order("name").Find(&departments).Error
+	return departments, err
+}
+
+func (r *departmentRepo) GetByID(id uint) (*models.Department, error) {
+	var department models.Department
+	if err := r.db.First(&department, id).Error; err != nil {
+		return nil, err
+	}
+	return &department, nil
+}
+
+// Update saves the department's name
+func (r *departmentRepo) Update(department *models.Department) error {
+	res := r.db.Model(department).Update("name", department.Name)
+	if res.Error == nil && res.RowsAffected == 0 {
+		// MySQL reports 0 for an unchanged name too, so tell the two apart
+		return r.db.Select("id").First(&models.Department{}, department.ID).Error
+	}
+	return res.Error
+}
+
+// Delete removes a department; the foreign key refuses it while students (deleted ones included) reference it
+func (r *departmentRepo) Delete(id uint) error {
+	res := r.db.Delete(&models.Department{}, id)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return res.Error
+}
+
+// CountStudents returns the number of (not deleted) students per department ID
+func (r *departmentRepo) CountStudents() (map[uint]int64, error) {
+	var rows []struct {
+		DepartmentID uint
+		Count        int64
+	}
+	err := r.db.Model(&models.Student{}).Select("department_id, COUNT(*) AS count").Group("department_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.DepartmentID] = row.Count
+	}
+	return counts, nil
+}
+
+// Merge moves every student of department fromID, soft deleted ones included, to intoID and deletes
+// fromID, in one transaction. Each moved student gets an audit entry. It returns the number of students moved.
+func (r *departmentRepo) Merge(fromID, intoID uint, audit *models.AuditLog) (int, error) {
+	var moved int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, id := range []uint{fromID, intoID} {
+			if err := tx.Select("id").First(&models.Department{}, id).Error; err != nil {
+				return err
+			}
+		}
+
+		var students []models.Student
+		if err := tx.Unscoped().Where("department_id = ?", fromID).Find(&students).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&models.Student{}).Where("department_id = ?", fromID).Update("department_id", intoID).Error; err != nil {
+			return err
+		}
+		for i := range students {
+			after := students[i]
+			after.DepartmentID = intoID
+			if err := writeAudit(tx, audit, students[i].ID, &students[i], &after); err != nil {
+				return err
+			}
+		}
+		moved = len(students)
+
+		return tx.Delete(&models.Department{}, fromID).Error
+	})
+	return moved, err
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"errors"
+	"hrms_backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrLeaveNotPending is returned when a leave request was decided or cancelled in the meantime
+var ErrLeaveNotPending = errors.New("leave request is no longer pending")
+
+// LeaveFilter narrows down List; zero values mean "no filter".
+type LeaveFilter struct {
+	StudentID uint
+	Status    string
+}
+
+type LeaveRepository interface {
+	Create(leave *models.LeaveRequest) error
+	GetByID(id uint) (*models.LeaveRequest, error)
+	List(filter LeaveFilter) ([]models.LeaveRequest, error)
+	HasOverlap(studentID uint, from, to time.Time) (bool, error)
+	Decide(leave *models.LeaveRequest) error
+	Approve(leave *models.LeaveRequest, days []time.Time, reason string, audit *models.AuditLog) (int, error)
+}
+
+type leaveRepo struct {
+	db   *gorm.DB
+	lock bool // lock the rows read, see Repos
+}
+
+func NewLeaveRepository(db *gorm.DB) LeaveRepository {
+	return &leaveRepo{db: db}
+}
+
+func (r *leaveRepo) Create(leave *models.LeaveRequest) error {
+	return r.db.Create(leave).Error
+}
+
+func (r *leaveRepo) GetByID(id uint) (*models.LeaveRequest, error) {
+	var leave models.LeaveRequest
+	if err := r.db.Preload("Student").First(&leave, id).Error; err != nil {
+		return nil, err
+	}
+	return &leave, nil
+}
+
+// List returns the matching requests, newest first
+func (r *leaveRepo) List(filter LeaveFilter) ([]models.LeaveRequest, error) {
+	query := r.db.Preload("Student")
+	if filter.StudentID != 0 {
+		query = query.Where("student_id = ?", filter.StudentID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	var leaves []models.LeaveRequest
+	err := query.Order("created_at DESC, id DESC").Find(&leaves).Error
+	return leaves, err
+}
+
+// HasOverlap reports whether the student has a pending or approved request overlapping [from, to]
+func (r *leaveRepo) HasOverlap(studentID uint, from, to time.Time) (bool, error) {
+	var count int64
+	err := forUpdate(r.db, r.lock).Model(&models.LeaveRequest{}).
+		Where("student_id = ? AND status IN ? AND start_date <= ? AND end_date >= ?",
+			studentID, []string{models.LeavePending, models.LeaveApproved}, to, from).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Decide moves a pending request to leave.Status with its review fields.
+// Fails with ErrLeaveNotPending if the request is no longer pending.
+func (r *leaveRepo) Decide(leave *models.LeaveRequest) error {
+	return decideLeave(r.db, leave)
+}
+
+// Approve decides the request and, in the same transaction, excuses the student on days (UTC midnights):
+// every record on those days becomes "excused" (with a correction entry) and a whole day
+// "excused" record is created on the days without one. It returns how many days were touched.
+// The audit entry is written for every record created or converted.
+func (r *leaveRepo) Approve(leave *models.LeaveRequest, days []time.Time, reason string, audit *models.AuditLog) (int, error) {
+	touched := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := decideLeave(tx, leave); err != nil {
+			return err
+		}
+		if len(days) == 0 {
+			return nil
+		}
+
+		var existing []models.Attendance
+		if err := tx.Where("student_id = ? AND date IN ?", leave.StudentID, days).Find(&existing).Error; err != nil {
+			return err
+		}
+		touchedDays := make(map[time.Time]bool)
+		wholeDay := make(map[time.Time]bool)
+		for i := range existing {
+			before := existing[i]
+			y, m, d := before.Date.Date()
+			day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+			if before.SectionID == nil {
+				wholeDay[day] = true
+			}
+			if before.Status == "excused" {
+				continue
+			}
+			after := before
+			after.Status, after.LateMinutes = "excused", 0
+			err := tx.Model(&after).Updates(map[string]any{"status": after.Status, "late_minutes": 0}).Error
+			if err != nil {
+				return err
+			}
+			correction := models.AttendanceCorrection{
+				AttendanceID:   before.ID,
+				StudentID:      before.StudentID,
+				Date:           before.Date,
+				Action:         "update",
+				PreviousStatus: before.Status,
+				NewStatus:      after.Status,
+				Reason:         reason,
+			}
+			if err := tx.Create(&correction).Error; err != nil {
+				return err
+			}
+			if err := writeAudit(tx, auditAs(audit, models.AuditActionUpdate), before.ID, &before, &after); err != nil {
+				return err
+			}
+			touchedDays[day] = true
+		}
+
+		var created []models.Attendance
+		for _, day := range days {
+			if !wholeDay[day] {
+				created = append(created, models.Attendance{StudentID: leave.StudentID, Date: day, Status: "excused"})
+				touchedDays[day] = true
+			}
+		}
+		if len(created) > 0 {
+			if err := tx.Create(&created).Error; err != nil {
+				return err
+			}
+			for i := range created {
+				if err := writeAudit(tx, auditAs(audit, models.AuditActionCreate), created[i].ID, nil, &created[i]); err != nil {
+					return err
+				}
+			}
+		}
+		touched = len(touchedDays)
+		return nil
+	})
+	return touched, err
+}
+
+// decideLeave only updates a pending row, so two reviewers cannot both decide the same request
+func decideLeave(tx *gorm.DB, leave *models.LeaveRequest) error {
+	res := tx.Model(leave).Where("status = ?", models.LeavePending).
+		Select("status", "reviewed_by_id", "reviewed_at", "review_note", "updated_at").Updates(leave)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		if err := tx.Select("id").First(&models.LeaveRequest{}, leave.ID).Error; err != nil {
+			return err
+		}
+		return ErrLeaveNotPending
+	}
+	return nil
+}
+
+// auditAs copies the audit entry with another action; nil stays nil
+func auditAs(entry *models.AuditLog, action string) *models.AuditLog {
+	if entry == nil {
+		return nil
+	}
+	copied := *entry
+	copied.Action = action
+	return &copied
+}
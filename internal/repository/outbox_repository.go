@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"encoding/json"
+	"hrms_backend/internal/models"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// outboxEvents names the event every audited change is published as: entity type -> action -> event
+var outboxEvents = map[string]map[string]string{
+	models.AuditEntityStudent: {
+		models.AuditActionCreate:  models.EventStudentCreated,
+		models.AuditActionUpdate:  models.EventStudentUpdated,
+		models.AuditActionDelete:  models.EventStudentDeleted,
+		models.AuditActionRestore: models.EventStudentRestored,
+		models.AuditActionPurge:   models.EventStudentPurged,
+	},
+	models.AuditEntityAttendance: {
+		models.AuditActionCreate: models.EventAttendanceMarked,
+		models.AuditActionUpdate: models.EventAttendanceUpdated,
+		models.AuditActionDelete: models.EventAttendanceDeleted,
+	},
+}
+
+// outboxClaim is how long a dispatcher may take to publish the batch it claimed before another
+// one hands it out again
+const outboxClaim = 5 * time.Minute
+
+// maxLastError is the length of the last_error columns, in characters
+const maxLastError = 500
+
+type OutboxRepository interface {
+	Drain(limit int, publish func(event models.OutboxEvent) error) (int, error)
+}
+
+type outboxRepo struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepo{db: db}
+}
+
+// Drain hands up to limit pending events to publish, oldest first, and marks the published ones.
+// It stops at the first event publish fails on, recording the error, so later events never
+// overtake it, and returns how many were published along with that error.
+// The batch is claimed for outboxClaim in a short transaction and published after it commits, so
+// no row lock is held while publishing; a second dispatcher finding the oldest events claimed
+// drains nothing. If the process dies before the end, the unpublished rest of the batch is handed
+// out again once the claim runs out.
+func (r *outboxRepo) Drain(limit int, publish func(event models.OutboxEvent) error) (int, error) {
+	events, err := r.claim(limit)
+	if err != nil {
+		return 0, err
+	}
+	for i, event := range events {
+		if publishErr := publish(event); publishErr != nil {
+			err := r.db.Model(&event).Updates(map[string]any{
+				"attempts":      gorm.Expr("attempts + 1"),
+				"last_error":    truncateLastError(publishErr.Error()),
+				"claimed_until": nil,
+			}).Error
+			if err == nil {
+				err = r.release(events[i+1:])
+			}
+			if err != nil {
+				return i, err
+			}
+			return i, publishErr
+		}
+		err := r.db.Model(&event).Updates(map[string]any{"published_at": time.Now(), "last_error": "", "claimed_until": nil}).Error
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(events), nil
+}
+
+// claim locks the oldest limit pending events just long enough to mark them as claimed, unless
+// another dispatcher's claim on any of them is still running
+func (r *outboxRepo) claim(limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("published_at IS NULL").Order("id").Limit(limit).Find(&events).Error
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		ids := make([]uint, len(events))
+		for i, event := range events {
+			if event.ClaimedUntil != nil && event.ClaimedUntil.After(now) {
+				events = nil
+				return nil
+			}
+			ids[i] = event.ID
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Model(&models.OutboxEvent{}).Where("id IN ?", ids).Update("claimed_until", now.Add(outboxClaim)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// release gives up the claim on events left unpublished, so the next call hands them out again
+func (r *outboxRepo) release(events []models.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return r.db.Model(&models.OutboxEvent{}).Where("id IN ?", ids).Update("claimed_until", nil).Error
+}
+
+// truncateLastError cuts msg to fit a last_error column, on a character boundary
+func truncateLastError(msg string) string {
+	if utf8.RuneCountInString(msg) <= maxLastError {
+		return msg
+	}
+	return string([]rune(msg)[:maxLastError])
+}
+
+// writeOutbox queues the change recorded by entry, with the entity as JSON, using tx so it
+// commits or rolls back together with the change. Entity types without events are skipped.
+func writeOutbox(tx *gorm.DB, entry models.AuditLog, entity any) error {
+	event, ok := outboxEvents[entry.EntityType][entry.Action]
+	if !ok {
+		return nil
+	}
+	payload, err := json.Marshal(entity)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&models.OutboxEvent{
+		Event:      event,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Payload:    string(payload),
+	}).Error
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"hrms_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ReportRepository interface {
+	Create(report *models.Report) error
+	GetAll(limit, offset int) ([]models.Report, error)
+	GetByID(id uint) (*models.Report, error)
+}
+
+type reportRepo struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepo{db: db}
+}
+
+// Create stores the report together with its rows (gorm saves the association in one transaction)
+func (r *reportRepo) Create(report *models.Report) error {
+	return r.db.Create(report).Error
+}
+
+// GetAll lists reports newest first, without their rows
+func (r *reportRepo) GetAll(limit, offset int) ([]models.Report, error) {
+	var reports []models.Report
+	err := r.db.Order("id DESC").Limit(limit).Offset(offset).Find(&reports).Error
+	return reports, err
+}
+
+// GetByID loads a report with its rows
+func (r *reportRepo) GetByID(id uint) (*models.Report, error) {
+	var report models.Report
+	err := r.db.Preload("Rows", func(db *gorm.DB) *gorm.DB {
+		return db.Order("student_name")
+	}).First(&report, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
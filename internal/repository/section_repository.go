@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"hrms_backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SectionRepository interface {
+	Create(section *models.Section) error
+	GetByCourse(courseID uint) ([]models.Section, error)
+	GetByID(id uint) (*models.Section, error)
+	Update(section *models.Section) error
+	Delete(id uint) error
+	CountEnrollments(sectionIDs []uint) (map[uint]int64, error)
+	Enroll(sectionID uint, studentIDs []uint) (int, error)
+	Unenroll(sectionID, studentID uint) error
+	GetEnrolledStudents(sectionID uint) ([]models.Student, error)
+	IsEnrolled(sectionID, studentID uint) (bool, error)
+}
+
+type sectionRepo struct {
+	db   *gorm.DB
+	lock bool // lock the rows read, see Repos
+}
+
+func NewSectionRepository(db *gorm.DB) SectionRepository {
+	return &sectionRepo{db: db}
+}
+
+func (r *sectionRepo) Create(section *models.Section) error {
+	return r.db.Omit(clause.Associations).Create(section).Error
+}
+
+// GetByCourse returns the sections of a course ordered by name
+func (r *sectionRepo) GetByCourse(courseID uint) ([]models.Section, error) {
+	var sections []models.Section
+	err := r.db.Preload("Course").Where("course_id = ?", courseID).Order("name").Find(&sections).Error
+	return sections, err
+}
+
+func (r *sectionRepo) GetByID(id uint) (*models.Section, error) {
+	var section models.Section
+	if err := forUpdate(r.db, r.lock).Preload("Course").First(&section, id).Error; err != nil {
+		return nil, err
+	}
+	return &section, nil
+}
+
+// Update saves the section's name
+func (r *sectionRepo) Update(section *models.Section) error {
+	res := r.db.Model(section).Update("name", section.Name)
+	if res.Error == nil && res.RowsAffected == 0 {
+		// MySQL reports 0 for an unchanged name too, so tell the two apart
+		return r.db.Select("id").First(&models.Section{}, section.ID).Error
+	}
+	return res.Error
+}
+
+// Delete removes a section with its enrollments; the foreign key refuses it once attendance was taken in it
+func (r *sectionRepo) Delete(id uint) error {
+	res := r.db.Delete(&models.Section{}, id)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return res.Error
+}
+
+// CountEnrollments returns the number of (not deleted) students enrolled per section ID
+func (r *sectionRepo) CountEnrollments(sectionIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(sectionIDs))
+	if len(sectionIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		SectionID uint
+		Count     int64
+	}
+	err := r.db.Model(&models.Enrollment{}).
+		Joins("JOIN students ON students.id = enrollments.student_id AND students.deleted_at IS NULL").
+		Where("enrollments.section_id IN ?", sectionIDs).
+		Select("enrollments.section_id, COUNT(*) AS count").Group("enrollments.section_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.SectionID] = row.Count
+	}
+	return counts, nil
+}
+
+// Enroll adds the students to the section, skipping those already enrolled.
+// It returns the number of new enrollments.
+func (r *sectionRepo) Enroll(sectionID uint, studentIDs []uint) (int, error) {
+	if len(studentIDs) == 0 {
+		return 0, nil
+	}
+	enrollments := make([]models.Enrollment, 0, len(studentIDs))
+	for _, id := range studentIDs {
+		enrollments = append(enrollments, models.Enrollment{SectionID: sectionID, StudentID: id})
+	}
+	res := r.db.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&enrollments)
+	return int(res.RowsAffected), res.Error
+}
+
+// Unenroll removes a student from the section; their attendance records in it are kept
+func (r *sectionRepo) Unenroll(sectionID, studentID uint) error {
+	res := r.db.Where("section_id = ? AND student_id = ?", sectionID, studentID).Delete(&models.Enrollment{})
+	if res.Error == nil && res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return res.Error
+}
+
+// GetEnrolledStudents returns the (not deleted) students of a section ordered by name
+func (r *sectionRepo) GetEnrolledStudents(sectionID uint) ([]models.Student, error) {
+	var students []models.Student
+	err := r.db.Preload("Department").
+		Joins("JOIN enrollments ON enrollments.student_id = students.id").
+		Where("enrollments.section_id = ?", sectionID).
+		Order("students.name, students.id").Find(&students).Error
+	return students, err
+}
+
+func (r *sectionRepo) IsEnrolled(sectionID, studentID uint) (bool, error) {
+	var count int64
+	err := forUpdate(r.db, r.lock).Model(&models.Enrollment{}).Where("section_id = ? AND student_id = ?", sectionID, studentID).Count(&count).Error
+	return count > 0, err
+}
@@ -2,24 +2,64 @@ package repository
 
 import (
 	"hrms_backend/internal/models"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// StudentFilter narrows down List; zero values mean "no filter".
+type StudentFilter struct {
+	Query          string // substring of the name or email
+	DepartmentID   uint
+	CreatedFrom    time.Time // inclusive
+	CreatedTo      time.Time // exclusive
+	IncludeDeleted bool
+	// Column to order by, "-" prefix for descending. Must be one of studentSortColumns.
+	Sort   string
+	Limit  int // 0 returns every match
+	Offset int
+	// Encoded cursor from StudentCursor: only students after it are returned and Offset is ignored
+	After string
+}
+
+// whitelisted sort keys -> SQL columns
+var studentSortColumns = map[string]sortColumn[models.Student]{
+	"id":            {"students.id", func(s *models.Student) any { return s.ID }},
+	"name":          {"students.name", func(s *models.Student) any { return s.Name }},
+	"email":         {"students.email", func(s *models.Student) any { return s.Email }},
+	"department_id": {"students.department_id", func(s *models.Student) any { return s.DepartmentID }},
+	"created_at":    {"students.created_at", func(s *models.Student) any { return s.CreatedAt }},
+}
+
+// StudentCursor returns the cursor to list the students following st in the given sort
+func StudentCursor(sort string, st *models.Student) string {
+	return cursorAfter(studentSortColumns, sort, "id", st, st.ID)
+}
+
 // handles DB operations (Create, Read, etc.).
 // controller depends on this abstraction, not the implementation.
 
 type StudentRepository interface {
-	Create(student *models.Student) error
-	GetAll(limit, offset int) ([]models.Student, error)
-	Update(id uint, student *models.Student) error
+	Create(student *models.Student, audit *models.AuditLog) error
+	CreateBatch(students []models.Student, audit *models.AuditLog) error
+	List(filter StudentFilter) ([]models.Student, int64, error)
+	Update(id uint, student *models.Student, audit *models.AuditLog) error
 	GetByID(id uint) (*models.Student, error)
-	Delete(id uint) error
+	GetByIDWithDeleted(id uint) (*models.Student, error)
+	GetByIDs(ids []uint) ([]models.Student, error)
+	GetByEmails(emails []string) ([]models.Student, error)
+	GetByDepartment(departmentID uint) ([]models.Student, error)
+	Delete(id uint, audit *models.AuditLog) error
+	Restore(id uint, audit *models.AuditLog) error
+	Purge(id uint, audit *models.AuditLog) error
 }
 
 // the interface
 type studentRepo struct {
-	db *gorm.DB
+	db   *gorm.DB
+	lock bool // lock the rows read, see Repos
 }
 
 // Constructor
@@ -27,34 +67,207 @@ func NewStudentRepository(db *gorm.DB) StudentRepository {
 	return &studentRepo{db: db}
 }
 
-// Create a student
-func (r *studentRepo) Create(student *models.Student) error {
-	return r.db.Create(student).Error
+// Create a student, with its audit entry in the same transaction
+func (r *studentRepo) Create(student *models.Student, audit *models.AuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(student).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, audit, student.ID, nil, student)
+	})
 }
 
-// Get all students
-func (r *studentRepo) GetAll(limit, offset int) ([]models.Student, error) {
+// CreateBatch inserts all students in one transaction, with an audit entry for each
+func (r *studentRepo) CreateBatch(students []models.Student, audit *models.AuditLog) error {
+	if len(students) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).CreateInBatches(students, 100).Error; err != nil {
+			return err
+		}
+		for i := range students {
+			if err := writeAudit(tx, audit, students[i].ID, nil, &students[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// List returns one page of students matching the filter plus the total number of matches
+func (r *studentRepo) List(filter StudentFilter) ([]models.Student, int64, error) {
+	query := r.db.Model(&models.Student{}).Preload("Department")
+	if filter.IncludeDeleted {
+		query = query.Unscoped()
+	}
+	if filter.Query != "" {
+		like := "%" + escapeLike(filter.Query) + "%"
+		query = query.Where("name LIKE ? OR email LIKE ?", like, like)
+	}
+	if filter.DepartmentID != 0 {
+		query = query.Where("department_id = ?", filter.DepartmentID)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		query = query.Where("created_at >= ?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedTo)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// default: insertion order; id as tie breaker so pages are stable
+	query, err := orderByKeyset(query, studentSortColumns, filter.Sort, "id", "students.id", filter.After)
+	if err != nil {
+		return nil, 0, err
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+		if filter.After == "" {
+			query = query.Offset(filter.Offset)
+		}
+	}
+
 	var students []models.Student
-	err := r.db.Limit(limit).Offset(offset).Find(&students).Error
-	return students, err
+	err = query.Find(&students).Error
+	return students, total, err
+}
+
+// escapeLike makes user input match literally inside a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
 // 6Get a student by ID (useful for update/delete)
 func (r *studentRepo) GetByID(id uint) (*models.Student, error) {
 	var student models.Student
-	err := r.db.First(&student, id).Error
+	err := forUpdate(r.db, r.lock).Preload("Department").First(&student, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &student, nil
 }
 
-// Update a student
-func (r *studentRepo) Update(id uint, student *models.Student) error {
-	return r.db.Model(&models.Student{}).Where("id = ?", id).Updates(student).Error
+// Get a student by ID even if it is soft deleted
+func (r *studentRepo) GetByIDWithDeleted(id uint) (*models.Student, error) {
+	var student models.Student
+	err := forUpdate(r.db, r.lock).Unscoped().Preload("Department").First(&student, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &student, nil
+}
+
+// Get several students in one query (missing IDs are simply absent from the result)
+func (r *studentRepo) GetByIDs(ids []uint) ([]models.Student, error) {
+	var students []models.Student
+	if len(ids) == 0 {
+		return students, nil
+	}
+	err := forUpdate(r.db, r.lock).Where("id IN ?", ids).Find(&students).Error
+	return students, err
+}
+
+// Get the (not deleted) students using any of the emails; the comparison follows the column
+// collation, so it is case insensitive like the unique index
+func (r *studentRepo) GetByEmails(emails []string) ([]models.Student, error) {
+	var students []models.Student
+	if len(emails) == 0 {
+		return students, nil
+	}
+	err := forUpdate(r.db, r.lock).Where("email IN ?", emails).Find(&students).Error
+	return students, err
+}
+
+// Get every student of a department
+func (r *studentRepo) GetByDepartment(departmentID uint) ([]models.Student, error) {
+	var students []models.Student
+	err := forUpdate(r.db, r.lock).Where("department_id = ?", departmentID).Find(&students).Error
+	return students, err
+}
+
+// Update a student; the audit entry records the columns that changed
+func (r *studentRepo) Update(id uint, student *models.Student, audit *models.AuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var before, after models.Student
+		if err := tx.First(&before, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Student{}).Omit(clause.Associations).Where("id = ?", id).Updates(student).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&after, id).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, audit, id, &before, &after)
+	})
+}
+
+// Delete a student, keeping its last state in the audit entry
+func (r *studentRepo) Delete(id uint, audit *models.AuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var before models.Student
+		if err := tx.First(&before, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Student{}, id).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, audit, id, &before, nil)
+	})
+}
+
+// Restore undoes the soft delete of a student
+func (r *studentRepo) Restore(id uint, audit *models.AuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Student{}).Where("id = ?", id).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		var restored models.Student
+		if err := tx.First(&restored, id).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, audit, id, nil, &restored)
+	})
 }
 
-// Delete a student
-func (r *studentRepo) Delete(id uint) error {
-	return r.db.Delete(&models.Student{}, id).Error
+// Purge permanently deletes a student, deleted or not, with its attendance records and their
+// correction history. Each record is audited (and published) as an attendance delete by the same
+// actor, before the purge of the student itself. The foreign keys unlink the student's user
+// accounts and drop their enrollments.
+func (r *studentRepo) Purge(id uint, audit *models.AuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var before models.Student
+		if err := tx.Unscoped().First(&before, id).Error; err != nil {
+			return err
+		}
+		var records []models.Attendance
+		if err := tx.Unscoped().Where("student_id = ?", id).Order("id").Find(&records).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("student_id = ?", id).Delete(&models.AttendanceCorrection{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("student_id = ?", id).Delete(&models.Attendance{}).Error; err != nil {
+			return err
+		}
+		if audit != nil {
+			recordAudit := *audit
+			recordAudit.EntityType = models.AuditEntityAttendance
+			recordAudit.Action = models.AuditActionDelete
+			for i := range records {
+				if err := writeAudit(tx, &recordAudit, records[i].ID, &records[i], nil); err != nil {
+					return err
+				}
+			}
+		}
+		if err := tx.Unscoped().Delete(&models.Student{}, id).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, audit, id, &before, nil)
+	})
 }
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"hrms_backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SessionFilter narrows down ListSessions; zero values mean "no filter".
+type SessionFilter struct {
+	SectionID        uint
+	TeacherID        uint
+	From             time.Time // inclusive
+	To               time.Time // inclusive
+	IncludeCancelled bool
+}
+
+type TimetableRepository interface {
+	CreateSlot(slot *models.TimetableSlot) error
+	GetSlots(sectionID uint) ([]models.TimetableSlot, error)
+	GetSlotsValidBetween(from, to time.Time) ([]models.TimetableSlot, error)
+	GetSlotByID(id uint) (*models.TimetableSlot, error)
+	UpdateSlot(slot *models.TimetableSlot, from time.Time) error
+	DeleteSlot(id uint, from time.Time) error
+	CreateSessions(sessions []models.ClassSession) (int, error)
+	ListSessions(filter SessionFilter) ([]models.ClassSession, error)
+	GetSessionByID(id uint) (*models.ClassSession, error)
+	SetCancelled(id uint, cancelled bool) error
+}
+
+type timetableRepo struct {
+	db *gorm.DB
+}
+
+func NewTimetableRepository(db *gorm.DB) TimetableRepository {
+	return &timetableRepo{db: db}
+}
+
+func (r *timetableRepo) CreateSlot(slot *models.TimetableSlot) error {
+	return r.db.Omit(clause.Associations).Create(slot).Error
+}
+
+// GetSlots returns the slots of a section, or of every section when sectionID is 0,
+// in weekly order
+func (r *timetableRepo) GetSlots(sectionID uint) ([]models.TimetableSlot, error) {
+	query := r.db.Preload("Section.Course")
+	if sectionID != 0 {
+		query = query.Where("section_id = ?", sectionID)
+	}
+	var slots []models.TimetableSlot
+	err := query.Order("weekday, start_time, id").Find(&slots).Error
+	return slots, err
+}
+
+// GetSlotsValidBetween returns the slots valid on at least one day of [from, to]
+func (r *timetableRepo) GetSlotsValidBetween(from, to time.Time) ([]models.TimetableSlot, error) {
+	var slots []models.TimetableSlot
+	err := r.db.Where("valid_from <= ? AND (valid_until IS NULL OR valid_until >= ?)", to, from).
+		Order("id").Find(&slots).Error
+	return slots, err
+}
+
+func (r *timetableRepo) GetSlotByID(id uint) (*models.TimetableSlot, error) {
+	var slot models.TimetableSlot
+	if err := r.db.Preload("Section.Course").First(&slot, id).Error; err != nil {
+		return nil, err
+	}
+	return &slot, nil
+}
+
+// UpdateSlot saves the slot and, in the same transaction, drops the sessions generated from it
+// on or after from, so they can be generated again with the new schedule.
+// Cancelled sessions are kept.
+func (r *timetableRepo) UpdateSlot(slot *models.TimetableSlot, from time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(slot).
+			Select("weekday", "start_time", "end_time", "room", "teacher_id", "late_after_minutes", "valid_from", "valid_until").
+			Updates(slot).Error
+		if err != nil {
+			return err
+		}
+		return deleteUpcomingSessions(tx, slot.ID, from)
+	})
+}
+
+// DeleteSlot removes a slot with its sessions on or after from; earlier sessions stay as they were
+func (r *timetableRepo) DeleteSlot(id uint, from time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteUpcomingSessions(tx, id, from); err != nil {
+			return err
+		}
+		res := tx.Delete(&models.TimetableSlot{}, id)
+		if res.Error == nil && res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return res.Error
+	})
+}
+
+func deleteUpcomingSessions(tx *gorm.DB, slotID uint, from time.Time) error {
+	return tx.Where("slot_id = ? AND date >= ? AND cancelled = ?", slotID, from, false).Delete(&models.ClassSession{}).Error
+}
+
+// CreateSessions inserts the sessions, skipping those whose section already has a session
+// starting at the same time. It returns the number of sessions created.
+func (r *timetableRepo) CreateSessions(sessions []models.ClassSession) (int, error) {
+	if len(sessions) == 0 {
+		return 0, nil
+	}
+	res := r.db.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(sessions, 100)
+	return int(res.RowsAffected), res.Error
+}
+
+// ListSessions returns the sessions matching the filter in chronological order
+func (r *timetableRepo) ListSessions(filter SessionFilter) ([]models.ClassSession, error) {
+	query := r.db.Preload("Section.Course")
+	if filter.SectionID != 0 {
+		query = query.Where("section_id = ?", filter.SectionID)
+	}
+	if filter.TeacherID != 0 {
+		query = query.Where("teacher_id = ?", filter.TeacherID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("date <= ?", filter.To)
+	}
+	if !filter.IncludeCancelled {
+		query = query.Where("cancelled = ?", false)
+	}
+	var sessions []models.ClassSession
+	err := query.Order("date, start_time, id").Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *timetableRepo) GetSessionByID(id uint) (*models.ClassSession, error) {
+	var session models.ClassSession
+	if err := r.db.Preload("Section.Course").First(&session, id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// SetCancelled cancels a session, or restores a cancelled one
+func (r *timetableRepo) SetCancelled(id uint, cancelled bool) error {
+	res := r.db.Model(&models.ClassSession{}).Where("id = ?", id).Update("cancelled", cancelled)
+	if res.Error == nil && res.RowsAffected == 0 {
+		// MySQL reports 0 for an unchanged value too, so tell the two apart
+		return r.db.Select("id").First(&models.ClassSession{}, id).Error
+	}
+	return res.Error
+}
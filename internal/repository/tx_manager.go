@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repos are the repositories bound to one transaction, handed out by TxManager.WithTx.
+// Their single-row and lookup reads lock the rows they return (SELECT ... FOR UPDATE) until the
+// transaction ends, so what a service checked cannot be changed or deleted by a concurrent request
+// before it writes.
+type Repos struct {
+	Students   StudentRepository
+	Attendance AttendanceRepository
+	Sections   SectionRepository
+	Leaves     LeaveRepository
+}
+
+type TxManager interface {
+	// WithTx runs fn in one transaction, committed if fn returns nil and rolled back otherwise
+	WithTx(ctx context.Context, fn func(repos Repos) error) error
+}
+
+type txManager struct {
+	db *gorm.DB
+}
+
+func NewTxManager(db *gorm.DB) TxManager {
+	return &txManager{db: db}
+}
+
+func (m *txManager) WithTx(ctx context.Context, fn func(repos Repos) error) error {
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the repositories' own transactions become savepoints of this one
+		return fn(Repos{
+			Students:   &studentRepo{db: tx, lock: true},
+			Attendance: &attendanceRepo{db: tx, lock: true},
+			Sections:   &sectionRepo{db: tx, lock: true},
+			Leaves:     &leaveRepo{db: tx, lock: true},
+		})
+	})
+}
+
+// forUpdate locks the rows a query reads when lock is set, i.e. for repositories from WithTx
+func forUpdate(db *gorm.DB, lock bool) *gorm.DB {
+	if !lock {
+		return db
+	}
+	return db.Clauses(clause.Locking{Strength: "UPDATE"})
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"hrms_backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DeliveryFilter narrows down ListDeliveries; zero values mean "no filter".
+type DeliveryFilter struct {
+	SubscriptionID uint
+	Status         string
+	Event          string
+	Limit          int
+	Offset         int
+}
+
+type WebhookRepository interface {
+	CreateSubscription(sub *models.WebhookSubscription) error
+	GetSubscriptions() ([]models.WebhookSubscription, error)
+	GetSubscriptionByID(id uint) (*models.WebhookSubscription, error)
+	GetActiveSubscriptions() ([]models.WebhookSubscription, error)
+	UpdateSubscription(sub *models.WebhookSubscription) error
+	DeleteSubscription(id uint) error
+
+	CreateDeliveries(deliveries []models.WebhookDelivery) error
+	GetDueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error)
+	GetDeliveryByID(id uint) (*models.WebhookDelivery, error)
+	UpdateDelivery(delivery *models.WebhookDelivery) error
+	ListDeliveries(filter DeliveryFilter) ([]models.WebhookDelivery, int64, error)
+}
+
+type webhookRepo struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepo{db: db}
+}
+
+func (r *webhookRepo) CreateSubscription(sub *models.WebhookSubscription) error {
+	return r.db.Create(sub).Error
+}
+
+func (r *webhookRepo) GetSubscriptions() ([]models.WebhookSubscription, error) {
+	var subs []models.WebhookSubscription
+	err := r.db.Order("id").Find(&subs).Error
+	return subs, err
+}
+
+func (r *webhookRepo) GetSubscriptionByID(id uint) (*models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	if err := r.db.First(&sub, id).Error; err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// GetActiveSubscriptions returns the subscriptions events are currently sent to
+func (r *webhookRepo) GetActiveSubscriptions() ([]models.WebhookSubscription, error) {
+	var subs []models.WebhookSubscription
+	err := r.db.Where("active = ?", true).Order("id").Find(&subs).Error
+	return subs, err
+}
+
+// UpdateSubscription saves the URL, secret, events and active flag
+func (r *webhookRepo) UpdateSubscription(sub *models.WebhookSubscription) error {
+	res := r.db.Model(sub).Select("url", "secret", "events", "active", "updated_at").Updates(sub)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return r.db.Select("id").First(&models.WebhookSubscription{}, sub.ID).Error
+	}
+	return res.Error
+}
+
+// DeleteSubscription removes a subscription; its delivery log goes with it
+func (r *webhookRepo) DeleteSubscription(id uint) error {
+	res := r.db.Delete(&models.WebhookSubscription{}, id)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return res.Error
+}
+
+// CreateDeliveries queues deliveries in one insert, skipping those of an event already queued
+// for the same subscription
+func (r *webhookRepo) CreateDeliveries(deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+// GetDueDeliveries returns up to limit pending deliveries whose next attempt is due, oldest first,
+// with their subscription. Deliveries of deactivated subscriptions wait until it is active again.
+func (r *webhookRepo) GetDueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.Joins("Subscription").
+		Where("Subscription.active = ?", true).
+		Where("webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ?", models.DeliveryPending, now).
+		Order("webhook_deliveries.next_attempt_at, webhook_deliveries.id").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *webhookRepo) GetDeliveryByID(id uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := r.db.First(&delivery, id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// UpdateDelivery saves the outcome of an attempt, with LastError cut to fit its column
+func (r *webhookRepo) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	delivery.LastError = truncateLastError(delivery.LastError)
+	return r.db.Model(delivery).
+		Select("status", "attempts", "next_attempt_at", "response_status", "last_error", "delivered_at", "updated_at").
+		Updates(delivery).Error
+}
+
+// ListDeliveries returns one page of matching deliveries, newest first, plus the total number of matches
+func (r *webhookRepo) ListDeliveries(filter DeliveryFilter) ([]models.WebhookDelivery, int64, error) {
+	query := r.db.Model(&models.WebhookDelivery{})
+	if filter.SubscriptionID != 0 {
+		query = query.Where("subscription_id = ?", filter.SubscriptionID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Event != "" {
+		query = query.Where("event = ?", filter.Event)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	err := query.Order("id DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&deliveries).Error
+	return deliveries, total, err
+}
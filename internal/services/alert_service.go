@@ -0,0 +1,268 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/notify"
+	"hrms_backend/internal/repository"
+	"hrms_backend/internal/viewmodels"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// AlertThresholds decide when an alert is sent about a student
+type AlertThresholds struct {
+	// rolling window, in days before today, the percentage and streak are computed over
+	WindowDays int
+	// alert when the attendance percentage over the window falls below this
+	MinPercentage float64
+	// ...as long as the student has at least this many records in the window, so one missed
+	// class on the first day of term is not 0%
+	MinRecords int
+	// alert after this many school days missed in a row
+	ConsecutiveAbsences int
+}
+
+// DefaultAlertThresholds: under 75% over the last 30 days (from 5 records), or 3 days missed in a row
+var DefaultAlertThresholds = AlertThresholds{WindowDays: 30, MinPercentage: 75, MinRecords: 5, ConsecutiveAbsences: 3}
+
+// ParseAlertThresholds overrides the defaults with the values that are set, e.g. from the environment
+func ParseAlertThresholds(windowDays, minPercentage, minRecords, consecutiveAbsences string) (AlertThresholds, error) {
+	t := DefaultAlertThresholds
+	ints := []struct {
+		name  string
+		value string
+		into  *int
+	}{
+		{"window days", windowDays, &t.WindowDays},
+		{"min records", minRecords, &t.MinRecords},
+		{"consecutive absences", consecutiveAbsences, &t.ConsecutiveAbsences},
+	}
+	for _, v := range ints {
+		if v.value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v.value)
+		if err != nil || n < 1 {
+			return t, fmt.Errorf("%s: %q is not a positive number", v.name, v.value)
+		}
+		*v.into = n
+	}
+	if minPercentage != "" {
+		p, err := strconv.ParseFloat(minPercentage, 64)
+		if err != nil || p < 0 || p > 100 {
+			return t, fmt.Errorf("min percentage: %q is not between 0 and 100", minPercentage)
+		}
+		t.MinPercentage = p
+	}
+	return t, nil
+}
+
+type AlertService interface {
+	CheckAbsences() (*viewmodels.AlertRunResponse, error)
+}
+
+type alertService struct {
+	repo       repository.AlertRepository
+	attRepo    repository.AttendanceRepository
+	calendar   SchoolCalendar
+	notifier   notify.Multi
+	thresholds AlertThresholds
+}
+
+func NewAlertService(repo repository.AlertRepository, attRepo repository.AttendanceRepository, calendar SchoolCalendar, notifier notify.Multi, thresholds AlertThresholds) AlertService {
+	return &alertService{repo: repo, attRepo: attRepo, calendar: calendar, notifier: notifier, thresholds: thresholds}
+}
+
+// studentAttendance is what the thresholds are checked against
+type studentAttendance struct {
+	student    models.Student
+	counted    int // records, excused ones left out
+	attended   int
+	percentage float64
+	streak     int
+}
+
+// CheckAbsences computes every student's attendance over the window and sends an alert for each
+// threshold newly crossed. A student is alerted once per threshold until it is no longer crossed.
+// Alerts that could not be sent are retried on the next run, only through the channels that failed,
+// and reported in the returned error.
+func (s *alertService) CheckAbsences() (*viewmodels.AlertRunResponse, error) {
+	to := truncateToDay(time.Now()).AddDate(0, 0, -1)
+	from := to.AddDate(0, 0, 1-s.thresholds.WindowDays)
+	records, err := expectedAttendanceBetween(s.attRepo, s.calendar, from, to)
+	if err != nil {
+		return nil, err
+	}
+	active, err := s.repo.GetActive()
+	if err != nil {
+		return nil, err
+	}
+	alerted := make(map[alertKey]models.AbsenceAlert, len(active))
+	for _, a := range active {
+		alerted[alertKey{a.StudentID, a.Kind}] = a
+	}
+
+	resp := &viewmodels.AlertRunResponse{From: from, To: to}
+	var failures []error
+	for _, st := range attendanceByStudent(records) {
+		resp.Checked++
+		for _, kind := range s.crossed(st) {
+			key := alertKey{st.student.ID, kind}
+			active, sent := alerted[key]
+			if sent {
+				delete(alerted, key) // still crossed: keep it active
+				if len(active.Pending) == 0 {
+					continue
+				}
+			}
+			var err error
+			if sent {
+				err = s.retry(active, st, from, to)
+			} else {
+				err = s.send(kind, st, from, to)
+			}
+			if err != nil {
+				resp.Failed++
+				failures = append(failures, err)
+				continue
+			}
+			resp.Sent++
+		}
+	}
+
+	// whatever is left is no longer crossed, or the student had no record in the window
+	var resolved []uint
+	for _, a := range alerted {
+		resolved = append(resolved, a.ID)
+	}
+	if err := s.repo.Resolve(resolved, time.Now()); err != nil {
+		return nil, err
+	}
+	resp.Resolved = len(resolved)
+
+	if len(failures) > 0 {
+		return resp, fmt.Errorf("%d of %d alerts could not be sent: %w", resp.Failed, resp.Sent+resp.Failed, errors.Join(failures...))
+	}
+	return resp, nil
+}
+
+type alertKey struct {
+	studentID uint
+	kind      string
+}
+
+// crossed lists the kinds of alert the student's attendance calls for
+func (s *alertService) crossed(st studentAttendance) []string {
+	var kinds []string
+	if st.counted >= s.thresholds.MinRecords && st.percentage < s.thresholds.MinPercentage {
+		kinds = append(kinds, models.AlertLowAttendance)
+	}
+	if st.streak >= s.thresholds.ConsecutiveAbsences {
+		kinds = append(kinds, models.AlertConsecutiveAbsences)
+	}
+	return kinds
+}
+
+// send notifies through every channel, then records the alert so it is not sent again, with the
+// channels that failed left pending. Nothing is recorded if every channel failed.
+func (s *alertService) send(kind string, st studentAttendance, from, to time.Time) error {
+	failed, err := s.notifier.NotifyOnly(s.alert(kind, st, from, to), nil)
+	if err != nil && len(failed) == len(s.notifier) {
+		return err
+	}
+
+	if aerr := s.repo.Activate(&models.AbsenceAlert{
+		StudentID:           st.student.ID,
+		Kind:                kind,
+		Percentage:          st.percentage,
+		ConsecutiveAbsences: st.streak,
+		SentAt:              time.Now(),
+		TimesSent:           1,
+		Pending:             failed,
+	}); aerr != nil {
+		return aerr
+	}
+	return err
+}
+
+// retry sends an active alert through the channels it is still pending on, keeping those that fail again
+func (s *alertService) retry(active models.AbsenceAlert, st studentAttendance, from, to time.Time) error {
+	failed, err := s.notifier.NotifyOnly(s.alert(active.Kind, st, from, to), active.Pending)
+	if perr := s.repo.SetPending(active.ID, failed); perr != nil {
+		return perr
+	}
+	return err
+}
+
+// alert describes the crossed threshold to the notifiers
+func (s *alertService) alert(kind string, st studentAttendance, from, to time.Time) notify.Alert {
+	alert := notify.Alert{
+		Kind:                kind,
+		StudentID:           st.student.ID,
+		StudentName:         st.student.Name,
+		StudentEmail:        st.student.Email,
+		Department:          st.student.Department.Name,
+		Percentage:          st.percentage,
+		ConsecutiveAbsences: st.streak,
+		From:                from,
+		To:                  to,
+	}
+	if kind == models.AlertLowAttendance {
+		alert.Message = fmt.Sprintf("Student %s (ID: %d) attended %.2f%% between %s and %s, below %.2f%%.",
+			st.student.Name, st.student.ID, st.percentage, from.Format("2006-01-02"), to.Format("2006-01-02"), s.thresholds.MinPercentage)
+	} else {
+		alert.Message = fmt.Sprintf("Student %s (ID: %d) was absent %d school days in a row up to %s.",
+			st.student.Name, st.student.ID, st.streak, to.Format("2006-01-02"))
+	}
+	return alert
+}
+
+// attendanceByStudent computes each student's percentage and current streak of absent days,
+// ordered by student ID. Excused records count neither way, so approved leave raises no alert.
+// Both count school days, see byDay: a day with any attendance ends the streak.
+func attendanceByStudent(records []models.Attendance) []studentAttendance {
+	byStudent := make(map[uint]*studentAttendance)
+	days := make(map[uint]map[time.Time]bool) // student -> day -> attended
+	for _, rec := range byDay(records) {
+		if rec.Status == "excused" {
+			continue
+		}
+		st, exists := byStudent[rec.StudentID]
+		if !exists {
+			st = &studentAttendance{student: rec.Student}
+			st.student.ID = rec.StudentID
+			byStudent[rec.StudentID] = st
+			days[rec.StudentID] = make(map[time.Time]bool)
+		}
+		st.counted++
+		attended := rec.Status == "present" || rec.Status == "late"
+		if attended {
+			st.attended++
+		}
+		day := truncateToDay(rec.Date)
+		days[rec.StudentID][day] = days[rec.StudentID][day] || attended
+	}
+
+	students := make([]studentAttendance, 0, len(byStudent))
+	for id, st := range byStudent {
+		st.percentage = attendancePercentage(st.attended, st.counted)
+
+		marked := make([]time.Time, 0, len(days[id]))
+		for day := range days[id] {
+			marked = append(marked, day)
+		}
+		sort.Slice(marked, func(i, j int) bool { return marked[i].After(marked[j]) })
+		for _, day := range marked {
+			if days[id][day] {
+				break
+			}
+			st.streak++
+		}
+		students = append(students, *st)
+	}
+	sort.Slice(students, func(i, j int) bool { return students[i].student.ID < students[j].student.ID })
+	return students
+}
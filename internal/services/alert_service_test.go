@@ -0,0 +1,187 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/notify"
+	"hrms_backend/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// --- Mock Alert Repo ---
+type MockAlertRepo struct {
+	mock.Mock
+}
+
+func (m *MockAlertRepo) GetActive() ([]models.AbsenceAlert, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AbsenceAlert), args.Error(1)
+}
+
+func (m *MockAlertRepo) Activate(alert *models.AbsenceAlert) error {
+	args := m.Called(alert)
+	return args.Error(0)
+}
+
+func (m *MockAlertRepo) SetPending(id uint, pending []string) error {
+	args := m.Called(id, pending)
+	return args.Error(0)
+}
+
+func (m *MockAlertRepo) Resolve(ids []uint, at time.Time) error {
+	args := m.Called(ids, at)
+	return args.Error(0)
+}
+
+// recordingNotifier keeps the alerts it was asked to send, failing when err is set
+type recordingNotifier struct {
+	sent []notify.Alert
+	err  error
+}
+
+func (n *recordingNotifier) Notify(alert notify.Alert) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, alert)
+	return nil
+}
+
+// --- Tests ---
+
+func TestParseAlertThresholds(t *testing.T) {
+	thresholds, err := services.ParseAlertThresholds("", "", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, services.DefaultAlertThresholds, thresholds)
+
+	thresholds, err = services.ParseAlertThresholds("14", "80.5", "3", "2")
+	assert.NoError(t, err)
+	assert.Equal(t, services.AlertThresholds{WindowDays: 14, MinPercentage: 80.5, MinRecords: 3, ConsecutiveAbsences: 2}, thresholds)
+
+	_, err = services.ParseAlertThresholds("0", "", "", "")
+	assert.Error(t, err)
+	_, err = services.ParseAlertThresholds("", "120", "", "")
+	assert.Error(t, err)
+}
+
+func TestCheckAbsences(t *testing.T) {
+	mockRepo := new(MockAlertRepo)
+	mockAttRepo := new(MockAttendanceRepo)
+	notifier := &recordingNotifier{}
+	thresholds := services.AlertThresholds{WindowDays: 30, MinPercentage: 75, MinRecords: 4, ConsecutiveAbsences: 3}
+	service := services.NewAlertService(mockRepo, mockAttRepo, fakeCalendar{}, notify.Multi{{Name: "email", Notifier: notifier}}, thresholds)
+
+	daysAgo := func(n int) time.Time { return time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -n) }
+	alice := models.Student{Model: gorm.Model{ID: 1}, Name: "Alice", Email: "alice@school.test"}
+	bob := models.Student{Model: gorm.Model{ID: 2}, Name: "Bob"}
+	carol := models.Student{Model: gorm.Model{ID: 3}, Name: "Carol"}
+	records := []models.Attendance{
+		// Alice: 1 of 4 days attended (25%), missed the last 3 days, one of them in two classes
+		{StudentID: 1, Student: alice, Date: daysAgo(6), Status: "present"},
+		{StudentID: 1, Student: alice, Date: daysAgo(5), Status: "absent"},
+		{StudentID: 1, Student: alice, Date: daysAgo(4), Status: "absent"},
+		{StudentID: 1, Student: alice, Date: daysAgo(3), Status: "absent"},
+		{StudentID: 1, Student: alice, Date: daysAgo(3), Status: "absent"},
+		// Bob: absent 3 days, but on approved leave for the last one: the streak is 2
+		{StudentID: 2, Student: bob, Date: daysAgo(4), Status: "present"},
+		{StudentID: 2, Student: bob, Date: daysAgo(3), Status: "absent"},
+		{StudentID: 2, Student: bob, Date: daysAgo(2), Status: "absent"},
+		{StudentID: 2, Student: bob, Date: daysAgo(1), Status: "excused"},
+		// Carol: late counts as attended
+		{StudentID: 3, Student: carol, Date: daysAgo(2), Status: "late"},
+		{StudentID: 3, Student: carol, Date: daysAgo(1), Status: "absent"},
+	}
+	mockAttRepo.On("GetAttendanceBetween", mock.Anything, mock.Anything).Return(records, nil)
+	mockAttRepo.On("GetUnmarkedSessions", mock.Anything, mock.Anything).Return([]models.Attendance{}, nil)
+
+	// Case 1: Alice crosses both thresholds; Carol's earlier alert is resolved
+	mockRepo.On("GetActive").Return([]models.AbsenceAlert{{ID: 9, StudentID: 3, Kind: models.AlertConsecutiveAbsences}}, nil).Once()
+	mockRepo.On("Activate", mock.MatchedBy(func(a *models.AbsenceAlert) bool {
+		return a.StudentID == 1 && a.Kind == models.AlertLowAttendance && a.Percentage == 25
+	})).Return(nil).Once()
+	mockRepo.On("Activate", mock.MatchedBy(func(a *models.AbsenceAlert) bool {
+		return a.StudentID == 1 && a.Kind == models.AlertConsecutiveAbsences && a.ConsecutiveAbsences == 3
+	})).Return(nil).Once()
+	mockRepo.On("Resolve", []uint{9}, mock.Anything).Return(nil).Once()
+	resp, err := service.CheckAbsences()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, resp.Checked)
+	assert.Equal(t, 2, resp.Sent)
+	assert.Equal(t, 1, resp.Resolved)
+	assert.Len(t, notifier.sent, 2)
+	assert.Equal(t, "alice@school.test", notifier.sent[0].StudentEmail)
+	assert.Equal(t, daysAgo(1), resp.To)
+	assert.Equal(t, daysAgo(30), resp.From)
+
+	// Case 2: Next run, both alerts still active: nothing is sent again
+	notifier.sent = nil
+	mockRepo.On("GetActive").Return([]models.AbsenceAlert{
+		{ID: 10, StudentID: 1, Kind: models.AlertLowAttendance},
+		{ID: 11, StudentID: 1, Kind: models.AlertConsecutiveAbsences},
+	}, nil).Once()
+	mockRepo.On("Resolve", []uint(nil), mock.Anything).Return(nil).Once()
+	resp, err = service.CheckAbsences()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resp.Sent)
+	assert.Empty(t, notifier.sent)
+
+	// Case 3: Notifier down: the run fails and nothing is recorded, so it is retried
+	notifier.err = errors.New("smtp down")
+	mockRepo.On("GetActive").Return([]models.AbsenceAlert{}, nil).Once()
+	mockRepo.On("Resolve", []uint(nil), mock.Anything).Return(nil).Once()
+	resp, err = service.CheckAbsences()
+	assert.ErrorContains(t, err, "smtp down")
+	assert.Equal(t, 2, resp.Failed)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCheckAbsencesPartialDelivery(t *testing.T) {
+	mockRepo := new(MockAlertRepo)
+	mockAttRepo := new(MockAttendanceRepo)
+	email, hook := &recordingNotifier{}, &recordingNotifier{err: errors.New("webhook down")}
+	thresholds := services.AlertThresholds{WindowDays: 30, MinPercentage: 75, MinRecords: 100, ConsecutiveAbsences: 3}
+	service := services.NewAlertService(mockRepo, mockAttRepo, fakeCalendar{},
+		notify.Multi{{Name: "email", Notifier: email}, {Name: "webhook", Notifier: hook}}, thresholds)
+
+	daysAgo := func(n int) time.Time { return time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -n) }
+	alice := models.Student{Model: gorm.Model{ID: 1}, Name: "Alice"}
+	mockAttRepo.On("GetAttendanceBetween", mock.Anything, mock.Anything).Return([]models.Attendance{
+		{StudentID: 1, Student: alice, Date: daysAgo(3), Status: "absent"},
+		{StudentID: 1, Student: alice, Date: daysAgo(2), Status: "absent"},
+		{StudentID: 1, Student: alice, Date: daysAgo(1), Status: "absent"},
+	}, nil)
+	mockAttRepo.On("GetUnmarkedSessions", mock.Anything, mock.Anything).Return([]models.Attendance{}, nil)
+	mockRepo.On("Resolve", []uint(nil), mock.Anything).Return(nil)
+
+	// Case 1: Email goes out, the webhook fails: recorded with the webhook pending
+	mockRepo.On("GetActive").Return([]models.AbsenceAlert{}, nil).Once()
+	mockRepo.On("Activate", mock.MatchedBy(func(a *models.AbsenceAlert) bool {
+		return a.StudentID == 1 && a.Kind == models.AlertConsecutiveAbsences && assert.ObjectsAreEqual([]string{"webhook"}, a.Pending)
+	})).Return(nil).Once()
+	resp, err := service.CheckAbsences()
+	assert.ErrorContains(t, err, "webhook down")
+	assert.Equal(t, 1, resp.Failed)
+	assert.Len(t, email.sent, 1)
+
+	// Case 2: Next run, the webhook is back: only it is retried, no second email
+	hook.err = nil
+	mockRepo.On("GetActive").Return([]models.AbsenceAlert{
+		{ID: 4, StudentID: 1, Kind: models.AlertConsecutiveAbsences, Active: true, Pending: []string{"webhook"}},
+	}, nil).Once()
+	mockRepo.On("SetPending", uint(4), []string(nil)).Return(nil).Once()
+	resp, err = service.CheckAbsences()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.Sent)
+	assert.Len(t, email.sent, 1)
+	assert.Len(t, hook.sent, 1)
+	mockRepo.AssertExpectations(t)
+}
@@ -1,110 +1,647 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"hrms_backend/internal/models"
 	"hrms_backend/internal/repository"
 	"hrms_backend/internal/viewmodels"
 	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAttendanceConflict is returned when the student already has a record for that day with a different status.
+	ErrAttendanceConflict = &Error{Kind: ErrConflict, Code: "attendance_conflict", Message: "attendance already marked with a different status for this date"}
+	// ErrEmptyBulkRequest is returned when a bulk request names neither records nor a department.
+	ErrEmptyBulkRequest = &Error{Kind: ErrValidation, Code: "empty_bulk_request", Message: "either records or department_id is required"}
+	// ErrAttendanceNotFound is returned when an attendance record ID does not exist.
+	ErrAttendanceNotFound = &Error{Kind: ErrNotFound, Code: "attendance_not_found", Message: "attendance record not found"}
+	// ErrIdempotencyKeyReused is returned when an Idempotency-Key is replayed with a different payload.
+	ErrIdempotencyKeyReused = &Error{Kind: ErrConflict, Code: "idempotency_key_reused", Message: "idempotency key already used for a different request"}
+	// ErrStatusRequired is returned when a record has no status and none can be derived from a check-in.
+	ErrStatusRequired = &Error{
+		Kind:    ErrValidation,
+		Code:    "status_required",
+		Message: "status is required unless check_in_at falls on a class session of section_id",
+		Fields:  map[string]string{"status": "required"},
+	}
 )
 
 type AttendanceService interface {
-	MarkAttendance(req viewmodels.CreateAttendanceRequest) error
-	GetAttendanceByStudentID(studentID uint) ([]viewmodels.AttendanceResponse, error)
-	GetWeeklyAttendance() ([]viewmodels.AttendanceResponse, error)
+	MarkAttendance(req viewmodels.CreateAttendanceRequest, idempotencyKey string, actor Actor) (*viewmodels.AttendanceResponse, bool, error)
+	MarkBulkAttendance(req viewmodels.BulkAttendanceRequest, actor Actor) (*viewmodels.BulkAttendanceResponse, error)
+	UpdateAttendance(id uint, req viewmodels.UpdateAttendanceRequest, actor Actor) (*viewmodels.AttendanceResponse, error)
+	CheckOut(id uint, req viewmodels.CheckOutRequest, actor Actor) (*viewmodels.AttendanceResponse, error)
+	DeleteAttendance(id uint, req viewmodels.DeleteAttendanceRequest, actor Actor) error
+	GetAttendanceHistory(id uint) ([]viewmodels.AttendanceCorrectionResponse, error)
+	ListAttendance(query viewmodels.AttendanceListQuery) (*viewmodels.AttendanceListResponse, error)
+	GetAttendanceByStudentID(studentID uint, query viewmodels.AttendanceListQuery) (*viewmodels.AttendanceListResponse, error)
+	GetAttendanceSummaryByStudentID(studentID uint, from, to time.Time) (*viewmodels.ReportRowResponse, error)
 }
 
 type attendanceService struct {
 	attRepo     repository.AttendanceRepository
 	studentRepo repository.StudentRepository // Dependency injected for Logic Check
+	calendar    SchoolCalendar               // no marking on days without classes
+	tx          repository.TxManager         // checks and writes that must not interleave with other requests
 }
 
-// Constructor: Requires both repositories
-func NewAttendanceService(attRepo repository.AttendanceRepository, studentRepo repository.StudentRepository) AttendanceService {
+// Constructor: Requires both repositories, the school calendar and the transaction manager,
+// whose repositories also check enrollment in sections
+func NewAttendanceService(attRepo repository.AttendanceRepository, studentRepo repository.StudentRepository, calendar SchoolCalendar, tx repository.TxManager) AttendanceService {
 	return &attendanceService{
 		attRepo:     attRepo,
 		studentRepo: studentRepo,
+		calendar:    calendar,
+		tx:          tx,
 	}
 }
 
-// MarkAttendance handles the business logic for creating attendance
-func (s *attendanceService) MarkAttendance(req viewmodels.CreateAttendanceRequest) error {
+// MarkAttendance handles the business logic for creating attendance.
+// It is idempotent per student and day, or per student, day and section when req.SectionID is set:
+// replaying the same status returns the stored record (created == false), while a different status
+// yields ErrAttendanceConflict. Per section records require the student to be enrolled in the section.
+// Weekends, holidays, closures and days outside every term are rejected with ErrNonSchoolDay.
+// With a check-in time on a class session of the section, the status may be left out: it is
+// derived from the session's late cutoff, and late records keep how many minutes late they were.
+func (s *attendanceService) MarkAttendance(req viewmodels.CreateAttendanceRequest, idempotencyKey string, actor Actor) (*viewmodels.AttendanceResponse, bool, error) {
+	day := truncateToDay(req.Date)
+
+	var attendance models.Attendance
+	created := false
+	err := s.tx.WithTx(context.Background(), func(repos repository.Repos) error {
+		// A retried request with a known key resolves to the record it created the first time
+		if idempotencyKey != "" {
+			existing, err := repos.Attendance.GetByIdempotencyKey(idempotencyKey)
+			if err == nil {
+				if existing.StudentID != req.StudentID || !existing.Date.Equal(day) ||
+					(req.Status != "" && existing.Status != req.Status) || sectionKey(existing.SectionID) != req.SectionID {
+					return ErrIdempotencyKeyReused
+				}
+				attendance = *existing
+				return nil
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+
+		if err := s.checkSchoolDay(day); err != nil {
+			return err
+		}
+
+		// STEP D: Logic Check - Verify Student Exists
+		// The student, enrollment and sessions stay locked until the record is stored,
+		// so a concurrent delete or unenroll cannot slip in between.
+		if _, err := repos.Students.GetByID(req.StudentID); err != nil {
+			return translateDBError(err, ErrStudentNotFound, nil)
+		}
+		if req.SectionID != 0 {
+			if err := checkEnrolled(repos.Sections, req.SectionID, req.StudentID); err != nil {
+				return err
+			}
+		}
+		if err := checkTimesOn(day, req.CheckInAt, req.CheckOutAt); err != nil {
+			return err
+		}
+		status, lateMinutes, err := arrivalStatus(repos.Attendance, req, day)
+		if err != nil {
+			return err
+		}
+
+		// Logic Check Passed: Create the Model
+		attendance = models.Attendance{
+			StudentID:   req.StudentID,
+			Date:        day,
+			Status:      status,
+			CheckInAt:   req.CheckInAt,
+			CheckOutAt:  req.CheckOutAt,
+			LateMinutes: lateMinutes,
+		}
+		if req.SectionID != 0 {
+			attendance.SectionID = &req.SectionID
+		}
+		if idempotencyKey != "" {
+			attendance.IdempotencyKey = &idempotencyKey
+		}
 
-	// STEP D: Logic Check - Verify Student Exists
-	// We use s.studentRepo.GetByID to ensure we don't mark attendance for a non-existent ID.
-	_, err := s.studentRepo.GetByID(req.StudentID)
+		// Persist, or load the record already stored for this student, day and section
+		created, err = repos.Attendance.FirstOrCreate(&attendance, actor.auditEntry(models.AuditActionCreate, models.AuditEntityAttendance))
+		if err != nil {
+			// a concurrent request with the same key stored a record for another student, day or section
+			return translateDBError(err, nil, ErrIdempotencyKeyReused)
+		}
+		if !created && attendance.Status != status {
+			return ErrAttendanceConflict
+		}
+		return nil
+	})
 	if err != nil {
-		return errors.New("student not found: cannot mark attendance")
+		return nil, false, err
+	}
+
+	resp := toAttendanceResponse(attendance)
+	return &resp, created, nil
+}
+
+// MarkBulkAttendance marks a whole class for one day, as whole day records.
+// Students are validated with a single batched lookup and all new rows are inserted in one
+// transaction. Rows that cannot be marked (unknown student, conflicting status, duplicates in
+// the request) are reported individually instead of failing the whole request. A day without
+// classes fails it with ErrNonSchoolDay.
+func (s *attendanceService) MarkBulkAttendance(req viewmodels.BulkAttendanceRequest, actor Actor) (*viewmodels.BulkAttendanceResponse, error) {
+	day := truncateToDay(req.Date)
+	if err := s.checkSchoolDay(day); err != nil {
+		return nil, err
 	}
 
-	// Logic Check Passed: Create the Model
-	attendance := models.Attendance{
-		StudentID: req.StudentID,
-		Date:      req.Date,
-		Status:    req.Status,
+	// The students and their records stay locked until the new rows are stored
+	var results []viewmodels.BulkAttendanceResult
+	err := s.tx.WithTx(context.Background(), func(repos repository.Repos) error {
+		var err error
+		results, err = markBulk(repos, req, day, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Persist
-	return s.attRepo.Create(&attendance)
+	resp := viewmodels.BulkAttendanceResponse{Date: day, Results: results}
+	for _, r := range results {
+		if r.Success {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	return &resp, nil
 }
 
-// GetAttendanceByStudentID fetches records and maps them to ViewModels
-func (s *attendanceService) GetAttendanceByStudentID(studentID uint) ([]viewmodels.AttendanceResponse, error) {
-	// 1. Verify student exists (Optional, but good practice)
-	if _, err := s.studentRepo.GetByID(studentID); err != nil {
-		return nil, errors.New("student not found")
+// markBulk stores the rows of a bulk request through repos and returns the outcome of every row
+func markBulk(repos repository.Repos, req viewmodels.BulkAttendanceRequest, day time.Time, actor Actor) ([]viewmodels.BulkAttendanceResult, error) {
+	// 1. Resolve the rows to mark and the students they refer to
+	var entries []bulkEntry
+	var students []models.Student
+	var err error
+	switch {
+	case req.DepartmentID != 0:
+		students, err = repos.Students.GetByDepartment(req.DepartmentID)
+		if err != nil {
+			return nil, err
+		}
+		entries = departmentEntries(students, req)
+	case len(req.Records) > 0:
+		ids := make([]uint, 0, len(req.Records))
+		for _, rec := range req.Records {
+			entries = append(entries, bulkEntry{BulkAttendanceEntry: rec})
+			ids = append(ids, rec.StudentID)
+		}
+		students, err = repos.Students.GetByIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrEmptyBulkRequest
 	}
 
-	// 2. Fetch data
-	records, err := s.attRepo.GetAttendanceByStudentID(studentID)
+	known := make(map[uint]bool, len(students))
+	ids := make([]uint, 0, len(students))
+	for _, st := range students {
+		known[st.ID] = true
+		ids = append(ids, st.ID)
+	}
+
+	// 2. Load what is already stored for that day so replays stay idempotent
+	stored, err := repos.Attendance.GetByStudentsAndDate(ids, day)
 	if err != nil {
 		return nil, err
 	}
+	existing := make(map[uint]models.Attendance, len(stored))
+	for _, rec := range stored {
+		existing[rec.StudentID] = rec
+	}
 
-	// 3. Convert to DTOs
-	responses := make([]viewmodels.AttendanceResponse, 0, len(records))
-	for _, rec := range records {
-		resp := viewmodels.AttendanceResponse{
-			ID:        rec.ID,
-			StudentID: rec.StudentID,
-			Date:      rec.Date,
-			Status:    rec.Status,
+	// 3. Decide the outcome of every row
+	results := make([]viewmodels.BulkAttendanceResult, len(entries))
+	toCreate := make([]models.Attendance, 0, len(entries))
+	createdIdx := make([]int, 0, len(entries))
+	seen := make(map[uint]bool, len(entries))
+	for i, e := range entries {
+		results[i] = viewmodels.BulkAttendanceResult{StudentID: e.StudentID, Status: e.Status}
+		switch {
+		case e.err != "":
+			results[i].Error = e.err
+		case seen[e.StudentID]:
+			results[i].Error = "duplicate student in request"
+		case !known[e.StudentID]:
+			results[i].Error = "student not found"
+		default:
+			if rec, ok := existing[e.StudentID]; ok {
+				if rec.Status != e.Status {
+					results[i].Error = ErrAttendanceConflict.Error()
+				} else {
+					results[i].Success = true
+					results[i].AttendanceID = rec.ID
+				}
+			} else {
+				toCreate = append(toCreate, models.Attendance{StudentID: e.StudentID, Date: day, Status: e.Status})
+				createdIdx = append(createdIdx, i)
+			}
+		}
+		seen[e.StudentID] = true
+	}
+
+	// 4. Persist all new rows in one transaction
+	if err := repos.Attendance.CreateBatch(toCreate, actor.auditEntry(models.AuditActionCreate, models.AuditEntityAttendance)); err != nil {
+		return nil, err
+	}
+	for j, i := range createdIdx {
+		results[i].Success = true
+		results[i].Created = true
+		results[i].AttendanceID = toCreate[j].ID
+	}
+	return results, nil
+}
+
+// UpdateAttendance corrects the status of a record. The previous status and the reason
+// are kept as a correction entry so the change stays traceable.
+func (s *attendanceService) UpdateAttendance(id uint, req viewmodels.UpdateAttendanceRequest, actor Actor) (*viewmodels.AttendanceResponse, error) {
+	var attendance *models.Attendance
+	err := s.tx.WithTx(context.Background(), func(repos repository.Repos) error {
+		var err error
+		attendance, err = getAttendance(repos.Attendance, id)
+		if err != nil {
+			return err
+		}
+
+		// nothing to correct
+		if attendance.Status == req.Status {
+			return nil
+		}
+
+		correction := newCorrection(attendance, "update", req.Reason)
+		correction.NewStatus = req.Status
+		attendance.Status = req.Status
+		if req.Status != "late" {
+			attendance.LateMinutes = 0
+		}
+		return repos.Attendance.UpdateStatus(attendance, &correction, actor.auditEntry(models.AuditActionUpdate, models.AuditEntityAttendance))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toAttendanceResponse(*attendance)
+	return &resp, nil
+}
+
+// CheckOut records when the student left; it must be on the record's day and after the check-in
+func (s *attendanceService) CheckOut(id uint, req viewmodels.CheckOutRequest, actor Actor) (*viewmodels.AttendanceResponse, error) {
+	var attendance *models.Attendance
+	err := s.tx.WithTx(context.Background(), func(repos repository.Repos) error {
+		var err error
+		attendance, err = getAttendance(repos.Attendance, id)
+		if err != nil {
+			return err
+		}
+		if err := checkTimesOn(attendance.Date, attendance.CheckInAt, &req.CheckOutAt); err != nil {
+			return err
+		}
+
+		attendance.CheckOutAt = &req.CheckOutAt
+		return repos.Attendance.SetCheckOut(attendance, actor.auditEntry(models.AuditActionUpdate, models.AuditEntityAttendance))
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := toAttendanceResponse(*attendance)
+	return &resp, nil
+}
+
+// DeleteAttendance removes a record, keeping its last status and the reason in the correction history
+func (s *attendanceService) DeleteAttendance(id uint, req viewmodels.DeleteAttendanceRequest, actor Actor) error {
+	return s.tx.WithTx(context.Background(), func(repos repository.Repos) error {
+		attendance, err := getAttendance(repos.Attendance, id)
+		if err != nil {
+			return err
 		}
-		// If the Student relation was preloaded in the repo, we can map the name
-		if rec.Student.Name != "" {
-			resp.StudentName = rec.Student.Name
+
+		correction := newCorrection(attendance, "delete", req.Reason)
+		return repos.Attendance.Delete(attendance, &correction, actor.auditEntry(models.AuditActionDelete, models.AuditEntityAttendance))
+	})
+}
+
+// GetAttendanceHistory lists the corrections of a record; it still works after the record is deleted
+func (s *attendanceService) GetAttendanceHistory(id uint) ([]viewmodels.AttendanceCorrectionResponse, error) {
+	corrections, err := s.attRepo.GetCorrections(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(corrections) == 0 {
+		// no history: only valid if the record itself exists
+		if _, err := getAttendance(s.attRepo, id); err != nil {
+			return nil, err
 		}
-		responses = append(responses, resp)
 	}
 
+	responses := make([]viewmodels.AttendanceCorrectionResponse, 0, len(corrections))
+	for _, c := range corrections {
+		responses = append(responses, viewmodels.AttendanceCorrectionResponse{
+			ID:             c.ID,
+			AttendanceID:   c.AttendanceID,
+			Action:         c.Action,
+			PreviousStatus: c.PreviousStatus,
+			NewStatus:      c.NewStatus,
+			Reason:         c.Reason,
+			CreatedAt:      c.CreatedAt,
+		})
+	}
 	return responses, nil
 }
 
-func (s *attendanceService) GetWeeklyAttendance() ([]viewmodels.AttendanceResponse, error) {
-	sevenDaysAgo := time.Now().AddDate(0, 0, -7)
+// arrivalStatus returns the status of a new record and, if late, by how many minutes.
+// A check-in on a class session of the section is compared to the session's start and grace
+// period; an explicit status still wins, e.g. to mark a late arrival as excused.
+func arrivalStatus(repo repository.AttendanceRepository, req viewmodels.CreateAttendanceRequest, day time.Time) (string, int, error) {
+	var session *models.ClassSession
+	if req.CheckInAt != nil && req.SectionID != 0 {
+		sessions, err := repo.GetSessionsOn(req.SectionID, day)
+		if err != nil {
+			return "", 0, err
+		}
+		session = sessionAt(sessions, *req.CheckInAt)
+	}
+	if session == nil {
+		if req.Status == "" {
+			return "", 0, ErrStatusRequired
+		}
+		return req.Status, 0, nil
+	}
+
+	minutes, late := lateness(*session, *req.CheckInAt)
+	status := req.Status
+	if status == "" {
+		status = "present"
+		if late {
+			status = "late"
+		}
+	}
+	if status != "late" {
+		minutes = 0
+	}
+	return status, minutes, nil
+}
 
-	records, err := s.attRepo.GetAttendanceSince(sevenDaysAgo)
+// sessionAt picks the session a check-in is for: the first one not over yet at that time,
+// otherwise the last one of the day
+func sessionAt(sessions []models.ClassSession, checkIn time.Time) *models.ClassSession {
+	if len(sessions) == 0 {
+		return nil
+	}
+	clock := checkIn.Format("15:04")
+	for i := range sessions {
+		if clock < sessions[i].EndTime {
+			return &sessions[i]
+		}
+	}
+	return &sessions[len(sessions)-1]
+}
+
+// lateness returns how many whole minutes after the session's start the check-in was, and whether
+// that is past the grace period. Session times are wall clock times in the check-in's own offset.
+func lateness(session models.ClassSession, checkIn time.Time) (int, bool) {
+	start, err := time.ParseInLocation("15:04", session.StartTime, checkIn.Location())
+	if err != nil {
+		return 0, false
+	}
+	y, m, d := checkIn.Date()
+	start = time.Date(y, m, d, start.Hour(), start.Minute(), 0, 0, checkIn.Location())
+
+	after := checkIn.Sub(start)
+	if after <= 0 {
+		return 0, false
+	}
+	return int(after / time.Minute), after > time.Duration(session.LateAfterMinutes)*time.Minute
+}
+
+// checkTimesOn verifies that check-in and check-out (either may be nil) fall on day, in that order
+func checkTimesOn(day time.Time, checkIn, checkOut *time.Time) error {
+	if checkIn != nil && !truncateToDay(*checkIn).Equal(day) {
+		return ValidationError("check_in_at", "must be on the day of the record")
+	}
+	if checkOut != nil && !truncateToDay(*checkOut).Equal(day) {
+		return ValidationError("check_out_at", "must be on the day of the record")
+	}
+	if checkIn != nil && checkOut != nil && !checkOut.After(*checkIn) {
+		return ValidationError("check_out_at", "must be after check_in_at")
+	}
+	return nil
+}
+
+// checkEnrolled verifies that the section exists and the student is enrolled in it
+func checkEnrolled(sections repository.SectionRepository, sectionID, studentID uint) error {
+	if _, err := sections.GetByID(sectionID); err != nil {
+		return translateDBError(err, ErrUnknownSection, nil)
+	}
+	enrolled, err := sections.IsEnrolled(sectionID, studentID)
+	if err != nil {
+		return err
+	}
+	if !enrolled {
+		return ErrStudentNotEnrolled
+	}
+	return nil
+}
+
+func getAttendance(repo repository.AttendanceRepository, id uint) (*models.Attendance, error) {
+	attendance, err := repo.GetByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrAttendanceNotFound, nil)
+	}
+	return attendance, nil
+}
+
+func newCorrection(attendance *models.Attendance, action, reason string) models.AttendanceCorrection {
+	return models.AttendanceCorrection{
+		AttendanceID:   attendance.ID,
+		StudentID:      attendance.StudentID,
+		Date:           attendance.Date,
+		Action:         action,
+		PreviousStatus: attendance.Status,
+		Reason:         reason,
+	}
+}
+
+// ListAttendance returns a filtered, sorted page of attendance records
+func (s *attendanceService) ListAttendance(query viewmodels.AttendanceListQuery) (*viewmodels.AttendanceListResponse, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 {
+		query.Limit = 20
+	}
+
+	filter := repository.AttendanceFilter{
+		StudentID:    query.StudentID,
+		DepartmentID: query.DepartmentID,
+		SectionID:    query.SectionID,
+		Status:       query.Status,
+		From:         query.From,
+		To:           query.To,
+		Sort:         query.Sort,
+		Limit:        query.Limit + 1, // one extra row tells whether another page follows
+		Offset:       (query.Page - 1) * query.Limit,
+		After:        query.Cursor,
+	}
+	records, total, err := s.attRepo.List(filter)
+	if err != nil {
+		return nil, translateDBError(err, nil, nil)
+	}
+	hasMore := len(records) > query.Limit
+	if hasMore {
+		records = records[:query.Limit]
+	}
+
+	resp := &viewmodels.AttendanceListResponse{
+		Data:  s.mapToResponse(records),
+		Total: total,
+		Limit: query.Limit,
+	}
+	if query.Cursor == "" {
+		resp.Page = query.Page
+	}
+	if hasMore {
+		resp.NextCursor = repository.AttendanceCursor(query.Sort, &records[len(records)-1])
+	}
+	return resp, nil
+}
+
+// GetAttendanceByStudentID returns a page of one student's attendance records, filtered, sorted
+// and paged like ListAttendance; a student_id in the query is ignored
+func (s *attendanceService) GetAttendanceByStudentID(studentID uint, query viewmodels.AttendanceListQuery) (*viewmodels.AttendanceListResponse, error) {
+	if _, err := s.studentRepo.GetByID(studentID); err != nil {
+		return nil, translateDBError(err, ErrStudentNotFound, nil)
+	}
+	query.StudentID = studentID
+	return s.ListAttendance(query)
+}
+
+// GetAttendanceSummaryByStudentID counts a student's school days within [from, to] (a zero from is open,
+// a zero to means today), unmarked class sessions included, exactly like a row of the stored reports
+func (s *attendanceService) GetAttendanceSummaryByStudentID(studentID uint, from, to time.Time) (*viewmodels.ReportRowResponse, error) {
+	student, err := s.studentRepo.GetByID(studentID)
+	if err != nil {
+		return nil, translateDBError(err, ErrStudentNotFound, nil)
+	}
+
+	if to.IsZero() {
+		to = truncateToDay(time.Now())
+	}
+	records, err := expectedAttendanceBetween(s.attRepo, s.calendar, from, to)
 	if err != nil {
 		return nil, err
 	}
+	var own []models.Attendance
+	for _, rec := range records {
+		if rec.StudentID == studentID {
+			own = append(own, rec)
+		}
+	}
 
-	return s.mapToResponse(records), nil
+	summary := viewmodels.ReportRowResponse{StudentID: student.ID, StudentName: student.Name, Department: student.Department.Name}
+	if rows := toReportResponse(models.Report{Rows: summarizeAttendance(own)}).Rows; len(rows) == 1 {
+		summary = rows[0]
+	}
+	return &summary, nil
 }
 
 // to avoid duplication
 func (s *attendanceService) mapToResponse(records []models.Attendance) []viewmodels.AttendanceResponse {
 	responses := make([]viewmodels.AttendanceResponse, 0, len(records))
 	for _, rec := range records {
-		resp := viewmodels.AttendanceResponse{
-			ID:        rec.ID,
-			StudentID: rec.StudentID,
-			Date:      rec.Date,
-			Status:    rec.Status,
+		responses = append(responses, toAttendanceResponse(rec))
+	}
+	return responses
+}
+
+// bulkEntry is a row of a bulk request; err is set when it is already known to be invalid.
+type bulkEntry struct {
+	viewmodels.BulkAttendanceEntry
+	err string
+}
+
+// departmentEntries expands "everyone in the department has DefaultStatus except these".
+// Exceptions naming students outside the department are kept as failed rows.
+func departmentEntries(students []models.Student, req viewmodels.BulkAttendanceRequest) []bulkEntry {
+	status := req.DefaultStatus
+	if status == "" {
+		status = "present"
+	}
+	overrides := make(map[uint]string, len(req.Exceptions))
+	for _, e := range req.Exceptions {
+		overrides[e.StudentID] = e.Status
+	}
+
+	entries := make([]bulkEntry, 0, len(students))
+	for _, st := range students {
+		entry := bulkEntry{BulkAttendanceEntry: viewmodels.BulkAttendanceEntry{StudentID: st.ID, Status: status}}
+		if override, ok := overrides[st.ID]; ok {
+			entry.Status = override
+			delete(overrides, st.ID)
 		}
-		if rec.Student.Name != "" {
-			resp.StudentName = rec.Student.Name
+		entries = append(entries, entry)
+	}
+	for _, e := range req.Exceptions {
+		if _, ok := overrides[e.StudentID]; ok {
+			entries = append(entries, bulkEntry{BulkAttendanceEntry: e, err: "student not in department"})
 		}
-		responses = append(responses, resp)
 	}
-	return responses
+	return entries
+}
+
+func toAttendanceResponse(rec models.Attendance) viewmodels.AttendanceResponse {
+	resp := viewmodels.AttendanceResponse{
+		ID:          rec.ID,
+		StudentID:   rec.StudentID,
+		Date:        rec.Date,
+		Status:      rec.Status,
+		SectionID:   rec.SectionID,
+		CheckInAt:   rec.CheckInAt,
+		CheckOutAt:  rec.CheckOutAt,
+		LateMinutes: rec.LateMinutes,
+	}
+	// If the Student relation was preloaded in the repo, we can map the name
+	if rec.Student.Name != "" {
+		resp.StudentName = rec.Student.Name
+	}
+	return resp
+}
+
+// checkSchoolDay rejects a day without classes
+func (s *attendanceService) checkSchoolDay(day time.Time) error {
+	off, err := s.calendar.NonSchoolDays(day, day)
+	if err != nil {
+		return err
+	}
+	if reason, closed := off[day]; closed {
+		return nonSchoolDayError(reason)
+	}
+	return nil
+}
+
+// sectionKey returns the section of a record, 0 for a whole day record
+func sectionKey(sectionID *uint) uint {
+	if sectionID == nil {
+		return 0
+	}
+	return *sectionID
+}
+
+// truncateToDay drops the time of day so a date maps to a single calendar day.
+// The day is taken in the caller's own offset, then stored as UTC midnight.
+func truncateToDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
 	"hrms_backend/internal/services"
 	"hrms_backend/internal/viewmodels"
 
@@ -24,83 +25,655 @@ func (m *MockAttendanceRepo) Create(attendance *models.Attendance) error {
 	return args.Error(0)
 }
 
-func (m *MockAttendanceRepo) GetAttendanceByStudentID(studentID uint) ([]models.Attendance, error) {
-	args := m.Called(studentID)
+func (m *MockAttendanceRepo) CreateBatch(records []models.Attendance, audit *models.AuditLog) error {
+	args := m.Called(records, audit)
+	return args.Error(0)
+}
+
+func (m *MockAttendanceRepo) FirstOrCreate(attendance *models.Attendance, audit *models.AuditLog) (bool, error) {
+	args := m.Called(attendance, audit)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAttendanceRepo) GetByIdempotencyKey(key string) (*models.Attendance, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Attendance), args.Error(1)
+}
+
+func (m *MockAttendanceRepo) GetByID(id uint) (*models.Attendance, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Attendance), args.Error(1)
+}
+
+func (m *MockAttendanceRepo) UpdateStatus(attendance *models.Attendance, correction *models.AttendanceCorrection, audit *models.AuditLog) error {
+	args := m.Called(attendance, correction, audit)
+	return args.Error(0)
+}
+
+func (m *MockAttendanceRepo) Delete(attendance *models.Attendance, correction *models.AttendanceCorrection, audit *models.AuditLog) error {
+	args := m.Called(attendance, correction, audit)
+	return args.Error(0)
+}
+
+func (m *MockAttendanceRepo) GetCorrections(attendanceID uint) ([]models.AttendanceCorrection, error) {
+	args := m.Called(attendanceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AttendanceCorrection), args.Error(1)
+}
+
+func (m *MockAttendanceRepo) List(filter repository.AttendanceFilter) ([]models.Attendance, int64, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]models.Attendance), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAttendanceRepo) GetByStudentsAndDate(studentIDs []uint, date time.Time) ([]models.Attendance, error) {
+	args := m.Called(studentIDs, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Attendance), args.Error(1)
+}
+
+func (m *MockAttendanceRepo) GetAttendanceBetween(from, to time.Time) ([]models.Attendance, error) {
+	args := m.Called(from, to)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.Attendance), args.Error(1)
 }
 
-func (m *MockAttendanceRepo) GetAttendanceSince(date time.Time) ([]models.Attendance, error) {
-	args := m.Called(date)
+func (m *MockAttendanceRepo) GetUnmarkedSessions(from, to time.Time) ([]models.Attendance, error) {
+	args := m.Called(from, to)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.Attendance), args.Error(1)
 }
 
+func (m *MockAttendanceRepo) GetSessionsOn(sectionID uint, date time.Time) ([]models.ClassSession, error) {
+	args := m.Called(sectionID, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ClassSession), args.Error(1)
+}
+
+func (m *MockAttendanceRepo) SetCheckOut(attendance *models.Attendance, audit *models.AuditLog) error {
+	args := m.Called(attendance, audit)
+	return args.Error(0)
+}
+
 // --- Tests ---
 
 func TestMarkAttendance(t *testing.T) {
 	mockAttRepo := new(MockAttendanceRepo)
 	mockStudentRepo := new(MockStudentRepo) // Reusing the mock from student_service_test.go
-	service := services.NewAttendanceService(mockAttRepo, mockStudentRepo)
+	service := services.NewAttendanceService(mockAttRepo, mockStudentRepo, fakeCalendar{}, fakeTx{Students: mockStudentRepo, Attendance: mockAttRepo})
 
-	req := viewmodels.CreateAttendanceRequest{StudentID: 1, Date: time.Now(), Status: "present"}
+	req := viewmodels.CreateAttendanceRequest{StudentID: 1, Date: time.Date(2025, 12, 12, 9, 30, 0, 0, time.UTC), Status: "present"}
+	day := time.Date(2025, 12, 12, 0, 0, 0, 0, time.UTC)
 
 	// Case 1: Success
 	// Expect check for student existence first
 	mockStudentRepo.On("GetByID", uint(1)).Return(&models.Student{Model: gorm.Model{ID: 1}}, nil).Once()
-	// Then expect create attendance
-	mockAttRepo.On("Create", mock.AnythingOfType("*models.Attendance")).Return(nil).Once()
+	// Then expect create attendance, with the date truncated to the day
+	mockAttRepo.On("FirstOrCreate", mock.MatchedBy(func(a *models.Attendance) bool {
+		return a.Date.Equal(day)
+	}), mock.Anything).Return(true, nil).Once()
 
-	err := service.MarkAttendance(req)
+	resp, created, err := service.MarkAttendance(req, "", services.Actor{})
 	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "present", resp.Status)
 
 	// Case 2: Student Not Found
-	mockStudentRepo.On("GetByID", uint(1)).Return(nil, errors.New("not found")).Once()
-	err = service.MarkAttendance(req)
+	mockStudentRepo.On("GetByID", uint(1)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, _, err = service.MarkAttendance(req, "", services.Actor{})
+	assert.ErrorIs(t, err, services.ErrStudentNotFound)
+	assert.ErrorIs(t, err, services.ErrNotFound)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestMarkAttendanceInTransaction(t *testing.T) {
+	// The plain repositories have no expectations: the key lookup, the student and enrollment checks,
+	// the session lookup and the insert must all go through the transaction
+	txAttRepo := new(MockAttendanceRepo)
+	txStudentRepo := new(MockStudentRepo)
+	txSectionRepo := new(MockSectionRepo)
+	service := services.NewAttendanceService(new(MockAttendanceRepo), new(MockStudentRepo), fakeCalendar{},
+		fakeTx{Students: txStudentRepo, Attendance: txAttRepo, Sections: txSectionRepo})
+
+	day := time.Date(2025, 12, 12, 0, 0, 0, 0, time.UTC)
+	checkIn := day.Add(9 * time.Hour)
+	req := viewmodels.CreateAttendanceRequest{StudentID: 1, Date: day, SectionID: 4, CheckInAt: &checkIn}
+	txAttRepo.On("GetByIdempotencyKey", "tx-key").Return(nil, gorm.ErrRecordNotFound).Once()
+	txStudentRepo.On("GetByID", uint(1)).Return(&models.Student{Model: gorm.Model{ID: 1}}, nil).Once()
+	txSectionRepo.On("GetByID", uint(4)).Return(&models.Section{ID: 4}, nil).Once()
+	txSectionRepo.On("IsEnrolled", uint(4), uint(1)).Return(true, nil).Once()
+	txAttRepo.On("GetSessionsOn", uint(4), day).Return([]models.ClassSession{{SectionID: 4, Date: day, StartTime: "09:00", EndTime: "10:00"}}, nil).Once()
+	txAttRepo.On("FirstOrCreate", mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	resp, created, err := service.MarkAttendance(req, "tx-key", services.Actor{})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "present", resp.Status)
+	txStudentRepo.AssertExpectations(t)
+	txAttRepo.AssertExpectations(t)
+	txSectionRepo.AssertExpectations(t)
+}
+
+func TestMarkAttendanceOnNonSchoolDay(t *testing.T) {
+	mockAttRepo := new(MockAttendanceRepo)
+	mockStudentRepo := new(MockStudentRepo)
+	day := time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC)
+	service := services.NewAttendanceService(mockAttRepo, mockStudentRepo, fakeCalendar{day: "holiday: Christmas"}, fakeTx{Students: mockStudentRepo, Attendance: mockAttRepo})
+
+	// Case 1: Single record, rejected with the reason
+	_, _, err := service.MarkAttendance(viewmodels.CreateAttendanceRequest{StudentID: 1, Date: day.Add(9 * time.Hour), Status: "present"}, "", services.Actor{})
+	assert.ErrorIs(t, err, services.ErrNonSchoolDay)
+	assert.ErrorIs(t, err, services.ErrValidation)
+	var domainErr *services.Error
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "holiday: Christmas", domainErr.Fields["date"])
+
+	// Case 2: Bulk, the whole request is rejected
+	_, err = service.MarkBulkAttendance(viewmodels.BulkAttendanceRequest{Date: day, DepartmentID: 1}, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrNonSchoolDay)
+	mockStudentRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+	mockAttRepo.AssertNotCalled(t, "FirstOrCreate", mock.Anything, mock.Anything)
+}
+
+func TestMarkAttendanceInSection(t *testing.T) {
+	mockAttRepo := new(MockAttendanceRepo)
+	mockStudentRepo := new(MockStudentRepo)
+	mockSectionRepo := new(MockSectionRepo)
+	service := services.NewAttendanceService(mockAttRepo, mockStudentRepo, fakeCalendar{}, fakeTx{Students: mockStudentRepo, Attendance: mockAttRepo, Sections: mockSectionRepo})
+
+	req := viewmodels.CreateAttendanceRequest{StudentID: 1, Date: time.Date(2025, 12, 12, 9, 0, 0, 0, time.UTC), Status: "late", SectionID: 4}
+	mockStudentRepo.On("GetByID", uint(1)).Return(&models.Student{Model: gorm.Model{ID: 1}}, nil)
+
+	// Case 1: Enrolled -> record tied to the section
+	mockSectionRepo.On("GetByID", uint(4)).Return(&models.Section{ID: 4}, nil).Once()
+	mockSectionRepo.On("IsEnrolled", uint(4), uint(1)).Return(true, nil).Once()
+	mockAttRepo.On("FirstOrCreate", mock.MatchedBy(func(a *models.Attendance) bool {
+		return a.SectionID != nil && *a.SectionID == 4
+	}), mock.Anything).Return(true, nil).Once()
+	resp, created, err := service.MarkAttendance(req, "", services.Actor{})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, uint(4), *resp.SectionID)
+
+	// Case 2: Not enrolled
+	mockSectionRepo.On("GetByID", uint(4)).Return(&models.Section{ID: 4}, nil).Once()
+	mockSectionRepo.On("IsEnrolled", uint(4), uint(1)).Return(false, nil).Once()
+	_, _, err = service.MarkAttendance(req, "", services.Actor{})
+	assert.ErrorIs(t, err, services.ErrStudentNotEnrolled)
+	assert.ErrorIs(t, err, services.ErrValidation)
+
+	// Case 3: Unknown section
+	mockSectionRepo.On("GetByID", uint(4)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, _, err = service.MarkAttendance(req, "", services.Actor{})
+	assert.ErrorIs(t, err, services.ErrUnknownSection)
+
+	// Case 4: Idempotency key first used for the whole day record -> rejected for a section
+	day := time.Date(2025, 12, 12, 0, 0, 0, 0, time.UTC)
+	mockAttRepo.On("GetByIdempotencyKey", "day-key").Return(&models.Attendance{StudentID: 1, Date: day, Status: "late"}, nil).Once()
+	_, _, err = service.MarkAttendance(req, "day-key", services.Actor{})
+	assert.ErrorIs(t, err, services.ErrIdempotencyKeyReused)
+	mockAttRepo.AssertNumberOfCalls(t, "FirstOrCreate", 1)
+}
+
+func TestMarkAttendanceCheckIn(t *testing.T) {
+	mockAttRepo := new(MockAttendanceRepo)
+	mockStudentRepo := new(MockStudentRepo)
+	mockSectionRepo := new(MockSectionRepo)
+	service := services.NewAttendanceService(mockAttRepo, mockStudentRepo, fakeCalendar{}, fakeTx{Students: mockStudentRepo, Attendance: mockAttRepo, Sections: mockSectionRepo})
+
+	day := time.Date(2025, 12, 12, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) *time.Time {
+		t := day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+		return &t
+	}
+	sessions := []models.ClassSession{
+		{SectionID: 4, Date: day, StartTime: "09:00", EndTime: "10:00", LateAfterMinutes: 5},
+		{SectionID: 4, Date: day, StartTime: "14:00", EndTime: "15:00"},
+	}
+	mockStudentRepo.On("GetByID", uint(1)).Return(&models.Student{Model: gorm.Model{ID: 1}}, nil)
+	mockSectionRepo.On("GetByID", uint(4)).Return(&models.Section{ID: 4}, nil)
+	mockSectionRepo.On("IsEnrolled", uint(4), uint(1)).Return(true, nil)
+	mockAttRepo.On("GetSessionsOn", uint(4), day).Return(sessions, nil)
+	var stored *models.Attendance
+	mockAttRepo.On("FirstOrCreate", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*models.Attendance)
+	}).Return(true, nil)
+	mark := func(status string, checkIn *time.Time) (*viewmodels.AttendanceResponse, error) {
+		resp, _, err := service.MarkAttendance(viewmodels.CreateAttendanceRequest{
+			StudentID: 1, Date: day, SectionID: 4, Status: status, CheckInAt: checkIn,
+		}, "", services.Actor{})
+		return resp, err
+	}
+
+	// Case 1: Within the grace period -> present
+	resp, err := mark("", at(9, 4))
+	assert.NoError(t, err)
+	assert.Equal(t, "present", resp.Status)
+	assert.Equal(t, 0, resp.LateMinutes)
+	assert.Equal(t, at(9, 4), stored.CheckInAt)
+
+	// Case 2: Past the grace period -> late, counted from the start
+	resp, err = mark("", at(9, 47))
+	assert.NoError(t, err)
+	assert.Equal(t, "late", resp.Status)
+	assert.Equal(t, 47, resp.LateMinutes)
+
+	// Case 3: After the morning class -> the afternoon one, which has no grace period
+	resp, err = mark("", at(14, 2))
+	assert.NoError(t, err)
+	assert.Equal(t, "late", resp.Status)
+	assert.Equal(t, 2, resp.LateMinutes)
+
+	// Case 4: An explicit status wins
+	resp, err = mark("excused", at(9, 47))
+	assert.NoError(t, err)
+	assert.Equal(t, "excused", resp.Status)
+	assert.Equal(t, 0, resp.LateMinutes)
+
+	// Case 5: Nothing to derive the status from
+	_, err = mark("", nil)
+	assert.ErrorIs(t, err, services.ErrStatusRequired)
+
+	// Case 6: Check-in on another day
+	_, err = mark("", at(33, 0))
+	assert.ErrorIs(t, err, services.ErrValidation)
+}
+
+func TestCheckOut(t *testing.T) {
+	mockAttRepo := new(MockAttendanceRepo)
+	service := services.NewAttendanceService(mockAttRepo, new(MockStudentRepo), fakeCalendar{}, fakeTx{Attendance: mockAttRepo})
+
+	day := time.Date(2025, 12, 12, 0, 0, 0, 0, time.UTC)
+	checkIn := day.Add(9 * time.Hour)
+	record := func() *models.Attendance {
+		return &models.Attendance{Model: gorm.Model{ID: 7}, StudentID: 1, Date: day, Status: "present", CheckInAt: &checkIn}
+	}
+
+	// Case 1: Success
+	mockAttRepo.On("GetByID", uint(7)).Return(record(), nil).Once()
+	mockAttRepo.On("SetCheckOut", mock.MatchedBy(func(a *models.Attendance) bool {
+		return a.CheckOutAt.Equal(day.Add(15 * time.Hour))
+	}), mock.Anything).Return(nil).Once()
+	resp, err := service.CheckOut(7, viewmodels.CheckOutRequest{CheckOutAt: day.Add(15 * time.Hour)}, services.Actor{})
+	assert.NoError(t, err)
+	assert.NotNil(t, resp.CheckOutAt)
+
+	// Case 2: Before the check-in
+	mockAttRepo.On("GetByID", uint(7)).Return(record(), nil).Once()
+	_, err = service.CheckOut(7, viewmodels.CheckOutRequest{CheckOutAt: day.Add(8 * time.Hour)}, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrValidation)
+
+	// Case 3: Not found
+	mockAttRepo.On("GetByID", uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.CheckOut(9, viewmodels.CheckOutRequest{CheckOutAt: day}, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrAttendanceNotFound)
+	mockAttRepo.AssertNumberOfCalls(t, "SetCheckOut", 1)
+}
+
+func TestMarkAttendanceReplay(t *testing.T) {
+	mockAttRepo := new(MockAttendanceRepo)
+	mockStudentRepo := new(MockStudentRepo)
+	service := services.NewAttendanceService(mockAttRepo, mockStudentRepo, fakeCalendar{}, fakeTx{Students: mockStudentRepo, Attendance: mockAttRepo})
+
+	req := viewmodels.CreateAttendanceRequest{StudentID: 1, Date: time.Now(), Status: "present"}
+	storedAs := func(status string) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			a := args.Get(0).(*models.Attendance)
+			*a = models.Attendance{Model: gorm.Model{ID: 7}, StudentID: a.StudentID, Date: a.Date, Status: status}
+		}
+	}
+
+	// Case 1: Same status already stored -> existing record, not created
+	mockStudentRepo.On("GetByID", uint(1)).Return(&models.Student{}, nil)
+	mockAttRepo.On("FirstOrCreate", mock.Anything, mock.Anything).Run(storedAs("present")).Return(false, nil).Once()
+	resp, created, err := service.MarkAttendance(req, "", services.Actor{})
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, uint(7), resp.ID)
+
+	// Case 2: Different status already stored -> conflict
+	mockAttRepo.On("FirstOrCreate", mock.Anything, mock.Anything).Run(storedAs("absent")).Return(false, nil).Once()
+	resp, _, err = service.MarkAttendance(req, "", services.Actor{})
+	assert.ErrorIs(t, err, services.ErrAttendanceConflict)
+	assert.Nil(t, resp)
+}
+
+func TestMarkAttendanceIdempotencyKey(t *testing.T) {
+	mockAttRepo := new(MockAttendanceRepo)
+	mockStudentRepo := new(MockStudentRepo)
+	service := services.NewAttendanceService(mockAttRepo, mockStudentRepo, fakeCalendar{}, fakeTx{Students: mockStudentRepo, Attendance: mockAttRepo})
+
+	day := time.Date(2025, 12, 12, 0, 0, 0, 0, time.UTC)
+	req := viewmodels.CreateAttendanceRequest{StudentID: 1, Date: day.Add(9 * time.Hour), Status: "present"}
+	stored := &models.Attendance{Model: gorm.Model{ID: 3}, StudentID: 1, Date: day, Status: "present"}
+
+	// Case 1: New key -> stored on the created row
+	mockAttRepo.On("GetByIdempotencyKey", "new-key").Return(nil, gorm.ErrRecordNotFound).Once()
+	mockStudentRepo.On("GetByID", uint(1)).Return(&models.Student{}, nil).Once()
+	mockAttRepo.On("FirstOrCreate", mock.MatchedBy(func(a *models.Attendance) bool {
+		return a.IdempotencyKey != nil && *a.IdempotencyKey == "new-key"
+	}), mock.Anything).Return(true, nil).Once()
+	_, created, err := service.MarkAttendance(req, "new-key", services.Actor{})
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	// Case 2: Retry with the same key and payload -> original record
+	mockAttRepo.On("GetByIdempotencyKey", "seen-key").Return(stored, nil).Once()
+	resp, created, err := service.MarkAttendance(req, "seen-key", services.Actor{})
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, uint(3), resp.ID)
+
+	// Case 3: Same key with a different payload -> rejected
+	mockAttRepo.On("GetByIdempotencyKey", "seen-key").Return(stored, nil).Once()
+	_, _, err = service.MarkAttendance(viewmodels.CreateAttendanceRequest{StudentID: 1, Date: day, Status: "absent"}, "seen-key", services.Actor{})
+	assert.ErrorIs(t, err, services.ErrIdempotencyKeyReused)
+
+	// Case 4: A concurrent request took the key for another student after the lookup -> rejected, not a 500
+	mockAttRepo.On("GetByIdempotencyKey", "raced-key").Return(nil, gorm.ErrRecordNotFound).Once()
+	mockStudentRepo.On("GetByID", uint(1)).Return(&models.Student{}, nil).Once()
+	mockAttRepo.On("FirstOrCreate", mock.Anything, mock.Anything).Return(false, gorm.ErrDuplicatedKey).Once()
+	_, _, err = service.MarkAttendance(req, "raced-key", services.Actor{})
+	assert.ErrorIs(t, err, services.ErrIdempotencyKeyReused)
+	assert.ErrorIs(t, err, services.ErrConflict)
+}
+
+func TestMarkBulkAttendance(t *testing.T) {
+	mockAttRepo := new(MockAttendanceRepo)
+	mockStudentRepo := new(MockStudentRepo)
+	service := services.NewAttendanceService(mockAttRepo, mockStudentRepo, fakeCalendar{}, fakeTx{Students: mockStudentRepo, Attendance: mockAttRepo})
+
+	day := time.Date(2025, 12, 12, 0, 0, 0, 0, time.UTC)
+
+	// Case 1: Explicit records with an unknown student, a replay, a conflict and a new row
+	req := viewmodels.BulkAttendanceRequest{
+		Date: day.Add(8 * time.Hour),
+		Records: []viewmodels.BulkAttendanceEntry{
+			{StudentID: 1, Status: "present"},
+			{StudentID: 2, Status: "absent"},
+			{StudentID: 3, Status: "late"},
+			{StudentID: 99, Status: "present"},
+		},
+	}
+	mockStudentRepo.On("GetByIDs", []uint{1, 2, 3, 99}).Return([]models.Student{
+		{Model: gorm.Model{ID: 1}}, {Model: gorm.Model{ID: 2}}, {Model: gorm.Model{ID: 3}},
+	}, nil).Once()
+	mockAttRepo.On("GetByStudentsAndDate", []uint{1, 2, 3}, day).Return([]models.Attendance{
+		{Model: gorm.Model{ID: 10}, StudentID: 2, Date: day, Status: "absent"},
+		{Model: gorm.Model{ID: 11}, StudentID: 3, Date: day, Status: "present"},
+	}, nil).Once()
+	mockAttRepo.On("CreateBatch", mock.MatchedBy(func(recs []models.Attendance) bool {
+		return len(recs) == 1 && recs[0].StudentID == 1
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).([]models.Attendance)[0].ID = 12
+	}).Return(nil).Once()
+
+	resp, err := service.MarkBulkAttendance(req, services.Actor{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resp.Succeeded)
+	assert.Equal(t, 2, resp.Failed)
+	assert.True(t, resp.Results[0].Created)
+	assert.Equal(t, uint(12), resp.Results[0].AttendanceID)
+	assert.True(t, resp.Results[1].Success)
+	assert.False(t, resp.Results[1].Created)
+	assert.Equal(t, services.ErrAttendanceConflict.Error(), resp.Results[2].Error)
+	assert.Equal(t, "student not found", resp.Results[3].Error)
+
+	// Case 2: Whole department present except one absentee
+	req = viewmodels.BulkAttendanceRequest{
+		Date:         day,
+		DepartmentID: 2,
+		Exceptions:   []viewmodels.BulkAttendanceEntry{{StudentID: 5, Status: "absent"}, {StudentID: 7, Status: "late"}},
+	}
+	mockStudentRepo.On("GetByDepartment", uint(2)).Return([]models.Student{
+		{Model: gorm.Model{ID: 4}}, {Model: gorm.Model{ID: 5}},
+	}, nil).Once()
+	mockAttRepo.On("GetByStudentsAndDate", []uint{4, 5}, day).Return([]models.Attendance{}, nil).Once()
+	mockAttRepo.On("CreateBatch", mock.MatchedBy(func(recs []models.Attendance) bool {
+		return len(recs) == 2 && recs[0].Status == "present" && recs[1].Status == "absent"
+	}), mock.Anything).Return(nil).Once()
+
+	resp, err = service.MarkBulkAttendance(req, services.Actor{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resp.Succeeded)
+	assert.Equal(t, 1, resp.Failed)
+	assert.Equal(t, "student not in department", resp.Results[2].Error)
+
+	// Case 3: Nothing to mark
+	_, err = service.MarkBulkAttendance(viewmodels.BulkAttendanceRequest{Date: day}, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrEmptyBulkRequest)
+
+	// Case 4: Transaction fails
+	req = viewmodels.BulkAttendanceRequest{Date: day, Records: []viewmodels.BulkAttendanceEntry{{StudentID: 1, Status: "present"}}}
+	mockStudentRepo.On("GetByIDs", []uint{1}).Return([]models.Student{{Model: gorm.Model{ID: 1}}}, nil).Once()
+	mockAttRepo.On("GetByStudentsAndDate", []uint{1}, day).Return([]models.Attendance{}, nil).Once()
+	mockAttRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
+	resp, err = service.MarkBulkAttendance(req, services.Actor{})
 	assert.Error(t, err)
-	assert.Equal(t, "student not found: cannot mark attendance", err.Error())
+	assert.Nil(t, resp)
 }
 
-func TestGetAttendanceByStudentID(t *testing.T) {
+func TestUpdateAttendance(t *testing.T) {
+	mockAttRepo := new(MockAttendanceRepo)
+	mockStudentRepo := new(MockStudentRepo)
+	service := services.NewAttendanceService(mockAttRepo, mockStudentRepo, fakeCalendar{}, fakeTx{Students: mockStudentRepo, Attendance: mockAttRepo})
+
+	req := viewmodels.UpdateAttendanceRequest{Status: "present", Reason: "was in the lab"}
+
+	// Case 1: Success, the correction keeps the previous status
+	mockAttRepo.On("GetByID", uint(1)).Return(&models.Attendance{Model: gorm.Model{ID: 1}, StudentID: 4, Status: "absent"}, nil).Once()
+	mockAttRepo.On("UpdateStatus",
+		mock.MatchedBy(func(a *models.Attendance) bool { return a.Status == "present" }),
+		mock.MatchedBy(func(c *models.AttendanceCorrection) bool {
+			return c.AttendanceID == 1 && c.StudentID == 4 && c.Action == "update" &&
+				c.PreviousStatus == "absent" && c.NewStatus == "present" && c.Reason == "was in the lab"
+		}),
+		mock.Anything,
+	).Return(nil).Once()
+
+	resp, err := service.UpdateAttendance(1, req, services.Actor{})
+	assert.NoError(t, err)
+	assert.Equal(t, "present", resp.Status)
+
+	// Case 2: Same status, nothing is written
+	mockAttRepo.On("GetByID", uint(2)).Return(&models.Attendance{Model: gorm.Model{ID: 2}, Status: "present"}, nil).Once()
+	resp, err = service.UpdateAttendance(2, req, services.Actor{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), resp.ID)
+
+	// Case 3: Not Found
+	mockAttRepo.On("GetByID", uint(99)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.UpdateAttendance(99, req, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrAttendanceNotFound)
+
+	mockAttRepo.AssertExpectations(t)
+}
+
+func TestDeleteAttendance(t *testing.T) {
 	mockAttRepo := new(MockAttendanceRepo)
 	mockStudentRepo := new(MockStudentRepo)
-	service := services.NewAttendanceService(mockAttRepo, mockStudentRepo)
+	service := services.NewAttendanceService(mockAttRepo, mockStudentRepo, fakeCalendar{}, fakeTx{Students: mockStudentRepo, Attendance: mockAttRepo})
+
+	req := viewmodels.DeleteAttendanceRequest{Reason: "wrong student"}
 
 	// Case 1: Success
+	existing := &models.Attendance{Model: gorm.Model{ID: 1}, StudentID: 4, Status: "late"}
+	mockAttRepo.On("GetByID", uint(1)).Return(existing, nil).Once()
+	mockAttRepo.On("Delete", existing, mock.MatchedBy(func(c *models.AttendanceCorrection) bool {
+		return c.Action == "delete" && c.PreviousStatus == "late" && c.NewStatus == "" && c.Reason == "wrong student"
+	}), mock.Anything).Return(nil).Once()
+	assert.NoError(t, service.DeleteAttendance(1, req, services.Actor{}))
+
+	// Case 2: Not Found
+	mockAttRepo.On("GetByID", uint(99)).Return(nil, gorm.ErrRecordNotFound).Once()
+	assert.ErrorIs(t, service.DeleteAttendance(99, req, services.Actor{}), services.ErrAttendanceNotFound)
+}
+
+func TestGetAttendanceHistory(t *testing.T) {
+	mockAttRepo := new(MockAttendanceRepo)
+	mockStudentRepo := new(MockStudentRepo)
+	service := services.NewAttendanceService(mockAttRepo, mockStudentRepo, fakeCalendar{}, fakeTx{Students: mockStudentRepo, Attendance: mockAttRepo})
+
+	// Case 1: History of a deleted record is still available
+	mockAttRepo.On("GetCorrections", uint(1)).Return([]models.AttendanceCorrection{
+		{AttendanceID: 1, Action: "update", PreviousStatus: "absent", NewStatus: "late"},
+		{AttendanceID: 1, Action: "delete", PreviousStatus: "late"},
+	}, nil).Once()
+	resp, err := service.GetAttendanceHistory(1)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 2)
+	assert.Equal(t, "delete", resp[1].Action)
+
+	// Case 2: No history and no record
+	mockAttRepo.On("GetCorrections", uint(99)).Return([]models.AttendanceCorrection{}, nil).Once()
+	mockAttRepo.On("GetByID", uint(99)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.GetAttendanceHistory(99)
+	assert.ErrorIs(t, err, services.ErrAttendanceNotFound)
+}
+
+func TestListAttendance(t *testing.T) {
+	mockAttRepo := new(MockAttendanceRepo)
+	mockStudentRepo := new(MockStudentRepo)
+	service := services.NewAttendanceService(mockAttRepo, mockStudentRepo, fakeCalendar{}, fakeTx{Students: mockStudentRepo, Attendance: mockAttRepo})
+
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	// Case 1: Filters are passed through and page 3 of 5 maps to offset 10
+	mockData := []models.Attendance{
+		{Model: gorm.Model{ID: 1}, StudentID: 1, Status: "late", Student: models.Student{Name: "Alice"}},
+	}
+	mockAttRepo.On("List", repository.AttendanceFilter{
+		DepartmentID: 2, Status: "late", From: from, Sort: "-date", Limit: 6, Offset: 10,
+	}).Return(mockData, int64(11), nil).Once()
+
+	resp, err := service.ListAttendance(viewmodels.AttendanceListQuery{
+		DepartmentID: 2, Status: "late", From: from, Sort: "-date", Page: 3, Limit: 5,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), resp.Total)
+	assert.Equal(t, "Alice", resp.Data[0].StudentName)
+	assert.Equal(t, 3, resp.Page)
+	assert.Empty(t, resp.NextCursor)
+
+	// Case 2: A full page gets a cursor, which is passed on and replaces the offset
+	page := []models.Attendance{
+		{Model: gorm.Model{ID: 4}, Date: from, Status: "present"},
+		{Model: gorm.Model{ID: 3}, Date: from, Status: "present"},
+		{Model: gorm.Model{ID: 2}, Date: from, Status: "absent"},
+	}
+	mockAttRepo.On("List", repository.AttendanceFilter{Limit: 3}).Return(page, int64(3), nil).Once()
+	resp, err = service.ListAttendance(viewmodels.AttendanceListQuery{Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Data, 2)
+	assert.NotEmpty(t, resp.NextCursor)
+
+	mockAttRepo.On("List", repository.AttendanceFilter{Limit: 3, After: resp.NextCursor}).Return(page[2:], int64(3), nil).Once()
+	resp, err = service.ListAttendance(viewmodels.AttendanceListQuery{Limit: 2, Cursor: resp.NextCursor})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Data, 1)
+	assert.Zero(t, resp.Page)
+	assert.Empty(t, resp.NextCursor)
+
+	// Case 3: Bad cursor
+	mockAttRepo.On("List", repository.AttendanceFilter{Limit: 21, After: "bogus"}).Return(nil, int64(0), repository.ErrInvalidCursor).Once()
+	_, err = service.ListAttendance(viewmodels.AttendanceListQuery{Cursor: "bogus"})
+	assert.ErrorIs(t, err, services.ErrInvalidCursor)
+	assert.ErrorIs(t, err, services.ErrValidation)
+
+	// Case 4: Defaults
+	mockAttRepo.On("List", repository.AttendanceFilter{Limit: 21}).Return(nil, int64(0), errors.New("db error")).Once()
+	resp, err = service.ListAttendance(viewmodels.AttendanceListQuery{})
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+}
+
+func TestGetAttendanceByStudentID(t *testing.T) {
+	mockAttRepo := new(MockAttendanceRepo)
+	mockStudentRepo := new(MockStudentRepo)
+	service := services.NewAttendanceService(mockAttRepo, mockStudentRepo, fakeCalendar{}, fakeTx{Students: mockStudentRepo, Attendance: mockAttRepo})
+
+	// Case 1: Success, paged like ListAttendance and limited to the student whatever the query says
 	mockStudentRepo.On("GetByID", uint(1)).Return(&models.Student{}, nil).Once()
 	mockData := []models.Attendance{
-		{Model: gorm.Model{ID: 1}, StudentID: 1, Status: "present"},
+		{Model: gorm.Model{ID: 1}, StudentID: 1, Date: time.Date(2025, 12, 2, 0, 0, 0, 0, time.UTC), Status: "present"},
+		{Model: gorm.Model{ID: 2}, StudentID: 1, Date: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), Status: "absent"},
 	}
-	mockAttRepo.On("GetAttendanceByStudentID", uint(1)).Return(mockData, nil).Once()
+	mockAttRepo.On("List", repository.AttendanceFilter{StudentID: 1, Status: "present", Limit: 2}).Return(mockData, int64(2), nil).Once()
 
-	resp, err := service.GetAttendanceByStudentID(1)
+	resp, err := service.GetAttendanceByStudentID(1, viewmodels.AttendanceListQuery{StudentID: 5, Status: "present", Limit: 1})
 	assert.NoError(t, err)
-	assert.Len(t, resp, 1)
+	assert.Len(t, resp.Data, 1)
+	assert.Equal(t, int64(2), resp.Total)
+	assert.NotEmpty(t, resp.NextCursor)
 
 	// Case 2: Student Not Found
 	mockStudentRepo.On("GetByID", uint(99)).Return(nil, errors.New("not found")).Once()
-	resp, err = service.GetAttendanceByStudentID(99)
+	resp, err = service.GetAttendanceByStudentID(99, viewmodels.AttendanceListQuery{})
 	assert.Error(t, err)
 	assert.Nil(t, resp)
 }
 
-func TestGetWeeklyAttendance(t *testing.T) {
+func TestGetAttendanceSummaryByStudentID(t *testing.T) {
 	mockAttRepo := new(MockAttendanceRepo)
 	mockStudentRepo := new(MockStudentRepo)
-	service := services.NewAttendanceService(mockAttRepo, mockStudentRepo)
+	service := services.NewAttendanceService(mockAttRepo, mockStudentRepo, fakeCalendar{}, fakeTx{Students: mockStudentRepo, Attendance: mockAttRepo})
 
-	// Case 1: Success
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	student := &models.Student{Model: gorm.Model{ID: 1}, Name: "Alice", Department: models.Department{Name: "CS"}}
+
+	// Case 1: Success, unmarked class sessions count as absences, other students are left out
+	mockStudentRepo.On("GetByID", uint(1)).Return(student, nil)
 	mockData := []models.Attendance{
-		{Model: gorm.Model{ID: 1}, StudentID: 1, Status: "present"},
+		{StudentID: 1, Student: *student, Date: from, Status: "present"},
+		{StudentID: 1, Student: *student, Date: from.AddDate(0, 0, 1), Status: "late"},
+		{StudentID: 2, Date: from.AddDate(0, 0, 1), Status: "absent"},
+		{StudentID: 1, Student: *student, Date: from.AddDate(0, 0, 3), Status: "present"},
 	}
-	// We use mock.Anything for the date argument since exact time matching is flaky
-	mockAttRepo.On("GetAttendanceSince", mock.Anything).Return(mockData, nil).Once()
+	mockAttRepo.On("GetAttendanceBetween", from, to).Return(mockData, nil).Once()
+	mockAttRepo.On("GetUnmarkedSessions", from, to).Return([]models.Attendance{
+		{StudentID: 1, Student: *student, Date: from.AddDate(0, 0, 2), Status: "absent"},
+		{StudentID: 2, Date: from.AddDate(0, 0, 2), Status: "absent"},
+	}, nil).Once()
 
-	resp, err := service.GetWeeklyAttendance()
+	resp, err := service.GetAttendanceSummaryByStudentID(1, from, to)
 	assert.NoError(t, err)
-	assert.Len(t, resp, 1)
+	assert.Equal(t, 2, resp.Present)
+	assert.Equal(t, 1, resp.Late)
+	assert.Equal(t, 1, resp.Absent)
+	assert.Equal(t, 4, resp.Total)
+	assert.Equal(t, 75.0, resp.Percentage)
+
+	// Case 2: No records yet
+	mockAttRepo.On("GetAttendanceBetween", from, to).Return([]models.Attendance{}, nil).Once()
+	mockAttRepo.On("GetUnmarkedSessions", from, to).Return([]models.Attendance{}, nil).Once()
+	resp, err = service.GetAttendanceSummaryByStudentID(1, from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice", resp.StudentName)
+	assert.Equal(t, 0, resp.Total)
+
+	// Case 3: Student Not Found
+	mockStudentRepo.On("GetByID", uint(99)).Return(nil, errors.New("not found")).Once()
+	resp, err = service.GetAttendanceSummaryByStudentID(99, from, to)
+	assert.Error(t, err)
+	assert.Nil(t, resp)
 }
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"encoding/json"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
+	"hrms_backend/internal/viewmodels"
+)
+
+// Actor identifies who makes a change and where the request came from, for the audit log.
+// The zero Actor stands for the system (e.g. scheduled jobs).
+type Actor struct {
+	UserID    uint
+	RequestID string
+	IP        string
+}
+
+// auditEntry starts the audit log entry for a change made by the actor;
+// the repository fills in the entity ID and the before/after state
+func (a Actor) auditEntry(action, entityType string) *models.AuditLog {
+	entry := &models.AuditLog{
+		Action:     action,
+		EntityType: entityType,
+		RequestID:  a.RequestID,
+		IP:         a.IP,
+	}
+	if a.UserID != 0 {
+		userID := a.UserID
+		entry.ActorID = &userID
+	}
+	return entry
+}
+
+type AuditService interface {
+	ListAuditLogs(query viewmodels.AuditLogQuery) (*viewmodels.AuditLogListResponse, error)
+}
+
+type auditService struct {
+	repo repository.AuditRepository
+}
+
+// Constructor
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+// ListAuditLogs returns a filtered page of audit entries, newest first
+func (s *auditService) ListAuditLogs(query viewmodels.AuditLogQuery) (*viewmodels.AuditLogListResponse, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 {
+		query.Limit = 20
+	}
+
+	entries, total, err := s.repo.List(repository.AuditFilter{
+		EntityType: query.EntityType,
+		EntityID:   query.EntityID,
+		ActorID:    query.ActorID,
+		Limit:      query.Limit,
+		Offset:     (query.Page - 1) * query.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]viewmodels.AuditLogResponse, 0, len(entries))
+	for _, e := range entries {
+		data = append(data, viewmodels.AuditLogResponse{
+			ID:         e.ID,
+			ActorID:    e.ActorID,
+			Action:     e.Action,
+			EntityType: e.EntityType,
+			EntityID:   e.EntityID,
+			Before:     rawJSON(e.Before),
+			After:      rawJSON(e.After),
+			RequestID:  e.RequestID,
+			IP:         e.IP,
+			CreatedAt:  e.CreatedAt,
+		})
+	}
+	return &viewmodels.AuditLogListResponse{Data: data, Total: total, Page: query.Page, Limit: query.Limit}, nil
+}
+
+func rawJSON(s *string) json.RawMessage {
+	if s == nil {
+		return nil
+	}
+	return json.RawMessage(*s)
+}
@@ -0,0 +1,75 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- Mock Repository ---
+type MockAuditRepo struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepo) List(filter repository.AuditFilter) ([]models.AuditLog, int64, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]models.AuditLog), args.Get(1).(int64), args.Error(2)
+}
+
+// --- Tests ---
+
+func TestListAuditLogs(t *testing.T) {
+	mockRepo := new(MockAuditRepo)
+	service := services.NewAuditService(mockRepo)
+
+	// Case 1: Success, defaults applied and JSON passed through
+	before, after := `{"status":"absent"}`, `{"status":"present"}`
+	actor := uint(3)
+	mockRepo.On("List", repository.AuditFilter{EntityType: "attendance", EntityID: 5, Limit: 20}).Return([]models.AuditLog{
+		{ID: 1, ActorID: &actor, Action: "update", EntityType: "attendance", EntityID: 5, Before: &before, After: &after, RequestID: "req-1"},
+	}, int64(1), nil).Once()
+
+	resp, err := service.ListAuditLogs(viewmodels.AuditLogQuery{EntityType: "attendance", EntityID: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), resp.Total)
+	assert.Equal(t, 1, resp.Page)
+	assert.JSONEq(t, before, string(resp.Data[0].Before))
+	assert.JSONEq(t, after, string(resp.Data[0].After))
+	assert.Equal(t, uint(3), *resp.Data[0].ActorID)
+
+	// Case 2: Repo Error
+	mockRepo.On("List", mock.Anything).Return(nil, int64(0), errors.New("db error")).Once()
+	resp, err = service.ListAuditLogs(viewmodels.AuditLogQuery{Page: 2})
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+}
+
+func TestMutationsCarryAuditEntry(t *testing.T) {
+	mockRepo := new(MockStudentRepo)
+	service := services.NewStudentService(mockRepo, new(MockDepartmentRepo), fakeTx{Students: mockRepo})
+	actor := services.Actor{UserID: 3, RequestID: "req-1", IP: "10.0.0.1"}
+
+	mockRepo.On("GetByID", uint(1)).Return(&models.Student{}, nil).Once()
+	mockRepo.On("Delete", uint(1), mock.MatchedBy(func(a *models.AuditLog) bool {
+		return a.ActorID != nil && *a.ActorID == 3 && a.Action == models.AuditActionDelete &&
+			a.EntityType == models.AuditEntityStudent && a.RequestID == "req-1" && a.IP == "10.0.0.1"
+	})).Return(nil).Once()
+	assert.NoError(t, service.DeleteStudent(1, actor))
+
+	// system changes have no actor
+	mockRepo.On("GetByID", uint(2)).Return(&models.Student{}, nil).Once()
+	mockRepo.On("Delete", uint(2), mock.MatchedBy(func(a *models.AuditLog) bool { return a.ActorID == nil })).Return(nil).Once()
+	assert.NoError(t, service.DeleteStudent(2, services.Actor{}))
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
+	"hrms_backend/internal/viewmodels"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 7 * 24 * time.Hour
+
+	// MinPasswordLength is the shortest password an account can have, as enforced on POST /users
+	MinPasswordLength = 8
+	// MinSecretLength is the shortest token signing key accepted, in bytes (256 bits for HS256)
+	MinSecretLength = 32
+	// placeholder JWT_SECRET that used to ship in .env
+	placeholderSecret = "change-me-in-production"
+)
+
+// ValidateSecret rejects a token signing key that is missing, too short or a known placeholder
+func ValidateSecret(secret string) error {
+	switch {
+	case secret == "":
+		return errors.New("must be set")
+	case secret == placeholderSecret:
+		return errors.New("is the placeholder value, set a long random one")
+	case len(secret) < MinSecretLength:
+		return fmt.Errorf("must be at least %d bytes", MinSecretLength)
+	}
+	return nil
+}
+
+var (
+	// ErrInvalidCredentials is returned for an unknown email or a wrong password (deliberately not distinguished).
+	ErrInvalidCredentials = &Error{Kind: ErrUnauthorized, Code: "invalid_credentials", Message: "invalid email or password"}
+	// ErrInvalidToken is returned for a token that is malformed, expired, badly signed or of the wrong type.
+	ErrInvalidToken = &Error{Kind: ErrUnauthorized, Code: "invalid_token", Message: "invalid or expired token"}
+	// ErrUserExists is returned when creating a user with an email already in use.
+	ErrUserExists = &Error{Kind: ErrConflict, Code: "user_exists", Message: "a user with this email already exists"}
+	// ErrStudentRequired is returned when a student account does not point to an existing student.
+	ErrStudentRequired = &Error{
+		Kind:    ErrValidation,
+		Code:    "student_required",
+		Message: "student accounts must reference an existing student",
+		Fields:  map[string]string{"student_id": "must reference an existing student"},
+	}
+)
+
+// Principal is the authenticated caller, as carried by an access token.
+type Principal struct {
+	UserID    uint
+	Role      string
+	StudentID *uint // set for student accounts
+}
+
+type AuthService interface {
+	Login(req viewmodels.LoginRequest) (*viewmodels.TokenResponse, error)
+	Refresh(req viewmodels.RefreshRequest) (*viewmodels.TokenResponse, error)
+	ParseAccessToken(token string) (*Principal, error)
+	CreateUser(req viewmodels.CreateUserRequest) (*viewmodels.UserResponse, error)
+	EnsureAdmin(email, password string) error
+}
+
+type authService struct {
+	userRepo    repository.UserRepository
+	studentRepo repository.StudentRepository
+	secret      []byte
+}
+
+// token claims; Type keeps refresh tokens from being used as access tokens and vice versa
+type tokenClaims struct {
+	Role      string `json:"role"`
+	StudentID *uint  `json:"sid,omitempty"`
+	Type      string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// Constructor: secret signs the tokens (HS256)
+func NewAuthService(userRepo repository.UserRepository, studentRepo repository.StudentRepository, secret []byte) AuthService {
+	return &authService{userRepo: userRepo, studentRepo: studentRepo, secret: secret}
+}
+
+// Login checks the credentials and issues an access/refresh token pair
+func (s *authService) Login(req viewmodels.LoginRequest) (*viewmodels.TokenResponse, error) {
+	user, err := s.userRepo.GetByEmail(req.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return s.issueTokens(user)
+}
+
+// Refresh trades a valid refresh token for a new pair.
+// The user is reloaded so role changes and deleted accounts take effect.
+func (s *authService) Refresh(req viewmodels.RefreshRequest) (*viewmodels.TokenResponse, error) {
+	claims, err := s.parse(req.RefreshToken, "refresh")
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	return s.issueTokens(user)
+}
+
+// ParseAccessToken validates an access token and returns the caller it was issued to
+func (s *authService) ParseAccessToken(token string) (*Principal, error) {
+	claims, err := s.parse(token, "access")
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	return &Principal{UserID: uint(id), Role: claims.Role, StudentID: claims.StudentID}, nil
+}
+
+// CreateUser registers an account with a bcrypt hashed password
+func (s *authService) CreateUser(req viewmodels.CreateUserRequest) (*viewmodels.UserResponse, error) {
+	if _, err := s.userRepo.GetByEmail(req.Email); err == nil {
+		return nil, ErrUserExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	user := models.User{Email: req.Email, Role: req.Role}
+	if req.Role == models.RoleStudent {
+		if req.StudentID == nil {
+			return nil, ErrStudentRequired
+		}
+		if _, err := s.studentRepo.GetByID(*req.StudentID); err != nil {
+			return nil, ErrStudentRequired
+		}
+		user.StudentID = req.StudentID
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	user.PasswordHash = string(hash)
+
+	if err := s.userRepo.Create(&user); err != nil {
+		// lost a race with another request for the same email
+		return nil, translateDBError(err, nil, ErrUserExists)
+	}
+	return &viewmodels.UserResponse{
+		ID:        user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		StudentID: user.StudentID,
+		CreatedAt: user.CreatedAt,
+	}, nil
+}
+
+// EnsureAdmin creates the bootstrap admin account unless a user with that email already exists.
+// The password must be as long as any account's, even if the account exists.
+func (s *authService) EnsureAdmin(email, password string) error {
+	if len(password) < MinPasswordLength {
+		return fmt.Errorf("admin password must be at least %d characters", MinPasswordLength)
+	}
+	_, err := s.CreateUser(viewmodels.CreateUserRequest{Email: email, Password: password, Role: models.RoleAdmin})
+	if errors.Is(err, ErrUserExists) {
+		return nil
+	}
+	return err
+}
+
+func (s *authService) issueTokens(user *models.User) (*viewmodels.TokenResponse, error) {
+	access, err := s.sign(user, "access", accessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := s.sign(user, "refresh", refreshTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &viewmodels.TokenResponse{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(accessTokenTTL.Seconds()),
+	}, nil
+}
+
+func (s *authService) sign(user *models.User, tokenType string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := tokenClaims{
+		Role:      user.Role,
+		StudentID: user.StudentID,
+		Type:      tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+}
+
+func (s *authService) parse(token, tokenType string) (*tokenClaims, error) {
+	var claims tokenClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, ErrInvalidToken.Wrap(err)
+	}
+	if claims.Type != tokenType {
+		return nil, ErrInvalidToken
+	}
+	return &claims, nil
+}
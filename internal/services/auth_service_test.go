@@ -0,0 +1,176 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// --- Mock User Repo ---
+type MockUserRepo struct {
+	mock.Mock
+}
+
+func (m *MockUserRepo) Create(user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) GetByID(id uint) (*models.User, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepo) GetByEmail(email string) (*models.User, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+// --- Helpers ---
+
+func hashPassword(t *testing.T, password string) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	assert.NoError(t, err)
+	return string(hash)
+}
+
+// --- Tests ---
+
+func TestLoginAndParseAccessToken(t *testing.T) {
+	mockUserRepo := new(MockUserRepo)
+	service := services.NewAuthService(mockUserRepo, new(MockStudentRepo), []byte("secret"))
+
+	studentID := uint(7)
+	user := &models.User{Model: gorm.Model{ID: 3}, Email: "s@test.com", Role: models.RoleStudent, StudentID: &studentID,
+		PasswordHash: hashPassword(t, "password1")}
+
+	// Case 1: Success, the access token carries the role and student
+	mockUserRepo.On("GetByEmail", "s@test.com").Return(user, nil)
+	tokens, err := service.Login(viewmodels.LoginRequest{Email: "s@test.com", Password: "password1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer", tokens.TokenType)
+
+	principal, err := service.ParseAccessToken(tokens.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), principal.UserID)
+	assert.Equal(t, models.RoleStudent, principal.Role)
+	assert.Equal(t, uint(7), *principal.StudentID)
+
+	// Case 2: A refresh token is not an access token
+	_, err = service.ParseAccessToken(tokens.RefreshToken)
+	assert.ErrorIs(t, err, services.ErrInvalidToken)
+
+	// Case 3: Signed with another secret
+	other := services.NewAuthService(mockUserRepo, new(MockStudentRepo), []byte("other"))
+	_, err = other.ParseAccessToken(tokens.AccessToken)
+	assert.ErrorIs(t, err, services.ErrInvalidToken)
+
+	// Case 4: Wrong password and unknown email look the same
+	_, err = service.Login(viewmodels.LoginRequest{Email: "s@test.com", Password: "wrong"})
+	assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+	mockUserRepo.On("GetByEmail", "nobody@test.com").Return(nil, gorm.ErrRecordNotFound)
+	_, err = service.Login(viewmodels.LoginRequest{Email: "nobody@test.com", Password: "password1"})
+	assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+}
+
+func TestRefresh(t *testing.T) {
+	mockUserRepo := new(MockUserRepo)
+	service := services.NewAuthService(mockUserRepo, new(MockStudentRepo), []byte("secret"))
+
+	user := &models.User{Model: gorm.Model{ID: 1}, Email: "t@test.com", Role: models.RoleTeacher, PasswordHash: hashPassword(t, "password1")}
+	mockUserRepo.On("GetByEmail", "t@test.com").Return(user, nil)
+	tokens, err := service.Login(viewmodels.LoginRequest{Email: "t@test.com", Password: "password1"})
+	assert.NoError(t, err)
+
+	// Case 1: Success, the role is reloaded from the user
+	mockUserRepo.On("GetByID", uint(1)).Return(&models.User{Model: gorm.Model{ID: 1}, Role: models.RoleAdmin}, nil).Once()
+	refreshed, err := service.Refresh(viewmodels.RefreshRequest{RefreshToken: tokens.RefreshToken})
+	assert.NoError(t, err)
+	principal, err := service.ParseAccessToken(refreshed.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, principal.Role)
+
+	// Case 2: An access token cannot be used to refresh
+	_, err = service.Refresh(viewmodels.RefreshRequest{RefreshToken: tokens.AccessToken})
+	assert.ErrorIs(t, err, services.ErrInvalidToken)
+
+	// Case 3: The user was deleted
+	mockUserRepo.On("GetByID", uint(1)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.Refresh(viewmodels.RefreshRequest{RefreshToken: tokens.RefreshToken})
+	assert.ErrorIs(t, err, services.ErrInvalidToken)
+
+	// Case 4: Garbage
+	_, err = service.Refresh(viewmodels.RefreshRequest{RefreshToken: "not.a.token"})
+	assert.ErrorIs(t, err, services.ErrInvalidToken)
+}
+
+func TestCreateUser(t *testing.T) {
+	mockUserRepo := new(MockUserRepo)
+	mockStudentRepo := new(MockStudentRepo)
+	service := services.NewAuthService(mockUserRepo, mockStudentRepo, []byte("secret"))
+
+	// Case 1: Success, only the hash is stored
+	mockUserRepo.On("GetByEmail", "t@test.com").Return(nil, gorm.ErrRecordNotFound).Once()
+	mockUserRepo.On("Create", mock.MatchedBy(func(u *models.User) bool {
+		return u.Role == models.RoleTeacher && bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte("password1")) == nil
+	})).Return(nil).Once()
+	resp, err := service.CreateUser(viewmodels.CreateUserRequest{Email: "t@test.com", Password: "password1", Role: models.RoleTeacher})
+	assert.NoError(t, err)
+	assert.Equal(t, "t@test.com", resp.Email)
+
+	// Case 2: Email taken
+	mockUserRepo.On("GetByEmail", "t@test.com").Return(&models.User{}, nil).Once()
+	_, err = service.CreateUser(viewmodels.CreateUserRequest{Email: "t@test.com", Password: "password1", Role: models.RoleTeacher})
+	assert.ErrorIs(t, err, services.ErrUserExists)
+
+	// Case 3: Student account pointing to a missing student
+	missing := uint(99)
+	mockUserRepo.On("GetByEmail", "s@test.com").Return(nil, gorm.ErrRecordNotFound).Once()
+	mockStudentRepo.On("GetByID", uint(99)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.CreateUser(viewmodels.CreateUserRequest{Email: "s@test.com", Password: "password1", Role: models.RoleStudent, StudentID: &missing})
+	assert.ErrorIs(t, err, services.ErrStudentRequired)
+
+	// Case 4: DB Error
+	mockUserRepo.On("GetByEmail", "x@test.com").Return(nil, errors.New("db error")).Once()
+	_, err = service.CreateUser(viewmodels.CreateUserRequest{Email: "x@test.com", Password: "password1", Role: models.RoleAdmin})
+	assert.Error(t, err)
+}
+
+func TestEnsureAdmin(t *testing.T) {
+	mockUserRepo := new(MockUserRepo)
+	service := services.NewAuthService(mockUserRepo, new(MockStudentRepo), []byte("secret"))
+
+	// Case 1: Already there, nothing to do
+	mockUserRepo.On("GetByEmail", "admin@test.com").Return(&models.User{}, nil).Once()
+	assert.NoError(t, service.EnsureAdmin("admin@test.com", "password1"))
+
+	// Case 2: Created
+	mockUserRepo.On("GetByEmail", "admin@test.com").Return(nil, gorm.ErrRecordNotFound).Once()
+	mockUserRepo.On("Create", mock.MatchedBy(func(u *models.User) bool { return u.Role == models.RoleAdmin })).Return(nil).Once()
+	assert.NoError(t, service.EnsureAdmin("admin@test.com", "password1"))
+
+	// Case 3: Password shorter than any account may have
+	assert.Error(t, service.EnsureAdmin("admin@test.com", "admin"))
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestValidateSecret(t *testing.T) {
+	assert.Error(t, services.ValidateSecret(""))
+	assert.Error(t, services.ValidateSecret("change-me-in-production"))
+	assert.Error(t, services.ValidateSecret("short-secret"))
+	assert.NoError(t, services.ValidateSecret("0123456789abcdef0123456789abcdef"))
+}
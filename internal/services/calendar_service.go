@@ -0,0 +1,333 @@
+package services
+
+import (
+	"fmt"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
+	"hrms_backend/internal/viewmodels"
+	"strings"
+	"time"
+)
+
+// maxCalendarDays bounds the range of a single GetCalendar call
+const maxCalendarDays = 366
+
+var (
+	// ErrTermNotFound is returned when a term ID does not exist.
+	ErrTermNotFound = &Error{Kind: ErrNotFound, Code: "term_not_found", Message: "term not found"}
+	// ErrTermNameTaken is returned when creating or renaming a term to a name already in use.
+	ErrTermNameTaken = &Error{
+		Kind:    ErrConflict,
+		Code:    "term_name_taken",
+		Message: "a term with this name already exists",
+		Fields:  map[string]string{"name": "already in use"},
+	}
+	// ErrClosureNotFound is returned when a holiday or closure ID does not exist.
+	ErrClosureNotFound = &Error{Kind: ErrNotFound, Code: "closure_not_found", Message: "holiday or closure not found"}
+	// ErrNonSchoolDay is returned when attendance is marked on a weekend, holiday, closure day or outside every term.
+	ErrNonSchoolDay = &Error{
+		Kind:    ErrValidation,
+		Code:    "non_school_day",
+		Message: "date is not a school day",
+		Fields:  map[string]string{"date": "not a school day"},
+	}
+)
+
+// SchoolCalendar tells which days have no classes. CalendarService implements it for the
+// services that mark or aggregate attendance.
+type SchoolCalendar interface {
+	// NonSchoolDays maps every day of [from, to] without classes (UTC midnight) to the reason,
+	// e.g. "weekend" or "holiday: Winter break". School days are left out.
+	NonSchoolDays(from, to time.Time) (map[time.Time]string, error)
+}
+
+type CalendarService interface {
+	SchoolCalendar
+	CreateTerm(req viewmodels.TermRequest) (*viewmodels.TermResponse, error)
+	ListTerms() ([]viewmodels.TermResponse, error)
+	UpdateTerm(id uint, req viewmodels.TermRequest) (*viewmodels.TermResponse, error)
+	DeleteTerm(id uint) error
+	CreateClosure(req viewmodels.ClosureRequest) (*viewmodels.ClosureResponse, error)
+	ListClosures(from, to time.Time) ([]viewmodels.ClosureResponse, error)
+	UpdateClosure(id uint, req viewmodels.ClosureRequest) (*viewmodels.ClosureResponse, error)
+	DeleteClosure(id uint) error
+	GetCalendar(from, to time.Time) ([]viewmodels.CalendarDayResponse, error)
+}
+
+type calendarService struct {
+	repo    repository.CalendarRepository
+	weekend map[time.Weekday]bool
+}
+
+// NewCalendarService takes the days of the week without classes, see ParseWeekend
+func NewCalendarService(repo repository.CalendarRepository, weekend []time.Weekday) CalendarService {
+	days := make(map[time.Weekday]bool, len(weekend))
+	for _, d := range weekend {
+		days[d] = true
+	}
+	return &calendarService{repo: repo, weekend: days}
+}
+
+// ParseWeekend reads a comma separated list of day names, e.g. "friday,saturday".
+// An empty value means Saturday and Sunday, "none" a seven day week.
+func ParseWeekend(value string) ([]time.Weekday, error) {
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(value) {
+	case "":
+		return []time.Weekday{time.Saturday, time.Sunday}, nil
+	case "none":
+		return nil, nil
+	}
+
+	var days []time.Weekday
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		day, ok := weekdayNamed(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q", name)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func (s *calendarService) CreateTerm(req viewmodels.TermRequest) (*viewmodels.TermResponse, error) {
+	term := models.Term{Name: req.Name}
+	if err := applyTermDates(&term, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateTerm(&term); err != nil {
+		return nil, translateDBError(err, nil, ErrTermNameTaken)
+	}
+	resp := toTermResponse(term)
+	return &resp, nil
+}
+
+// ListTerms returns every term in chronological order
+func (s *calendarService) ListTerms() ([]viewmodels.TermResponse, error) {
+	terms, err := s.repo.GetTerms()
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]viewmodels.TermResponse, 0, len(terms))
+	for _, t := range terms {
+		responses = append(responses, toTermResponse(t))
+	}
+	return responses, nil
+}
+
+func (s *calendarService) UpdateTerm(id uint, req viewmodels.TermRequest) (*viewmodels.TermResponse, error) {
+	term, err := s.repo.GetTermByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrTermNotFound, nil)
+	}
+	term.Name = req.Name
+	if err := applyTermDates(term, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateTerm(term); err != nil {
+		return nil, translateDBError(err, ErrTermNotFound, ErrTermNameTaken)
+	}
+	resp := toTermResponse(*term)
+	return &resp, nil
+}
+
+func (s *calendarService) DeleteTerm(id uint) error {
+	return translateDBError(s.repo.DeleteTerm(id), ErrTermNotFound, nil)
+}
+
+func (s *calendarService) CreateClosure(req viewmodels.ClosureRequest) (*viewmodels.ClosureResponse, error) {
+	var closure models.Closure
+	if err := applyClosure(&closure, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateClosure(&closure); err != nil {
+		return nil, err
+	}
+	resp := toClosureResponse(closure)
+	return &resp, nil
+}
+
+// ListClosures returns the holidays and closures overlapping [from, to] in chronological order.
+// Zero bounds are open.
+func (s *calendarService) ListClosures(from, to time.Time) ([]viewmodels.ClosureResponse, error) {
+	if !from.IsZero() {
+		from = truncateToDay(from)
+	}
+	if !to.IsZero() {
+		to = truncateToDay(to)
+	}
+	closures, err := s.repo.GetClosuresBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]viewmodels.ClosureResponse, 0, len(closures))
+	for _, c := range closures {
+		responses = append(responses, toClosureResponse(c))
+	}
+	return responses, nil
+}
+
+func (s *calendarService) UpdateClosure(id uint, req viewmodels.ClosureRequest) (*viewmodels.ClosureResponse, error) {
+	closure, err := s.repo.GetClosureByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrClosureNotFound, nil)
+	}
+	if err := applyClosure(closure, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateClosure(closure); err != nil {
+		return nil, translateDBError(err, ErrClosureNotFound, nil)
+	}
+	resp := toClosureResponse(*closure)
+	return &resp, nil
+}
+
+func (s *calendarService) DeleteClosure(id uint) error {
+	return translateDBError(s.repo.DeleteClosure(id), ErrClosureNotFound, nil)
+}
+
+// GetCalendar returns every day of [from, to] (less than a year) with whether classes take place
+func (s *calendarService) GetCalendar(from, to time.Time) ([]viewmodels.CalendarDayResponse, error) {
+	from, to = truncateToDay(from), truncateToDay(to)
+	if to.Before(from) {
+		return nil, ValidationError("to", "must not be before from")
+	}
+	if to.Sub(from) >= maxCalendarDays*24*time.Hour {
+		return nil, ValidationError("to", "must be less than a year after from")
+	}
+
+	off, err := s.NonSchoolDays(from, to)
+	if err != nil {
+		return nil, err
+	}
+	var days []viewmodels.CalendarDayResponse
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		reason := off[day]
+		days = append(days, viewmodels.CalendarDayResponse{Date: day, SchoolDay: reason == "", Reason: reason})
+	}
+	return days, nil
+}
+
+// NonSchoolDays checks, in this order, holidays and closures, the weekend and terms. Terms only
+// count once at least one is defined, so a school that never set them up keeps every weekday.
+func (s *calendarService) NonSchoolDays(from, to time.Time) (map[time.Time]string, error) {
+	from, to = truncateToDay(from), truncateToDay(to)
+	off := make(map[time.Time]string)
+	if to.Before(from) {
+		return off, nil
+	}
+
+	closures, err := s.repo.GetClosuresBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+	termCount, err := s.repo.CountTerms()
+	if err != nil {
+		return nil, err
+	}
+	var terms []models.Term
+	if termCount > 0 {
+		if terms, err = s.repo.GetTermsBetween(from, to); err != nil {
+			return nil, err
+		}
+	}
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if c := closureOn(closures, day); c != nil {
+			off[day] = c.Kind + ": " + c.Name
+		} else if s.weekend[day.Weekday()] {
+			off[day] = "weekend"
+		} else if termCount > 0 && !inTerm(terms, day) {
+			off[day] = "outside term"
+		}
+	}
+	return off, nil
+}
+
+// nonSchoolDayError reports the reason a day has no classes, e.g. "weekend"
+func nonSchoolDayError(reason string) *Error {
+	err := ErrNonSchoolDay.Wrap(nil)
+	err.Message = "date is not a school day (" + reason + ")"
+	err.Fields["date"] = reason
+	return err
+}
+
+// onSchoolDays drops the records dated on a day without classes, e.g. marked before a holiday
+// was entered, so aggregations count school days only
+func onSchoolDays(calendar SchoolCalendar, records []models.Attendance) ([]models.Attendance, error) {
+	if len(records) == 0 {
+		return records, nil
+	}
+	first, last := truncateToDay(records[0].Date), truncateToDay(records[0].Date)
+	for _, rec := range records[1:] {
+		day := truncateToDay(rec.Date)
+		if day.Before(first) {
+			first = day
+		}
+		if day.After(last) {
+			last = day
+		}
+	}
+	off, err := calendar.NonSchoolDays(first, last)
+	if err != nil || len(off) == 0 {
+		return records, err
+	}
+
+	kept := make([]models.Attendance, 0, len(records))
+	for _, rec := range records {
+		if _, closed := off[truncateToDay(rec.Date)]; !closed {
+			kept = append(kept, rec)
+		}
+	}
+	return kept, nil
+}
+
+func closureOn(closures []models.Closure, day time.Time) *models.Closure {
+	for i, c := range closures {
+		if !day.Before(truncateToDay(c.StartDate)) && !day.After(truncateToDay(c.EndDate)) {
+			return &closures[i]
+		}
+	}
+	return nil
+}
+
+func inTerm(terms []models.Term, day time.Time) bool {
+	for _, t := range terms {
+		if !day.Before(truncateToDay(t.StartDate)) && !day.After(truncateToDay(t.EndDate)) {
+			return true
+		}
+	}
+	return false
+}
+
+func applyTermDates(term *models.Term, req viewmodels.TermRequest) error {
+	term.StartDate, term.EndDate = truncateToDay(req.StartDate), truncateToDay(req.EndDate)
+	if term.EndDate.Before(term.StartDate) {
+		return ValidationError("end_date", "must not be before start_date")
+	}
+	return nil
+}
+
+func applyClosure(closure *models.Closure, req viewmodels.ClosureRequest) error {
+	closure.Kind, closure.Name = req.Kind, req.Name
+	closure.StartDate, closure.EndDate = truncateToDay(req.StartDate), truncateToDay(req.EndDate)
+	if closure.EndDate.Before(closure.StartDate) {
+		return ValidationError("end_date", "must not be before start_date")
+	}
+	return nil
+}
+
+func toTermResponse(term models.Term) viewmodels.TermResponse {
+	return viewmodels.TermResponse{ID: term.ID, Name: term.Name, StartDate: term.StartDate, EndDate: term.EndDate}
+}
+
+func toClosureResponse(closure models.Closure) viewmodels.ClosureResponse {
+	return viewmodels.ClosureResponse{
+		ID:        closure.ID,
+		Kind:      closure.Kind,
+		Name:      closure.Name,
+		StartDate: closure.StartDate,
+		EndDate:   closure.EndDate,
+	}
+}
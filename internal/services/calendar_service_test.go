@@ -0,0 +1,252 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// fakeCalendar is a SchoolCalendar with the given days off; the zero value has classes every day
+type fakeCalendar map[time.Time]string
+
+func (f fakeCalendar) NonSchoolDays(from, to time.Time) (map[time.Time]string, error) {
+	off := make(map[time.Time]string)
+	for day, reason := range f {
+		if !day.Before(from) && !day.After(to) {
+			off[day] = reason
+		}
+	}
+	return off, nil
+}
+
+// --- Mock Calendar Repo ---
+type MockCalendarRepo struct {
+	mock.Mock
+}
+
+func (m *MockCalendarRepo) CreateTerm(term *models.Term) error {
+	args := m.Called(term)
+	return args.Error(0)
+}
+
+func (m *MockCalendarRepo) GetTerms() ([]models.Term, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Term), args.Error(1)
+}
+
+func (m *MockCalendarRepo) GetTermByID(id uint) (*models.Term, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Term), args.Error(1)
+}
+
+func (m *MockCalendarRepo) UpdateTerm(term *models.Term) error {
+	args := m.Called(term)
+	return args.Error(0)
+}
+
+func (m *MockCalendarRepo) DeleteTerm(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCalendarRepo) GetTermsBetween(from, to time.Time) ([]models.Term, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Term), args.Error(1)
+}
+
+func (m *MockCalendarRepo) CountTerms() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCalendarRepo) CreateClosure(closure *models.Closure) error {
+	args := m.Called(closure)
+	return args.Error(0)
+}
+
+func (m *MockCalendarRepo) GetClosuresBetween(from, to time.Time) ([]models.Closure, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Closure), args.Error(1)
+}
+
+func (m *MockCalendarRepo) GetClosureByID(id uint) (*models.Closure, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Closure), args.Error(1)
+}
+
+func (m *MockCalendarRepo) UpdateClosure(closure *models.Closure) error {
+	args := m.Called(closure)
+	return args.Error(0)
+}
+
+func (m *MockCalendarRepo) DeleteClosure(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// --- Tests ---
+
+func TestParseWeekend(t *testing.T) {
+	days, err := services.ParseWeekend("")
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Weekday{time.Saturday, time.Sunday}, days)
+
+	days, err = services.ParseWeekend("Friday, saturday")
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Weekday{time.Friday, time.Saturday}, days)
+
+	days, err = services.ParseWeekend("none")
+	assert.NoError(t, err)
+	assert.Empty(t, days)
+
+	_, err = services.ParseWeekend("sat")
+	assert.Error(t, err)
+}
+
+func TestNonSchoolDays(t *testing.T) {
+	mockRepo := new(MockCalendarRepo)
+	service := services.NewCalendarService(mockRepo, []time.Weekday{time.Saturday, time.Sunday})
+
+	// Thursday 2025-12-18 to Tuesday 2025-12-23
+	from := time.Date(2025, 12, 18, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 12, 23, 0, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2025, 12, d, 0, 0, 0, 0, time.UTC) }
+
+	// Case 1: No terms defined, a holiday over the weekend wins over "weekend"
+	mockRepo.On("GetClosuresBetween", from, to).Return([]models.Closure{
+		{Kind: models.ClosureHoliday, Name: "Winter break", StartDate: day(20), EndDate: day(22)},
+	}, nil).Once()
+	mockRepo.On("CountTerms").Return(int64(0), nil).Once()
+	off, err := service.NonSchoolDays(from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, map[time.Time]string{
+		day(20): "holiday: Winter break",
+		day(21): "holiday: Winter break",
+		day(22): "holiday: Winter break",
+	}, off)
+
+	// Case 2: Terms defined, days outside them are off
+	mockRepo.On("GetClosuresBetween", from, to).Return([]models.Closure{}, nil).Once()
+	mockRepo.On("CountTerms").Return(int64(2), nil).Once()
+	mockRepo.On("GetTermsBetween", from, to).Return([]models.Term{{Name: "Fall", StartDate: day(1), EndDate: day(19)}}, nil).Once()
+	off, err = service.NonSchoolDays(from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, map[time.Time]string{
+		day(20): "weekend",
+		day(21): "weekend",
+		day(22): "outside term",
+		day(23): "outside term",
+	}, off)
+
+	// Case 3: DB error
+	mockRepo.On("GetClosuresBetween", from, to).Return(nil, errors.New("db error")).Once()
+	_, err = service.NonSchoolDays(from, to)
+	assert.Error(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetCalendar(t *testing.T) {
+	mockRepo := new(MockCalendarRepo)
+	service := services.NewCalendarService(mockRepo, nil)
+	from := time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 12, 26, 0, 0, 0, 0, time.UTC)
+
+	// Case 1: One entry per day
+	mockRepo.On("GetClosuresBetween", from, to).Return([]models.Closure{
+		{Kind: models.ClosureHoliday, Name: "Christmas", StartDate: to.AddDate(0, 0, -1), EndDate: to.AddDate(0, 0, -1)},
+	}, nil).Once()
+	mockRepo.On("CountTerms").Return(int64(0), nil).Once()
+	days, err := service.GetCalendar(from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, []viewmodels.CalendarDayResponse{
+		{Date: from, SchoolDay: true},
+		{Date: from.AddDate(0, 0, 1), Reason: "holiday: Christmas"},
+		{Date: to, SchoolDay: true},
+	}, days)
+
+	// Case 2: More than a year
+	_, err = service.GetCalendar(from, from.AddDate(0, 0, 366))
+	assert.ErrorIs(t, err, services.ErrValidation)
+}
+
+func TestTermCRUD(t *testing.T) {
+	mockRepo := new(MockCalendarRepo)
+	service := services.NewCalendarService(mockRepo, nil)
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 12, 19, 0, 0, 0, 0, time.UTC)
+
+	// Case 1: Create
+	mockRepo.On("CreateTerm", mock.MatchedBy(func(term *models.Term) bool {
+		return term.Name == "Fall 2025" && term.StartDate.Equal(start) && term.EndDate.Equal(end)
+	})).Return(nil).Once()
+	resp, err := service.CreateTerm(viewmodels.TermRequest{Name: "Fall 2025", StartDate: start, EndDate: end})
+	assert.NoError(t, err)
+	assert.Equal(t, "Fall 2025", resp.Name)
+
+	// Case 2: Ends before it starts
+	_, err = service.CreateTerm(viewmodels.TermRequest{Name: "Fall 2025", StartDate: end, EndDate: start})
+	assert.ErrorIs(t, err, services.ErrValidation)
+
+	// Case 3: Name taken
+	mockRepo.On("CreateTerm", mock.Anything).Return(gorm.ErrDuplicatedKey).Once()
+	_, err = service.CreateTerm(viewmodels.TermRequest{Name: "Fall 2025", StartDate: start, EndDate: end})
+	assert.ErrorIs(t, err, services.ErrTermNameTaken)
+
+	// Case 4: Update unknown term
+	mockRepo.On("GetTermByID", uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.UpdateTerm(9, viewmodels.TermRequest{Name: "Fall 2025", StartDate: start, EndDate: end})
+	assert.ErrorIs(t, err, services.ErrTermNotFound)
+
+	// Case 5: Delete
+	mockRepo.On("DeleteTerm", uint(1)).Return(nil).Once()
+	assert.NoError(t, service.DeleteTerm(1))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClosureCRUD(t *testing.T) {
+	mockRepo := new(MockCalendarRepo)
+	service := services.NewCalendarService(mockRepo, nil)
+	day := time.Date(2025, 12, 25, 15, 0, 0, 0, time.UTC)
+
+	// Case 1: Create a single day, time of day dropped
+	mockRepo.On("CreateClosure", mock.MatchedBy(func(c *models.Closure) bool {
+		return c.Kind == models.ClosureHoliday && c.StartDate.Equal(c.EndDate) && c.StartDate.Hour() == 0
+	})).Return(nil).Once()
+	resp, err := service.CreateClosure(viewmodels.ClosureRequest{Kind: "holiday", Name: "Christmas", StartDate: day, EndDate: day})
+	assert.NoError(t, err)
+	assert.Equal(t, "Christmas", resp.Name)
+
+	// Case 2: List with open bounds
+	mockRepo.On("GetClosuresBetween", time.Time{}, time.Time{}).Return([]models.Closure{{ID: 1, Name: "Christmas"}}, nil).Once()
+	list, err := service.ListClosures(time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	// Case 3: Delete unknown closure
+	mockRepo.On("DeleteClosure", uint(9)).Return(gorm.ErrRecordNotFound).Once()
+	assert.ErrorIs(t, service.DeleteClosure(9), services.ErrClosureNotFound)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
+	"hrms_backend/internal/viewmodels"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrCourseNotFound is returned when a course ID does not exist.
+	ErrCourseNotFound = &Error{Kind: ErrNotFound, Code: "course_not_found", Message: "course not found"}
+	// ErrCourseCodeTaken is returned when creating or updating a course with a code already in use.
+	ErrCourseCodeTaken = &Error{
+		Kind:    ErrConflict,
+		Code:    "course_code_taken",
+		Message: "a course with this code already exists",
+		Fields:  map[string]string{"code": "already in use"},
+	}
+	// ErrCourseInUse is returned when deleting a course that still has sections.
+	ErrCourseInUse = &Error{Kind: ErrConflict, Code: "course_in_use", Message: "course still has sections, delete them first"}
+	// ErrSectionNotFound is returned when a section ID does not exist.
+	ErrSectionNotFound = &Error{Kind: ErrNotFound, Code: "section_not_found", Message: "section not found"}
+	// ErrSectionNameTaken is returned when a course already has a section with that name.
+	ErrSectionNameTaken = &Error{
+		Kind:    ErrConflict,
+		Code:    "section_name_taken",
+		Message: "the course already has a section with this name",
+		Fields:  map[string]string{"name": "already in use"},
+	}
+	// ErrSectionInUse is returned when deleting a section that attendance was recorded in.
+	ErrSectionInUse = &Error{Kind: ErrConflict, Code: "section_in_use", Message: "attendance was recorded in this section, it cannot be deleted"}
+	// ErrEnrollmentNotFound is returned when removing a student that is not enrolled in the section.
+	ErrEnrollmentNotFound = &Error{Kind: ErrNotFound, Code: "enrollment_not_found", Message: "student is not enrolled in this section"}
+	// ErrUnknownSection is returned when attendance names a section that does not exist.
+	ErrUnknownSection = &Error{
+		Kind:    ErrValidation,
+		Code:    "unknown_section",
+		Message: "section does not exist",
+		Fields:  map[string]string{"section_id": "does not exist"},
+	}
+	// ErrStudentNotEnrolled is returned when marking attendance in a section the student is not enrolled in.
+	ErrStudentNotEnrolled = &Error{
+		Kind:    ErrValidation,
+		Code:    "student_not_enrolled",
+		Message: "student is not enrolled in this section",
+		Fields:  map[string]string{"student_id": "not enrolled in the section"},
+	}
+)
+
+type CourseService interface {
+	CreateCourse(req viewmodels.CourseRequest) (*viewmodels.CourseResponse, error)
+	ListCourses() ([]viewmodels.CourseResponse, error)
+	GetCourseByID(id uint) (*viewmodels.CourseResponse, error)
+	UpdateCourse(id uint, req viewmodels.CourseRequest) (*viewmodels.CourseResponse, error)
+	DeleteCourse(id uint) error
+
+	CreateSection(courseID uint, req viewmodels.SectionRequest) (*viewmodels.SectionResponse, error)
+	ListSections(courseID uint) ([]viewmodels.SectionResponse, error)
+	GetSectionByID(id uint) (*viewmodels.SectionResponse, error)
+	UpdateSection(id uint, req viewmodels.SectionRequest) (*viewmodels.SectionResponse, error)
+	DeleteSection(id uint) error
+
+	EnrollStudents(sectionID uint, req viewmodels.EnrollStudentsRequest) (*viewmodels.EnrollStudentsResponse, error)
+	UnenrollStudent(sectionID, studentID uint) error
+	ListEnrolledStudents(sectionID uint) ([]viewmodels.StudentResponse, error)
+}
+
+type courseService struct {
+	courses     repository.CourseRepository
+	sections    repository.SectionRepository
+	studentRepo repository.StudentRepository
+}
+
+func NewCourseService(courses repository.CourseRepository, sections repository.SectionRepository, studentRepo repository.StudentRepository) CourseService {
+	return &courseService{courses: courses, sections: sections, studentRepo: studentRepo}
+}
+
+func (s *courseService) CreateCourse(req viewmodels.CourseRequest) (*viewmodels.CourseResponse, error) {
+	course := models.Course{Code: req.Code, Name: req.Name}
+	if err := s.courses.Create(&course); err != nil {
+		return nil, translateDBError(err, nil, ErrCourseCodeTaken)
+	}
+	resp := toCourseResponse(course)
+	return &resp, nil
+}
+
+// ListCourses returns every course ordered by code
+func (s *courseService) ListCourses() ([]viewmodels.CourseResponse, error) {
+	courses, err := s.courses.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]viewmodels.CourseResponse, 0, len(courses))
+	for _, c := range courses {
+		responses = append(responses, toCourseResponse(c))
+	}
+	return responses, nil
+}
+
+func (s *courseService) GetCourseByID(id uint) (*viewmodels.CourseResponse, error) {
+	course, err := s.courses.GetByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrCourseNotFound, nil)
+	}
+	resp := toCourseResponse(*course)
+	return &resp, nil
+}
+
+func (s *courseService) UpdateCourse(id uint, req viewmodels.CourseRequest) (*viewmodels.CourseResponse, error) {
+	course, err := s.courses.GetByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrCourseNotFound, nil)
+	}
+	course.Code = req.Code
+	course.Name = req.Name
+	if err := s.courses.Update(course); err != nil {
+		return nil, translateDBError(err, ErrCourseNotFound, ErrCourseCodeTaken)
+	}
+	resp := toCourseResponse(*course)
+	return &resp, nil
+}
+
+// DeleteCourse removes a course without sections
+func (s *courseService) DeleteCourse(id uint) error {
+	err := s.courses.Delete(id)
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return ErrCourseInUse.Wrap(err)
+	}
+	return translateDBError(err, ErrCourseNotFound, nil)
+}
+
+func (s *courseService) CreateSection(courseID uint, req viewmodels.SectionRequest) (*viewmodels.SectionResponse, error) {
+	course, err := s.courses.GetByID(courseID)
+	if err != nil {
+		return nil, translateDBError(err, ErrCourseNotFound, nil)
+	}
+	section := models.Section{CourseID: course.ID, Course: *course, Name: req.Name}
+	if err := s.sections.Create(&section); err != nil {
+		return nil, translateDBError(err, nil, ErrSectionNameTaken)
+	}
+	resp := toSectionResponse(section, 0)
+	return &resp, nil
+}
+
+// ListSections returns the sections of a course ordered by name, with their number of students
+func (s *courseService) ListSections(courseID uint) ([]viewmodels.SectionResponse, error) {
+	if _, err := s.courses.GetByID(courseID); err != nil {
+		return nil, translateDBError(err, ErrCourseNotFound, nil)
+	}
+	sections, err := s.sections.GetByCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(sections))
+	for _, sec := range sections {
+		ids = append(ids, sec.ID)
+	}
+	counts, err := s.sections.CountEnrollments(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]viewmodels.SectionResponse, 0, len(sections))
+	for _, sec := range sections {
+		responses = append(responses, toSectionResponse(sec, counts[sec.ID]))
+	}
+	return responses, nil
+}
+
+func (s *courseService) GetSectionByID(id uint) (*viewmodels.SectionResponse, error) {
+	section, err := s.getSection(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.withEnrollmentCount(*section)
+}
+
+// UpdateSection renames a section
+func (s *courseService) UpdateSection(id uint, req viewmodels.SectionRequest) (*viewmodels.SectionResponse, error) {
+	section, err := s.getSection(id)
+	if err != nil {
+		return nil, err
+	}
+	section.Name = req.Name
+	if err := s.sections.Update(section); err != nil {
+		return nil, translateDBError(err, ErrSectionNotFound, ErrSectionNameTaken)
+	}
+	return s.withEnrollmentCount(*section)
+}
+
+// DeleteSection removes a section with its enrollments, unless attendance was recorded in it
+func (s *courseService) DeleteSection(id uint) error {
+	err := s.sections.Delete(id)
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return ErrSectionInUse.Wrap(err)
+	}
+	return translateDBError(err, ErrSectionNotFound, nil)
+}
+
+// EnrollStudents adds students to a section. Students already enrolled are left as is,
+// so repeating a request is harmless. Unknown or deleted students fail the whole request.
+func (s *courseService) EnrollStudents(sectionID uint, req viewmodels.EnrollStudentsRequest) (*viewmodels.EnrollStudentsResponse, error) {
+	if _, err := s.getSection(sectionID); err != nil {
+		return nil, err
+	}
+
+	ids := slices.Clone(req.StudentIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	students, err := s.studentRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(students) != len(ids) {
+		return nil, ValidationError("student_ids", "unknown students: "+joinIDs(missingStudents(ids, students)))
+	}
+
+	enrolled, err := s.sections.Enroll(sectionID, ids)
+	if err != nil {
+		return nil, err
+	}
+	return &viewmodels.EnrollStudentsResponse{Enrolled: enrolled, AlreadyEnrolled: len(ids) - enrolled}, nil
+}
+
+// UnenrollStudent removes a student from a section; attendance already recorded in it is kept
+func (s *courseService) UnenrollStudent(sectionID, studentID uint) error {
+	if _, err := s.getSection(sectionID); err != nil {
+		return err
+	}
+	return translateDBError(s.sections.Unenroll(sectionID, studentID), ErrEnrollmentNotFound, nil)
+}
+
+// ListEnrolledStudents returns the students of a section ordered by name
+func (s *courseService) ListEnrolledStudents(sectionID uint) ([]viewmodels.StudentResponse, error) {
+	if _, err := s.getSection(sectionID); err != nil {
+		return nil, err
+	}
+	students, err := s.sections.GetEnrolledStudents(sectionID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]viewmodels.StudentResponse, 0, len(students))
+	for _, st := range students {
+		responses = append(responses, toStudentResponse(st))
+	}
+	return responses, nil
+}
+
+func (s *courseService) getSection(id uint) (*models.Section, error) {
+	section, err := s.sections.GetByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrSectionNotFound, nil)
+	}
+	return section, nil
+}
+
+func (s *courseService) withEnrollmentCount(section models.Section) (*viewmodels.SectionResponse, error) {
+	counts, err := s.sections.CountEnrollments([]uint{section.ID})
+	if err != nil {
+		return nil, err
+	}
+	resp := toSectionResponse(section, counts[section.ID])
+	return &resp, nil
+}
+
+// missingStudents returns the IDs (sorted) that no student was found for
+func missingStudents(ids []uint, students []models.Student) []uint {
+	found := make(map[uint]bool, len(students))
+	for _, st := range students {
+		found[st.ID] = true
+	}
+	var missing []uint
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+func joinIDs(ids []uint) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprint(id)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func toCourseResponse(course models.Course) viewmodels.CourseResponse {
+	return viewmodels.CourseResponse{
+		ID:        course.ID,
+		Code:      course.Code,
+		Name:      course.Name,
+		CreatedAt: course.CreatedAt,
+	}
+}
+
+func toSectionResponse(section models.Section, students int64) viewmodels.SectionResponse {
+	return viewmodels.SectionResponse{
+		ID:         section.ID,
+		CourseID:   section.CourseID,
+		CourseCode: section.Course.Code,
+		Name:       section.Name,
+		Students:   students,
+		CreatedAt:  section.CreatedAt,
+	}
+}
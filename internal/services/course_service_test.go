@@ -0,0 +1,220 @@
+package services_test
+
+import (
+	"testing"
+
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// --- Mock Course Repo ---
+type MockCourseRepo struct {
+	mock.Mock
+}
+
+func (m *MockCourseRepo) Create(course *models.Course) error {
+	args := m.Called(course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepo) GetAll() ([]models.Course, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Course), args.Error(1)
+}
+
+func (m *MockCourseRepo) GetByID(id uint) (*models.Course, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Course), args.Error(1)
+}
+
+func (m *MockCourseRepo) Update(course *models.Course) error {
+	args := m.Called(course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepo) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// --- Mock Section Repo ---
+type MockSectionRepo struct {
+	mock.Mock
+}
+
+func (m *MockSectionRepo) Create(section *models.Section) error {
+	args := m.Called(section)
+	return args.Error(0)
+}
+
+func (m *MockSectionRepo) GetByCourse(courseID uint) ([]models.Section, error) {
+	args := m.Called(courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Section), args.Error(1)
+}
+
+func (m *MockSectionRepo) GetByID(id uint) (*models.Section, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Section), args.Error(1)
+}
+
+func (m *MockSectionRepo) Update(section *models.Section) error {
+	args := m.Called(section)
+	return args.Error(0)
+}
+
+func (m *MockSectionRepo) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockSectionRepo) CountEnrollments(sectionIDs []uint) (map[uint]int64, error) {
+	args := m.Called(sectionIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uint]int64), args.Error(1)
+}
+
+func (m *MockSectionRepo) Enroll(sectionID uint, studentIDs []uint) (int, error) {
+	args := m.Called(sectionID, studentIDs)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSectionRepo) Unenroll(sectionID, studentID uint) error {
+	args := m.Called(sectionID, studentID)
+	return args.Error(0)
+}
+
+func (m *MockSectionRepo) GetEnrolledStudents(sectionID uint) ([]models.Student, error) {
+	args := m.Called(sectionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Student), args.Error(1)
+}
+
+func (m *MockSectionRepo) IsEnrolled(sectionID, studentID uint) (bool, error) {
+	args := m.Called(sectionID, studentID)
+	return args.Bool(0), args.Error(1)
+}
+
+// --- Tests ---
+
+func TestCreateCourse(t *testing.T) {
+	mockCourses := new(MockCourseRepo)
+	service := services.NewCourseService(mockCourses, new(MockSectionRepo), new(MockStudentRepo))
+
+	// Case 1: Success
+	mockCourses.On("Create", mock.MatchedBy(func(c *models.Course) bool { return c.Code == "CS101" })).Return(nil).Once()
+	resp, err := service.CreateCourse(viewmodels.CourseRequest{Code: "CS101", Name: "Programming"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Programming", resp.Name)
+
+	// Case 2: Code taken
+	mockCourses.On("Create", mock.Anything).Return(gorm.ErrDuplicatedKey).Once()
+	_, err = service.CreateCourse(viewmodels.CourseRequest{Code: "cs101", Name: "Programming"})
+	assert.ErrorIs(t, err, services.ErrCourseCodeTaken)
+}
+
+func TestDeleteCourse(t *testing.T) {
+	mockCourses := new(MockCourseRepo)
+	service := services.NewCourseService(mockCourses, new(MockSectionRepo), new(MockStudentRepo))
+
+	// Case 1: Still has sections
+	mockCourses.On("Delete", uint(1)).Return(gorm.ErrForeignKeyViolated).Once()
+	err := service.DeleteCourse(1)
+	assert.ErrorIs(t, err, services.ErrCourseInUse)
+	assert.ErrorIs(t, err, services.ErrConflict)
+
+	// Case 2: Not found
+	mockCourses.On("Delete", uint(9)).Return(gorm.ErrRecordNotFound).Once()
+	assert.ErrorIs(t, service.DeleteCourse(9), services.ErrCourseNotFound)
+}
+
+func TestSections(t *testing.T) {
+	mockCourses := new(MockCourseRepo)
+	mockSections := new(MockSectionRepo)
+	service := services.NewCourseService(mockCourses, mockSections, new(MockStudentRepo))
+	course := models.Course{ID: 1, Code: "CS101"}
+
+	// Case 1: Create
+	mockCourses.On("GetByID", uint(1)).Return(&course, nil).Once()
+	mockSections.On("Create", mock.MatchedBy(func(s *models.Section) bool { return s.CourseID == 1 && s.Name == "A" })).Return(nil).Once()
+	resp, err := service.CreateSection(1, viewmodels.SectionRequest{Name: "A"})
+	assert.NoError(t, err)
+	assert.Equal(t, "CS101", resp.CourseCode)
+
+	// Case 2: Name taken within the course
+	mockCourses.On("GetByID", uint(1)).Return(&course, nil).Once()
+	mockSections.On("Create", mock.Anything).Return(gorm.ErrDuplicatedKey).Once()
+	_, err = service.CreateSection(1, viewmodels.SectionRequest{Name: "A"})
+	assert.ErrorIs(t, err, services.ErrSectionNameTaken)
+
+	// Case 3: List with enrollment counts
+	mockCourses.On("GetByID", uint(1)).Return(&course, nil).Once()
+	mockSections.On("GetByCourse", uint(1)).Return([]models.Section{{ID: 3, CourseID: 1, Name: "A"}, {ID: 4, CourseID: 1, Name: "B"}}, nil).Once()
+	mockSections.On("CountEnrollments", []uint{3, 4}).Return(map[uint]int64{3: 25}, nil).Once()
+	list, err := service.ListSections(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(25), list[0].Students)
+	assert.Equal(t, int64(0), list[1].Students)
+
+	// Case 4: Unknown course
+	mockCourses.On("GetByID", uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.ListSections(9)
+	assert.ErrorIs(t, err, services.ErrCourseNotFound)
+
+	// Case 5: Delete once attendance was taken
+	mockSections.On("Delete", uint(3)).Return(gorm.ErrForeignKeyViolated).Once()
+	assert.ErrorIs(t, service.DeleteSection(3), services.ErrSectionInUse)
+}
+
+func TestEnrollStudents(t *testing.T) {
+	mockSections := new(MockSectionRepo)
+	mockStudents := new(MockStudentRepo)
+	service := services.NewCourseService(new(MockCourseRepo), mockSections, mockStudents)
+	section := &models.Section{ID: 3}
+
+	// Case 1: Duplicates in the request collapse, already enrolled students are counted apart
+	mockSections.On("GetByID", uint(3)).Return(section, nil).Once()
+	mockStudents.On("GetByIDs", []uint{1, 2, 5}).Return([]models.Student{{Model: gorm.Model{ID: 1}}, {Model: gorm.Model{ID: 2}}, {Model: gorm.Model{ID: 5}}}, nil).Once()
+	mockSections.On("Enroll", uint(3), []uint{1, 2, 5}).Return(2, nil).Once()
+	resp, err := service.EnrollStudents(3, viewmodels.EnrollStudentsRequest{StudentIDs: []uint{5, 1, 2, 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, viewmodels.EnrollStudentsResponse{Enrolled: 2, AlreadyEnrolled: 1}, *resp)
+
+	// Case 2: Unknown students fail the request
+	mockSections.On("GetByID", uint(3)).Return(section, nil).Once()
+	mockStudents.On("GetByIDs", []uint{1, 7, 8}).Return([]models.Student{{Model: gorm.Model{ID: 1}}}, nil).Once()
+	_, err = service.EnrollStudents(3, viewmodels.EnrollStudentsRequest{StudentIDs: []uint{1, 7, 8}})
+	assert.ErrorIs(t, err, services.ErrValidation)
+	assert.Contains(t, err.Error(), "7, 8")
+
+	// Case 3: Unknown section
+	mockSections.On("GetByID", uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.EnrollStudents(9, viewmodels.EnrollStudentsRequest{StudentIDs: []uint{1}})
+	assert.ErrorIs(t, err, services.ErrSectionNotFound)
+
+	// Case 4: Unenroll a student that is not enrolled
+	mockSections.On("GetByID", uint(3)).Return(section, nil).Once()
+	mockSections.On("Unenroll", uint(3), uint(4)).Return(gorm.ErrRecordNotFound).Once()
+	assert.ErrorIs(t, service.UnenrollStudent(3, 4), services.ErrEnrollmentNotFound)
+	mockSections.AssertNotCalled(t, "Enroll", uint(3), []uint{1, 7, 8})
+}
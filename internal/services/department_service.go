@@ -0,0 +1,226 @@
+package services
+
+import (
+	"errors"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
+	"hrms_backend/internal/viewmodels"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrDepartmentNotFound is returned when a department ID does not exist.
+	ErrDepartmentNotFound = &Error{Kind: ErrNotFound, Code: "department_not_found", Message: "department not found"}
+	// ErrUnknownDepartment is returned when a student is assigned a department that does not exist.
+	ErrUnknownDepartment = &Error{
+		Kind:    ErrValidation,
+		Code:    "unknown_department",
+		Message: "department does not exist",
+		Fields:  map[string]string{"department_id": "does not exist"},
+	}
+	// ErrDepartmentNameTaken is returned when creating or renaming a department to a name already in use.
+	ErrDepartmentNameTaken = &Error{
+		Kind:    ErrConflict,
+		Code:    "department_name_taken",
+		Message: "a department with this name already exists",
+		Fields:  map[string]string{"name": "already in use"},
+	}
+	// ErrDepartmentInUse is returned when deleting a department that students (deleted ones included) still belong to.
+	ErrDepartmentInUse = &Error{Kind: ErrConflict, Code: "department_in_use", Message: "department still has students, move or merge them first"}
+)
+
+type DepartmentService interface {
+	CreateDepartment(req viewmodels.DepartmentRequest) (*viewmodels.DepartmentResponse, error)
+	ListDepartments() ([]viewmodels.DepartmentResponse, error)
+	GetDepartmentByID(id uint) (*viewmodels.DepartmentResponse, error)
+	UpdateDepartment(id uint, req viewmodels.DepartmentRequest) (*viewmodels.DepartmentResponse, error)
+	DeleteDepartment(id uint) error
+	MergeDepartment(id uint, req viewmodels.MergeDepartmentRequest, actor Actor) (*viewmodels.MergeDepartmentResponse, error)
+	GetAttendanceSummary(from, to time.Time) ([]viewmodels.DepartmentAttendanceResponse, error)
+	GetDepartmentAttendanceSummary(id uint, from, to time.Time) (*viewmodels.DepartmentAttendanceResponse, error)
+}
+
+type departmentService struct {
+	repo     repository.DepartmentRepository
+	attRepo  repository.AttendanceRepository
+	calendar SchoolCalendar // days without classes are left out of the summaries
+}
+
+func NewDepartmentService(repo repository.DepartmentRepository, attRepo repository.AttendanceRepository, calendar SchoolCalendar) DepartmentService {
+	return &departmentService{repo: repo, attRepo: attRepo, calendar: calendar}
+}
+
+func (s *departmentService) CreateDepartment(req viewmodels.DepartmentRequest) (*viewmodels.DepartmentResponse, error) {
+	department := models.Department{Name: req.Name}
+	if err := s.repo.Create(&department); err != nil {
+		return nil, translateDBError(err, nil, ErrDepartmentNameTaken)
+	}
+	resp := toDepartmentResponse(department, 0)
+	return &resp, nil
+}
+
+// ListDepartments returns every department, ordered by name, with its number of students
+func (s *departmentService) ListDepartments() ([]viewmodels.DepartmentResponse, error) {
+	departments, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.repo.CountStudents()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]viewmodels.DepartmentResponse, 0, len(departments))
+	for _, d := range departments {
+		responses = append(responses, toDepartmentResponse(d, counts[d.ID]))
+	}
+	return responses, nil
+}
+
+func (s *departmentService) GetDepartmentByID(id uint) (*viewmodels.DepartmentResponse, error) {
+	department, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrDepartmentNotFound, nil)
+	}
+	return s.withStudentCount(*department)
+}
+
+// UpdateDepartment renames a department; its students follow, as they reference it by ID
+func (s *departmentService) UpdateDepartment(id uint, req viewmodels.DepartmentRequest) (*viewmodels.DepartmentResponse, error) {
+	department, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrDepartmentNotFound, nil)
+	}
+	department.Name = req.Name
+	if err := s.repo.Update(department); err != nil {
+		return nil, translateDBError(err, ErrDepartmentNotFound, ErrDepartmentNameTaken)
+	}
+	return s.withStudentCount(*department)
+}
+
+// DeleteDepartment removes an empty department
+func (s *departmentService) DeleteDepartment(id uint) error {
+	err := s.repo.Delete(id)
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return ErrDepartmentInUse.Wrap(err)
+	}
+	return translateDBError(err, ErrDepartmentNotFound, nil)
+}
+
+// MergeDepartment moves the students of department id to req.IntoID and deletes id,
+// e.g. to fold "Computer Science" into "CS"
+func (s *departmentService) MergeDepartment(id uint, req viewmodels.MergeDepartmentRequest, actor Actor) (*viewmodels.MergeDepartmentResponse, error) {
+	if req.IntoID == id {
+		return nil, ValidationError("into_id", "must be another department")
+	}
+	into, err := s.repo.GetByID(req.IntoID)
+	if err != nil {
+		return nil, translateDBError(err, ValidationError("into_id", "does not exist"), nil)
+	}
+
+	moved, err := s.repo.Merge(id, req.IntoID, actor.auditEntry(models.AuditActionUpdate, models.AuditEntityStudent))
+	if err != nil {
+		return nil, translateDBError(err, ErrDepartmentNotFound, nil)
+	}
+
+	department, err := s.withStudentCount(*into)
+	if err != nil {
+		return nil, err
+	}
+	return &viewmodels.MergeDepartmentResponse{Department: *department, MovedStudents: moved}, nil
+}
+
+// GetAttendanceSummary aggregates the attendance of [from, to] per department, every department included
+func (s *departmentService) GetAttendanceSummary(from, to time.Time) ([]viewmodels.DepartmentAttendanceResponse, error) {
+	departments, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.attRepo.GetAttendanceBetween(truncateToDay(from), truncateToDay(to))
+	if err != nil {
+		return nil, err
+	}
+	if records, err = onSchoolDays(s.calendar, records); err != nil {
+		return nil, err
+	}
+	return summarizeByDepartment(departments, records), nil
+}
+
+// GetDepartmentAttendanceSummary aggregates the attendance of one department's students over [from, to]
+func (s *departmentService) GetDepartmentAttendanceSummary(id uint, from, to time.Time) (*viewmodels.DepartmentAttendanceResponse, error) {
+	department, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrDepartmentNotFound, nil)
+	}
+	records, _, err := s.attRepo.List(repository.AttendanceFilter{DepartmentID: id, From: truncateToDay(from), To: truncateToDay(to)})
+	if err != nil {
+		return nil, err
+	}
+	if records, err = onSchoolDays(s.calendar, records); err != nil {
+		return nil, err
+	}
+	return &summarizeByDepartment([]models.Department{*department}, records)[0], nil
+}
+
+func (s *departmentService) withStudentCount(department models.Department) (*viewmodels.DepartmentResponse, error) {
+	counts, err := s.repo.CountStudents()
+	if err != nil {
+		return nil, err
+	}
+	resp := toDepartmentResponse(department, counts[department.ID])
+	return &resp, nil
+}
+
+// summarizeByDepartment counts the school days of each status per department (see byDay), one row
+// per department in the given order.
+// Records of students outside these departments are ignored.
+func summarizeByDepartment(departments []models.Department, records []models.Attendance) []viewmodels.DepartmentAttendanceResponse {
+	rows := make([]viewmodels.DepartmentAttendanceResponse, len(departments))
+	index := make(map[uint]int, len(departments))
+	for i, d := range departments {
+		rows[i] = viewmodels.DepartmentAttendanceResponse{DepartmentID: d.ID, DepartmentName: d.Name}
+		index[d.ID] = i
+	}
+
+	seen := make(map[uint]bool) // students counted already
+	for _, rec := range byDay(records) {
+		i, ok := index[rec.Student.DepartmentID]
+		if !ok {
+			continue
+		}
+		row := &rows[i]
+		if !seen[rec.StudentID] {
+			seen[rec.StudentID] = true
+			row.Students++
+		}
+
+		row.Total++
+		switch rec.Status {
+		case "present":
+			row.Present++
+		case "absent":
+			row.Absent++
+		case "late":
+			row.Late++
+			row.LateMinutes += rec.LateMinutes
+		case "excused":
+			row.Excused++
+		}
+	}
+
+	for i := range rows {
+		rows[i].Percentage = attendancePercentage(rows[i].Present+rows[i].Late, rows[i].Total)
+	}
+	return rows
+}
+
+func toDepartmentResponse(department models.Department, students int64) viewmodels.DepartmentResponse {
+	return viewmodels.DepartmentResponse{
+		ID:        department.ID,
+		Name:      department.Name,
+		Students:  students,
+		CreatedAt: department.CreatedAt,
+	}
+}
@@ -0,0 +1,216 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// --- Mock Department Repo ---
+type MockDepartmentRepo struct {
+	mock.Mock
+}
+
+func (m *MockDepartmentRepo) Create(department *models.Department) error {
+	args := m.Called(department)
+	return args.Error(0)
+}
+
+func (m *MockDepartmentRepo) GetAll() ([]models.Department, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepo) GetByID(id uint) (*models.Department, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepo) Update(department *models.Department) error {
+	args := m.Called(department)
+	return args.Error(0)
+}
+
+func (m *MockDepartmentRepo) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockDepartmentRepo) CountStudents() (map[uint]int64, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uint]int64), args.Error(1)
+}
+
+func (m *MockDepartmentRepo) Merge(fromID, intoID uint, audit *models.AuditLog) (int, error) {
+	args := m.Called(fromID, intoID, audit)
+	return args.Int(0), args.Error(1)
+}
+
+// --- Tests ---
+
+func TestCreateDepartment(t *testing.T) {
+	mockRepo := new(MockDepartmentRepo)
+	service := services.NewDepartmentService(mockRepo, new(MockAttendanceRepo), fakeCalendar{})
+
+	// Case 1: Success
+	mockRepo.On("Create", mock.MatchedBy(func(d *models.Department) bool { return d.Name == "CS" })).Return(nil).Once()
+	resp, err := service.CreateDepartment(viewmodels.DepartmentRequest{Name: "CS"})
+	assert.NoError(t, err)
+	assert.Equal(t, "CS", resp.Name)
+
+	// Case 2: Name taken
+	mockRepo.On("Create", mock.Anything).Return(gorm.ErrDuplicatedKey).Once()
+	_, err = service.CreateDepartment(viewmodels.DepartmentRequest{Name: "cs"})
+	assert.ErrorIs(t, err, services.ErrDepartmentNameTaken)
+	assert.ErrorIs(t, err, services.ErrConflict)
+}
+
+func TestListDepartments(t *testing.T) {
+	mockRepo := new(MockDepartmentRepo)
+	service := services.NewDepartmentService(mockRepo, new(MockAttendanceRepo), fakeCalendar{})
+
+	mockRepo.On("GetAll").Return([]models.Department{{ID: 1, Name: "CS"}, {ID: 2, Name: "Math"}}, nil).Once()
+	mockRepo.On("CountStudents").Return(map[uint]int64{1: 12}, nil).Once()
+	resp, err := service.ListDepartments()
+	assert.NoError(t, err)
+	assert.Len(t, resp, 2)
+	assert.Equal(t, int64(12), resp[0].Students)
+	assert.Equal(t, int64(0), resp[1].Students)
+}
+
+func TestUpdateDepartment(t *testing.T) {
+	mockRepo := new(MockDepartmentRepo)
+	service := services.NewDepartmentService(mockRepo, new(MockAttendanceRepo), fakeCalendar{})
+
+	// Case 1: Success
+	mockRepo.On("GetByID", uint(1)).Return(&models.Department{ID: 1, Name: "Comp Sci"}, nil).Once()
+	mockRepo.On("Update", mock.MatchedBy(func(d *models.Department) bool { return d.ID == 1 && d.Name == "CS" })).Return(nil).Once()
+	mockRepo.On("CountStudents").Return(map[uint]int64{1: 3}, nil).Once()
+	resp, err := service.UpdateDepartment(1, viewmodels.DepartmentRequest{Name: "CS"})
+	assert.NoError(t, err)
+	assert.Equal(t, "CS", resp.Name)
+	assert.Equal(t, int64(3), resp.Students)
+
+	// Case 2: Not found
+	mockRepo.On("GetByID", uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.UpdateDepartment(9, viewmodels.DepartmentRequest{Name: "CS"})
+	assert.ErrorIs(t, err, services.ErrDepartmentNotFound)
+}
+
+func TestDeleteDepartment(t *testing.T) {
+	mockRepo := new(MockDepartmentRepo)
+	service := services.NewDepartmentService(mockRepo, new(MockAttendanceRepo), fakeCalendar{})
+
+	// Case 1: Success
+	mockRepo.On("Delete", uint(1)).Return(nil).Once()
+	assert.NoError(t, service.DeleteDepartment(1))
+
+	// Case 2: Still has students
+	mockRepo.On("Delete", uint(2)).Return(gorm.ErrForeignKeyViolated).Once()
+	err := service.DeleteDepartment(2)
+	assert.ErrorIs(t, err, services.ErrDepartmentInUse)
+	assert.ErrorIs(t, err, services.ErrConflict)
+
+	// Case 3: Not found
+	mockRepo.On("Delete", uint(9)).Return(gorm.ErrRecordNotFound).Once()
+	assert.ErrorIs(t, service.DeleteDepartment(9), services.ErrDepartmentNotFound)
+}
+
+func TestMergeDepartment(t *testing.T) {
+	mockRepo := new(MockDepartmentRepo)
+	service := services.NewDepartmentService(mockRepo, new(MockAttendanceRepo), fakeCalendar{})
+	actor := services.Actor{UserID: 1}
+
+	// Case 1: Success, every moved student is audited
+	mockRepo.On("GetByID", uint(1)).Return(&models.Department{ID: 1, Name: "CS"}, nil).Once()
+	mockRepo.On("Merge", uint(2), uint(1), mock.MatchedBy(func(a *models.AuditLog) bool {
+		return a.Action == models.AuditActionUpdate && a.EntityType == models.AuditEntityStudent
+	})).Return(4, nil).Once()
+	mockRepo.On("CountStudents").Return(map[uint]int64{1: 10}, nil).Once()
+	resp, err := service.MergeDepartment(2, viewmodels.MergeDepartmentRequest{IntoID: 1}, actor)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, resp.MovedStudents)
+	assert.Equal(t, int64(10), resp.Department.Students)
+
+	// Case 2: Into itself
+	_, err = service.MergeDepartment(1, viewmodels.MergeDepartmentRequest{IntoID: 1}, actor)
+	assert.ErrorIs(t, err, services.ErrValidation)
+
+	// Case 3: Unknown target
+	mockRepo.On("GetByID", uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.MergeDepartment(2, viewmodels.MergeDepartmentRequest{IntoID: 9}, actor)
+	assert.ErrorIs(t, err, services.ErrValidation)
+
+	// Case 4: Unknown source
+	mockRepo.On("GetByID", uint(1)).Return(&models.Department{ID: 1, Name: "CS"}, nil).Once()
+	mockRepo.On("Merge", uint(8), uint(1), mock.Anything).Return(0, gorm.ErrRecordNotFound).Once()
+	_, err = service.MergeDepartment(8, viewmodels.MergeDepartmentRequest{IntoID: 1}, actor)
+	assert.ErrorIs(t, err, services.ErrDepartmentNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDepartmentAttendanceSummary(t *testing.T) {
+	mockRepo := new(MockDepartmentRepo)
+	mockAttRepo := new(MockAttendanceRepo)
+	service := services.NewDepartmentService(mockRepo, mockAttRepo, fakeCalendar{})
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	cs := models.Department{ID: 1, Name: "CS"}
+	math := models.Department{ID: 2, Name: "Math"}
+	alice := models.Student{Model: gorm.Model{ID: 1}, DepartmentID: 1}
+	bob := models.Student{Model: gorm.Model{ID: 2}, DepartmentID: 1}
+	records := []models.Attendance{
+		{StudentID: 1, Student: alice, Date: from, Status: "present"},
+		{StudentID: 1, Student: alice, Date: from.AddDate(0, 0, 1), Status: "late"},
+		{StudentID: 2, Student: bob, Date: from, Status: "absent"},
+		{StudentID: 2, Student: bob, Date: from.AddDate(0, 0, 1), Status: "excused"},
+		{StudentID: 3, Date: from, Status: "present"}, // deleted student, not loaded
+	}
+
+	// Case 1: Every department, empty ones included
+	mockRepo.On("GetAll").Return([]models.Department{cs, math}, nil).Once()
+	mockAttRepo.On("GetAttendanceBetween", from, to).Return(records, nil).Once()
+	rows, err := service.GetAttendanceSummary(from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, []viewmodels.DepartmentAttendanceResponse{
+		{DepartmentID: 1, DepartmentName: "CS", Students: 2, Present: 1, Late: 1, Absent: 1, Excused: 1, Total: 4, Percentage: 50},
+		{DepartmentID: 2, DepartmentName: "Math"},
+	}, rows)
+
+	// Case 2: One department
+	mockRepo.On("GetByID", uint(1)).Return(&cs, nil).Once()
+	mockAttRepo.On("List", repository.AttendanceFilter{DepartmentID: 1, From: from, To: to}).Return(records[:2], int64(2), nil).Once()
+	row, err := service.GetDepartmentAttendanceSummary(1, from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, row.Students)
+	assert.Equal(t, float64(100), row.Percentage)
+
+	// Case 3: Unknown department
+	mockRepo.On("GetByID", uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.GetDepartmentAttendanceSummary(9, from, to)
+	assert.ErrorIs(t, err, services.ErrDepartmentNotFound)
+
+	// Case 4: DB error
+	mockRepo.On("GetAll").Return(nil, errors.New("db error")).Once()
+	_, err = service.GetAttendanceSummary(from, to)
+	assert.Error(t, err)
+}
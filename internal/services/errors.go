@@ -0,0 +1,96 @@
+package services
+
+import (
+	"errors"
+	"hrms_backend/internal/repository"
+	"maps"
+
+	"gorm.io/gorm"
+)
+
+// Kinds of domain errors. Every *Error matches exactly one of them with errors.Is,
+// which is what the HTTP layer uses to pick the status code.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// Error is a domain error: a Kind, a stable machine readable Code, a message safe to show
+// to clients and, for validation errors, the offending fields.
+// Two *Error values with the same Code match each other with errors.Is, so the package level
+// values below can be used as sentinels even when a copy wrapping the cause is returned.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Fields  map[string]string // field name -> problem
+	Err     error             // underlying cause, e.g. gorm.ErrRecordNotFound
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	if t, ok := target.(*Error); ok {
+		return t.Code == e.Code
+	}
+	return target == e.Kind
+}
+
+// Wrap returns a copy of e caused by err
+func (e *Error) Wrap(err error) *Error {
+	wrapped := *e
+	wrapped.Fields = maps.Clone(e.Fields)
+	wrapped.Err = err
+	return &wrapped
+}
+
+// ValidationError reports one invalid input field
+func ValidationError(field, message string) *Error {
+	return &Error{
+		Kind:    ErrValidation,
+		Code:    "validation_failed",
+		Message: field + ": " + message,
+		Fields:  map[string]string{field: message},
+	}
+}
+
+// ErrStudentNotFound is returned when a student ID does not exist.
+var ErrStudentNotFound = &Error{Kind: ErrNotFound, Code: "student_not_found", Message: "student not found"}
+
+// ErrDuplicate is returned when a unique column (e.g. an email) is already taken.
+var ErrDuplicate = &Error{Kind: ErrConflict, Code: "duplicate", Message: "a record with the same unique value already exists"}
+
+// ErrInvalidCursor is returned when a pagination cursor is malformed or was issued for another sort.
+var ErrInvalidCursor = &Error{
+	Kind:    ErrValidation,
+	Code:    "invalid_cursor",
+	Message: "cursor is invalid or was issued for a different sort",
+	Fields:  map[string]string{"cursor": "invalid or issued for a different sort"},
+}
+
+// translateDBError turns the database errors callers can act on into domain errors:
+// a missing row becomes notFound, a unique key violation becomes duplicate and a bad
+// pagination cursor becomes ErrInvalidCursor.
+// Other errors are returned unchanged and end up as internal errors.
+func translateDBError(err error, notFound, duplicate *Error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound) && notFound != nil:
+		return notFound.Wrap(err)
+	case errors.Is(err, gorm.ErrDuplicatedKey) && duplicate != nil:
+		return duplicate.Wrap(err)
+	case errors.Is(err, repository.ErrInvalidCursor):
+		return ErrInvalidCursor.Wrap(err)
+	}
+	return err
+}
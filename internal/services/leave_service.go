@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
+	"hrms_backend/internal/viewmodels"
+	"time"
+)
+
+// maxLeaveDays bounds the range of a single leave request
+const maxLeaveDays = 366
+
+var (
+	// ErrLeaveNotFound is returned when a leave request ID does not exist, or belongs to another student.
+	ErrLeaveNotFound = &Error{Kind: ErrNotFound, Code: "leave_not_found", Message: "leave request not found"}
+	// ErrLeaveNotPending is returned when approving, rejecting or cancelling a request that was already decided or cancelled.
+	ErrLeaveNotPending = &Error{Kind: ErrConflict, Code: "leave_not_pending", Message: "leave request is no longer pending"}
+	// ErrLeaveOverlap is returned when the student already has a pending or approved request for some of the days.
+	ErrLeaveOverlap = &Error{
+		Kind:    ErrConflict,
+		Code:    "leave_overlap",
+		Message: "a pending or approved leave request already covers some of these days",
+		Fields:  map[string]string{"start_date": "overlaps another leave request"},
+	}
+)
+
+type LeaveService interface {
+	SubmitLeave(studentID uint, req viewmodels.LeaveRequestRequest, actor Actor) (*viewmodels.LeaveResponse, error)
+	ListLeave(query viewmodels.LeaveQuery) ([]viewmodels.LeaveResponse, error)
+	GetLeave(id uint) (*viewmodels.LeaveResponse, error)
+	ApproveLeave(id uint, req viewmodels.ReviewLeaveRequest, actor Actor) (*viewmodels.LeaveResponse, error)
+	RejectLeave(id uint, req viewmodels.ReviewLeaveRequest, actor Actor) (*viewmodels.LeaveResponse, error)
+	CancelLeave(id, studentID uint) (*viewmodels.LeaveResponse, error)
+}
+
+type leaveService struct {
+	repo     repository.LeaveRepository
+	calendar SchoolCalendar // only school days get excused
+	tx       repository.TxManager
+}
+
+func NewLeaveService(repo repository.LeaveRepository, calendar SchoolCalendar, tx repository.TxManager) LeaveService {
+	return &leaveService{repo: repo, calendar: calendar, tx: tx}
+}
+
+// SubmitLeave files a pending request for the student, by the student or by staff on their behalf
+func (s *leaveService) SubmitLeave(studentID uint, req viewmodels.LeaveRequestRequest, actor Actor) (*viewmodels.LeaveResponse, error) {
+	from, to := truncateToDay(req.StartDate), truncateToDay(req.EndDate)
+	if to.Before(from) {
+		return nil, ValidationError("end_date", "must not be before start_date")
+	}
+	if to.Sub(from) >= maxLeaveDays*24*time.Hour {
+		return nil, ValidationError("end_date", "must be less than a year after start_date")
+	}
+
+	// The student and their overlapping requests stay locked until the new one is stored, so two
+	// requests for the same days cannot both pass the overlap check
+	var leave models.LeaveRequest
+	err := s.tx.WithTx(context.Background(), func(repos repository.Repos) error {
+		student, err := repos.Students.GetByID(studentID)
+		if err != nil {
+			return translateDBError(err, ErrStudentNotFound, nil)
+		}
+		overlap, err := repos.Leaves.HasOverlap(studentID, from, to)
+		if err != nil {
+			return err
+		}
+		if overlap {
+			return ErrLeaveOverlap
+		}
+
+		leave = models.LeaveRequest{
+			StudentID: student.ID,
+			Student:   *student,
+			StartDate: from,
+			EndDate:   to,
+			Reason:    req.Reason,
+			Status:    models.LeavePending,
+		}
+		if actor.UserID != 0 {
+			userID := actor.UserID
+			leave.RequestedByID = &userID
+		}
+		return repos.Leaves.Create(&leave)
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := toLeaveResponse(leave)
+	return &resp, nil
+}
+
+// ListLeave returns the matching requests, newest first
+func (s *leaveService) ListLeave(query viewmodels.LeaveQuery) ([]viewmodels.LeaveResponse, error) {
+	leaves, err := s.repo.List(repository.LeaveFilter{StudentID: query.StudentID, Status: query.Status})
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]viewmodels.LeaveResponse, 0, len(leaves))
+	for _, l := range leaves {
+		responses = append(responses, toLeaveResponse(l))
+	}
+	return responses, nil
+}
+
+func (s *leaveService) GetLeave(id uint) (*viewmodels.LeaveResponse, error) {
+	leave, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrLeaveNotFound, nil)
+	}
+	resp := toLeaveResponse(*leave)
+	return &resp, nil
+}
+
+// ApproveLeave accepts a pending request and excuses the student on its school days: records already
+// marked on those days become "excused" and a whole day "excused" record is added where there was none.
+// Days without classes are left alone.
+func (s *leaveService) ApproveLeave(id uint, req viewmodels.ReviewLeaveRequest, actor Actor) (*viewmodels.LeaveResponse, error) {
+	leave, err := s.pendingLeave(id)
+	if err != nil {
+		return nil, err
+	}
+	off, err := s.calendar.NonSchoolDays(leave.StartDate, leave.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	var days []time.Time
+	for day := truncateToDay(leave.StartDate); !day.After(truncateToDay(leave.EndDate)); day = day.AddDate(0, 0, 1) {
+		if _, closed := off[day]; !closed {
+			days = append(days, day)
+		}
+	}
+
+	review(leave, models.LeaveApproved, req.Note, actor)
+	reason := fmt.Sprintf("leave request #%d approved", leave.ID)
+	excused, err := s.repo.Approve(leave, days, reason, actor.auditEntry(models.AuditActionUpdate, models.AuditEntityAttendance))
+	if err != nil {
+		return nil, translateLeaveError(err)
+	}
+	resp := toLeaveResponse(*leave)
+	resp.ExcusedDays = excused
+	return &resp, nil
+}
+
+// RejectLeave turns down a pending request; attendance is not touched
+func (s *leaveService) RejectLeave(id uint, req viewmodels.ReviewLeaveRequest, actor Actor) (*viewmodels.LeaveResponse, error) {
+	leave, err := s.pendingLeave(id)
+	if err != nil {
+		return nil, err
+	}
+	review(leave, models.LeaveRejected, req.Note, actor)
+	if err := s.repo.Decide(leave); err != nil {
+		return nil, translateLeaveError(err)
+	}
+	resp := toLeaveResponse(*leave)
+	return &resp, nil
+}
+
+// CancelLeave withdraws a pending request. A studentID other than 0 restricts it to that
+// student's own requests; the others are reported as not found.
+func (s *leaveService) CancelLeave(id, studentID uint) (*viewmodels.LeaveResponse, error) {
+	leave, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrLeaveNotFound, nil)
+	}
+	if studentID != 0 && leave.StudentID != studentID {
+		return nil, ErrLeaveNotFound
+	}
+	if leave.Status != models.LeavePending {
+		return nil, ErrLeaveNotPending
+	}
+	leave.Status = models.LeaveCancelled
+	if err := s.repo.Decide(leave); err != nil {
+		return nil, translateLeaveError(err)
+	}
+	resp := toLeaveResponse(*leave)
+	return &resp, nil
+}
+
+func (s *leaveService) pendingLeave(id uint) (*models.LeaveRequest, error) {
+	leave, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrLeaveNotFound, nil)
+	}
+	if leave.Status != models.LeavePending {
+		return nil, ErrLeaveNotPending
+	}
+	return leave, nil
+}
+
+// review records the decision on leave
+func review(leave *models.LeaveRequest, status, note string, actor Actor) {
+	now := time.Now()
+	leave.Status = status
+	leave.ReviewNote = note
+	leave.ReviewedAt = &now
+	if actor.UserID != 0 {
+		userID := actor.UserID
+		leave.ReviewedByID = &userID
+	}
+}
+
+// translateLeaveError maps a request decided concurrently to ErrLeaveNotPending
+func translateLeaveError(err error) error {
+	if errors.Is(err, repository.ErrLeaveNotPending) {
+		return ErrLeaveNotPending.Wrap(err)
+	}
+	return translateDBError(err, ErrLeaveNotFound, nil)
+}
+
+func toLeaveResponse(leave models.LeaveRequest) viewmodels.LeaveResponse {
+	return viewmodels.LeaveResponse{
+		ID:            leave.ID,
+		StudentID:     leave.StudentID,
+		StudentName:   leave.Student.Name,
+		StartDate:     leave.StartDate,
+		EndDate:       leave.EndDate,
+		Reason:        leave.Reason,
+		Status:        leave.Status,
+		RequestedByID: leave.RequestedByID,
+		ReviewedByID:  leave.ReviewedByID,
+		ReviewedAt:    leave.ReviewedAt,
+		ReviewNote:    leave.ReviewNote,
+		CreatedAt:     leave.CreatedAt,
+	}
+}
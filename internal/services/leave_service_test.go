@@ -0,0 +1,169 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// --- Mock Leave Repo ---
+type MockLeaveRepo struct {
+	mock.Mock
+}
+
+func (m *MockLeaveRepo) Create(leave *models.LeaveRequest) error {
+	args := m.Called(leave)
+	return args.Error(0)
+}
+
+func (m *MockLeaveRepo) GetByID(id uint) (*models.LeaveRequest, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LeaveRequest), args.Error(1)
+}
+
+func (m *MockLeaveRepo) List(filter repository.LeaveFilter) ([]models.LeaveRequest, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LeaveRequest), args.Error(1)
+}
+
+func (m *MockLeaveRepo) HasOverlap(studentID uint, from, to time.Time) (bool, error) {
+	args := m.Called(studentID, from, to)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLeaveRepo) Decide(leave *models.LeaveRequest) error {
+	args := m.Called(leave)
+	return args.Error(0)
+}
+
+func (m *MockLeaveRepo) Approve(leave *models.LeaveRequest, days []time.Time, reason string, audit *models.AuditLog) (int, error) {
+	args := m.Called(leave, days, reason, audit)
+	return args.Int(0), args.Error(1)
+}
+
+// --- Tests ---
+
+func TestSubmitLeave(t *testing.T) {
+	mockRepo := new(MockLeaveRepo)
+	mockStudentRepo := new(MockStudentRepo)
+	service := services.NewLeaveService(mockRepo, fakeCalendar{}, fakeTx{Students: mockStudentRepo, Leaves: mockRepo})
+	start := time.Date(2025, 12, 8, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 12, 10, 0, 0, 0, 0, time.UTC)
+	req := viewmodels.LeaveRequestRequest{StartDate: start.Add(9 * time.Hour), EndDate: end, Reason: "Flu"}
+
+	// Case 1: Pending, requested by the caller
+	mockStudentRepo.On("GetByID", uint(7)).Return(&models.Student{Model: gorm.Model{ID: 7}, Name: "Alice"}, nil)
+	mockRepo.On("HasOverlap", uint(7), start, end).Return(false, nil).Once()
+	mockRepo.On("Create", mock.MatchedBy(func(l *models.LeaveRequest) bool {
+		return l.StudentID == 7 && l.StartDate.Equal(start) && l.Status == models.LeavePending && *l.RequestedByID == 3
+	})).Return(nil).Once()
+	resp, err := service.SubmitLeave(7, req, services.Actor{UserID: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice", resp.StudentName)
+
+	// Case 2: Overlaps another request
+	mockRepo.On("HasOverlap", uint(7), start, end).Return(true, nil).Once()
+	_, err = service.SubmitLeave(7, req, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrLeaveOverlap)
+
+	// Case 3: Ends before it starts
+	_, err = service.SubmitLeave(7, viewmodels.LeaveRequestRequest{StartDate: end, EndDate: start, Reason: "Flu"}, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrValidation)
+
+	// Case 4: Unknown student
+	mockStudentRepo.On("GetByID", uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.SubmitLeave(9, req, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrStudentNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestApproveLeave(t *testing.T) {
+	mockRepo := new(MockLeaveRepo)
+	// Friday 2025-12-05 to Tuesday 2025-12-09, over a weekend
+	day := func(d int) time.Time { return time.Date(2025, 12, d, 0, 0, 0, 0, time.UTC) }
+	calendar := fakeCalendar{day(6): "weekend", day(7): "weekend"}
+	service := services.NewLeaveService(mockRepo, calendar, fakeTx{Leaves: mockRepo})
+	pending := func() *models.LeaveRequest {
+		return &models.LeaveRequest{ID: 4, StudentID: 7, StartDate: day(5), EndDate: day(9), Status: models.LeavePending}
+	}
+
+	// Case 1: Only school days are excused
+	mockRepo.On("GetByID", uint(4)).Return(pending(), nil).Once()
+	mockRepo.On("Approve", mock.MatchedBy(func(l *models.LeaveRequest) bool {
+		return l.Status == models.LeaveApproved && *l.ReviewedByID == 2 && l.ReviewNote == "get well" && l.ReviewedAt != nil
+	}), []time.Time{day(5), day(8), day(9)}, "leave request #4 approved", mock.Anything).Return(3, nil).Once()
+	resp, err := service.ApproveLeave(4, viewmodels.ReviewLeaveRequest{Note: "get well"}, services.Actor{UserID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, models.LeaveApproved, resp.Status)
+	assert.Equal(t, 3, resp.ExcusedDays)
+
+	// Case 2: Already decided
+	decided := pending()
+	decided.Status = models.LeaveRejected
+	mockRepo.On("GetByID", uint(4)).Return(decided, nil).Once()
+	_, err = service.ApproveLeave(4, viewmodels.ReviewLeaveRequest{}, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrLeaveNotPending)
+
+	// Case 3: Decided by someone else in the meantime
+	mockRepo.On("GetByID", uint(4)).Return(pending(), nil).Once()
+	mockRepo.On("Approve", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(0, repository.ErrLeaveNotPending).Once()
+	_, err = service.ApproveLeave(4, viewmodels.ReviewLeaveRequest{}, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrLeaveNotPending)
+	assert.ErrorIs(t, err, services.ErrConflict)
+
+	// Case 4: Unknown request
+	mockRepo.On("GetByID", uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.ApproveLeave(9, viewmodels.ReviewLeaveRequest{}, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrLeaveNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRejectAndCancelLeave(t *testing.T) {
+	mockRepo := new(MockLeaveRepo)
+	service := services.NewLeaveService(mockRepo, fakeCalendar{}, fakeTx{Leaves: mockRepo})
+	pending := func() *models.LeaveRequest {
+		return &models.LeaveRequest{ID: 4, StudentID: 7, Status: models.LeavePending}
+	}
+
+	// Case 1: Reject leaves attendance alone
+	mockRepo.On("GetByID", uint(4)).Return(pending(), nil).Once()
+	mockRepo.On("Decide", mock.MatchedBy(func(l *models.LeaveRequest) bool { return l.Status == models.LeaveRejected })).Return(nil).Once()
+	resp, err := service.RejectLeave(4, viewmodels.ReviewLeaveRequest{Note: "no note from parents"}, services.Actor{UserID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "no note from parents", resp.ReviewNote)
+	mockRepo.AssertNotCalled(t, "Approve", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	// Case 2: The owner cancels
+	mockRepo.On("GetByID", uint(4)).Return(pending(), nil).Once()
+	mockRepo.On("Decide", mock.MatchedBy(func(l *models.LeaveRequest) bool { return l.Status == models.LeaveCancelled })).Return(nil).Once()
+	resp, err = service.CancelLeave(4, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, models.LeaveCancelled, resp.Status)
+
+	// Case 3: Another student's request does not exist for them
+	mockRepo.On("GetByID", uint(4)).Return(pending(), nil).Once()
+	_, err = service.CancelLeave(4, 8)
+	assert.ErrorIs(t, err, services.ErrLeaveNotFound)
+
+	// Case 4: Approved requests cannot be cancelled
+	approved := pending()
+	approved.Status = models.LeaveApproved
+	mockRepo.On("GetByID", uint(4)).Return(approved, nil).Once()
+	_, err = service.CancelLeave(4, 0)
+	assert.ErrorIs(t, err, services.ErrLeaveNotPending)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
+	"log"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// events handed to the publisher per outbox claim
+const outboxBatchSize = 100
+
+// OutboxDispatcher publishes the events the repositories write to the outbox together with every
+// student and attendance change, in the order they were written. Events survive restarts: they stay
+// in the outbox until published, and a crash before they are marked only means they are published
+// again, under the same ID.
+type OutboxDispatcher struct {
+	repo        repository.OutboxRepository
+	departments repository.DepartmentRepository // department names for student events
+	publisher   EventPublisher
+}
+
+func NewOutboxDispatcher(repo repository.OutboxRepository, departments repository.DepartmentRepository, publisher EventPublisher) *OutboxDispatcher {
+	return &OutboxDispatcher{repo: repo, departments: departments, publisher: publisher}
+}
+
+// Run drains the outbox right away and then every interval, until ctx is done. Meant to run in its own goroutine.
+func (d *OutboxDispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.Dispatch(); err != nil {
+			log.Printf("⚠️ Outbox: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch publishes every pending event, oldest first, and returns how many it published.
+// It stops at the first event that cannot be published; that one is retried on the next call,
+// before anything written after it.
+func (d *OutboxDispatcher) Dispatch() (int, error) {
+	total := 0
+	for {
+		n, err := d.repo.Drain(outboxBatchSize, d.publish)
+		total += n
+		if err != nil || n < outboxBatchSize {
+			return total, err
+		}
+	}
+}
+
+// publish turns an outbox row back into the response the API gives for the entity and publishes it
+func (d *OutboxDispatcher) publish(event models.OutboxEvent) error {
+	var data any
+	switch event.EntityType {
+	case models.AuditEntityStudent:
+		if event.Event == models.EventStudentPurged {
+			data = map[string]uint{"id": event.EntityID}
+			break
+		}
+		var st models.Student
+		if err := json.Unmarshal([]byte(event.Payload), &st); err != nil {
+			return err
+		}
+		if event.Event == models.EventStudentDeleted {
+			st.DeletedAt = gorm.DeletedAt{Time: event.CreatedAt, Valid: true}
+		}
+		if st.Department.Name == "" && st.DepartmentID != 0 {
+			department, err := d.departments.GetByID(st.DepartmentID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if department != nil {
+				st.Department = *department
+			}
+		}
+		data = toStudentResponse(st)
+	case models.AuditEntityAttendance:
+		var rec models.Attendance
+		if err := json.Unmarshal([]byte(event.Payload), &rec); err != nil {
+			return err
+		}
+		data = toAttendanceResponse(rec)
+	default:
+		data = json.RawMessage(event.Payload)
+	}
+
+	return d.publisher.Publish(WebhookEvent{
+		ID:        strconv.FormatUint(uint64(event.ID), 10),
+		Event:     event.Event,
+		CreatedAt: event.CreatedAt,
+		Data:      data,
+	})
+}
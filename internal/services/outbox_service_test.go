@@ -0,0 +1,95 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeOutbox drains its pending events the way the repository does: in order, stopping at the
+// first failure, which stays pending
+type fakeOutbox struct {
+	pending []models.OutboxEvent
+}
+
+func (o *fakeOutbox) Drain(limit int, publish func(event models.OutboxEvent) error) (int, error) {
+	published := 0
+	for len(o.pending) > 0 && published < limit {
+		if err := publish(o.pending[0]); err != nil {
+			return published, err
+		}
+		o.pending = o.pending[1:]
+		published++
+	}
+	return published, nil
+}
+
+// recordingPublisher keeps the events it was asked to publish, failing on the event names in fail
+type recordingPublisher struct {
+	events []services.WebhookEvent
+	fail   map[string]bool
+}
+
+func (p *recordingPublisher) Publish(event services.WebhookEvent) error {
+	if p.fail[event.Event] {
+		return errors.New("db down")
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+// --- Tests ---
+
+func TestOutboxDispatch(t *testing.T) {
+	mockDeptRepo := new(MockDepartmentRepo)
+	mockDeptRepo.On("GetByID", uint(2)).Return(&models.Department{ID: 2, Name: "IT"}, nil)
+	written := time.Date(2025, 12, 12, 9, 30, 0, 0, time.UTC)
+	outbox := &fakeOutbox{pending: []models.OutboxEvent{
+		{ID: 1, Event: models.EventStudentCreated, EntityType: models.AuditEntityStudent, EntityID: 7, CreatedAt: written,
+			Payload: `{"ID":7,"Name":"Alice","Email":"alice@test.com","DepartmentID":2}`},
+		{ID: 2, Event: models.EventAttendanceMarked, EntityType: models.AuditEntityAttendance, EntityID: 5, CreatedAt: written,
+			Payload: `{"ID":5,"StudentID":7,"Date":"2025-12-12T00:00:00Z","Status":"late","LateMinutes":12}`},
+		{ID: 3, Event: models.EventStudentDeleted, EntityType: models.AuditEntityStudent, EntityID: 7, CreatedAt: written,
+			Payload: `{"ID":7,"Name":"Alice","Email":"alice@test.com","DepartmentID":2}`},
+		{ID: 4, Event: models.EventStudentPurged, EntityType: models.AuditEntityStudent, EntityID: 7, CreatedAt: written,
+			Payload: `{"ID":7}`},
+	}}
+	publisher := &recordingPublisher{fail: map[string]bool{models.EventStudentDeleted: true}}
+	dispatcher := services.NewOutboxDispatcher(outbox, mockDeptRepo, publisher)
+
+	// Case 1: Published in order, as the API returns the entities, until one fails
+	n, err := dispatcher.Dispatch()
+	assert.ErrorContains(t, err, "db down")
+	assert.Equal(t, 2, n)
+	if assert.Len(t, publisher.events, 2) {
+		assert.Equal(t, services.WebhookEvent{ID: "1", Event: models.EventStudentCreated, CreatedAt: written,
+			Data: viewmodels.StudentResponse{ID: 7, Name: "Alice", Email: "alice@test.com", DepartmentID: 2, Department: "IT"}}, publisher.events[0])
+		attendance := publisher.events[1].Data.(viewmodels.AttendanceResponse)
+		assert.Equal(t, "2", publisher.events[1].ID)
+		assert.Equal(t, "late", attendance.Status)
+		assert.Equal(t, 12, attendance.LateMinutes)
+	}
+
+	// Case 2: The failed event goes out first on the next run, before the ones after it
+	publisher.fail = nil
+	n, err = dispatcher.Dispatch()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	if assert.Len(t, publisher.events, 4) {
+		deleted := publisher.events[2].Data.(viewmodels.StudentResponse)
+		assert.Equal(t, models.EventStudentDeleted, publisher.events[2].Event)
+		assert.Equal(t, written, *deleted.DeletedAt)
+		assert.Equal(t, map[string]uint{"id": 7}, publisher.events[3].Data)
+	}
+
+	// Case 3: Nothing left
+	n, err = dispatcher.Dispatch()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
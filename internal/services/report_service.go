@@ -0,0 +1,270 @@
+package services
+
+import (
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
+	"hrms_backend/internal/viewmodels"
+	"math"
+	"sort"
+	"time"
+)
+
+// ErrReportNotFound is returned when a report ID does not exist.
+var ErrReportNotFound = &Error{Kind: ErrNotFound, Code: "report_not_found", Message: "report not found"}
+
+type ReportService interface {
+	GenerateWeeklyReport() (*viewmodels.ReportResponse, error)
+	GenerateMonthlyReport() (*viewmodels.ReportResponse, error)
+	GetAttendanceSummary(from, to time.Time) ([]viewmodels.ReportRowResponse, error)
+	GetAllReports(page, limit int) ([]viewmodels.ReportResponse, error)
+	GetReportByID(id uint) (*viewmodels.ReportResponse, error)
+}
+
+type reportService struct {
+	reportRepo repository.ReportRepository
+	attRepo    repository.AttendanceRepository
+	calendar   SchoolCalendar // days without classes are left out of the totals
+}
+
+// Constructor
+func NewReportService(reportRepo repository.ReportRepository, attRepo repository.AttendanceRepository, calendar SchoolCalendar) ReportService {
+	return &reportService{reportRepo: reportRepo, attRepo: attRepo, calendar: calendar}
+}
+
+// GenerateWeeklyReport aggregates the last seven full days (today excluded) and stores the result.
+// Weekends, holidays and closures in that window are skipped, see expectedAttendanceBetween.
+func (s *reportService) GenerateWeeklyReport() (*viewmodels.ReportResponse, error) {
+	today := truncateToDay(time.Now())
+	return s.generate("weekly", today.AddDate(0, 0, -7), today.AddDate(0, 0, -1))
+}
+
+// GenerateMonthlyReport aggregates the previous calendar month and stores the result
+func (s *reportService) GenerateMonthlyReport() (*viewmodels.ReportResponse, error) {
+	today := truncateToDay(time.Now())
+	firstOfMonth := today.AddDate(0, 0, 1-today.Day())
+	return s.generate("monthly", firstOfMonth.AddDate(0, -1, 0), firstOfMonth.AddDate(0, 0, -1))
+}
+
+// generate builds one row per student seen in [from, to] and persists the report
+func (s *reportService) generate(reportType string, from, to time.Time) (*viewmodels.ReportResponse, error) {
+	records, err := expectedAttendanceBetween(s.attRepo, s.calendar, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := models.Report{
+		Type:        reportType,
+		PeriodStart: from,
+		PeriodEnd:   to,
+		Rows:        summarizeAttendance(records),
+	}
+	if err := s.reportRepo.Create(&report); err != nil {
+		return nil, err
+	}
+
+	resp := toReportResponse(report)
+	return &resp, nil
+}
+
+// GetAttendanceSummary aggregates [from, to] per student without storing a report
+func (s *reportService) GetAttendanceSummary(from, to time.Time) ([]viewmodels.ReportRowResponse, error) {
+	records, err := expectedAttendanceBetween(s.attRepo, s.calendar, truncateToDay(from), truncateToDay(to))
+	if err != nil {
+		return nil, err
+	}
+	return toReportResponse(models.Report{Rows: summarizeAttendance(records)}).Rows, nil
+}
+
+// expectedAttendanceBetween returns the records of [from, to] plus an absence for every class
+// session a student was expected at but nobody marked. Only days before today are checked for
+// unmarked sessions: today's may still be marked. Days without classes are dropped, so they
+// count neither as attended nor as missed.
+func expectedAttendanceBetween(attRepo repository.AttendanceRepository, calendar SchoolCalendar, from, to time.Time) ([]models.Attendance, error) {
+	records, err := attRepo.GetAttendanceBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if yesterday := truncateToDay(time.Now()).AddDate(0, 0, -1); to.After(yesterday) {
+		to = yesterday
+	}
+	if !to.Before(from) {
+		unmarked, err := attRepo.GetUnmarkedSessions(from, to)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, unmarked...)
+	}
+	return onSchoolDays(calendar, records)
+}
+
+func (s *reportService) GetAllReports(page, limit int) ([]viewmodels.ReportResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	reports, err := s.reportRepo.GetAll(limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]viewmodels.ReportResponse, 0, len(reports))
+	for _, r := range reports {
+		responses = append(responses, toReportResponse(r))
+	}
+	return responses, nil
+}
+
+func (s *reportService) GetReportByID(id uint) (*viewmodels.ReportResponse, error) {
+	report, err := s.reportRepo.GetByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrReportNotFound, nil)
+	}
+
+	resp := toReportResponse(*report)
+	return &resp, nil
+}
+
+// summarizeAttendance counts the school days of each status per student (see byDay), ordered by student ID
+func summarizeAttendance(records []models.Attendance) []models.ReportRow {
+	byStudent := make(map[uint]*models.ReportRow)
+	for _, rec := range byDay(records) {
+		row, exists := byStudent[rec.StudentID]
+		if !exists {
+			row = &models.ReportRow{
+				StudentID:   rec.StudentID,
+				StudentName: rec.Student.Name,
+				Department:  rec.Student.Department.Name,
+			}
+			byStudent[rec.StudentID] = row
+		}
+
+		row.Total++
+		switch rec.Status {
+		case "present":
+			row.Present++
+		case "absent":
+			row.Absent++
+		case "late":
+			row.Late++
+			row.LateMinutes += rec.LateMinutes
+		case "excused":
+			row.Excused++
+		}
+	}
+
+	rows := make([]models.ReportRow, 0, len(byStudent))
+	for _, row := range byStudent {
+		row.Percentage = attendancePercentage(row.Present+row.Late, row.Total)
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].StudentID < rows[j].StudentID })
+	return rows
+}
+
+// byDay collapses the records of each student and day into one, so a day counts once however many
+// classes it had. A whole day record decides the day. Otherwise the day is present if any class was,
+// late if any was (with the minutes of every late class), excused if every class was, else absent.
+// The result is ordered by student and day; its records have no section.
+func byDay(records []models.Attendance) []models.Attendance {
+	type key struct {
+		studentID uint
+		day       time.Time
+	}
+	type dayRecords struct {
+		wholeDay *models.Attendance
+		classes  []models.Attendance
+	}
+	days := make(map[key]*dayRecords)
+	keys := make([]key, 0, len(records))
+	for i, rec := range records {
+		k := key{rec.StudentID, truncateToDay(rec.Date)}
+		d, exists := days[k]
+		if !exists {
+			d = &dayRecords{}
+			days[k] = d
+			keys = append(keys, k)
+		}
+		if rec.SectionID == nil {
+			if d.wholeDay == nil {
+				d.wholeDay = &records[i]
+			}
+		} else {
+			d.classes = append(d.classes, rec)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].studentID != keys[j].studentID {
+			return keys[i].studentID < keys[j].studentID
+		}
+		return keys[i].day.Before(keys[j].day)
+	})
+
+	collapsed := make([]models.Attendance, 0, len(keys))
+	for _, k := range keys {
+		d := days[k]
+		if d.wholeDay != nil {
+			rec := *d.wholeDay
+			rec.Date = k.day
+			collapsed = append(collapsed, rec)
+			continue
+		}
+		rec := models.Attendance{StudentID: k.studentID, Student: d.classes[0].Student, Date: k.day, Status: "absent"}
+		excused := true
+		for _, c := range d.classes {
+			switch c.Status {
+			case "present":
+				rec.Status = "present"
+			case "late":
+				if rec.Status != "present" {
+					rec.Status = "late"
+				}
+				rec.LateMinutes += c.LateMinutes
+			}
+			excused = excused && c.Status == "excused"
+		}
+		if excused {
+			rec.Status = "excused"
+		}
+		if rec.Status != "late" {
+			rec.LateMinutes = 0
+		}
+		collapsed = append(collapsed, rec)
+	}
+	return collapsed
+}
+
+// attendancePercentage rounds attended/total to two decimals; 0 when nothing was marked
+func attendancePercentage(attended, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(attended)/float64(total)*10000) / 100
+}
+
+func toReportResponse(report models.Report) viewmodels.ReportResponse {
+	resp := viewmodels.ReportResponse{
+		ID:          report.ID,
+		Type:        report.Type,
+		PeriodStart: report.PeriodStart,
+		PeriodEnd:   report.PeriodEnd,
+		CreatedAt:   report.CreatedAt,
+	}
+	for _, row := range report.Rows {
+		resp.Rows = append(resp.Rows, viewmodels.ReportRowResponse{
+			StudentID:   row.StudentID,
+			StudentName: row.StudentName,
+			Department:  row.Department,
+			Present:     row.Present,
+			Absent:      row.Absent,
+			Late:        row.Late,
+			Excused:     row.Excused,
+			LateMinutes: row.LateMinutes,
+			Total:       row.Total,
+			Percentage:  row.Percentage,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,215 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// --- Mock Report Repo ---
+type MockReportRepo struct {
+	mock.Mock
+}
+
+func (m *MockReportRepo) Create(report *models.Report) error {
+	args := m.Called(report)
+	return args.Error(0)
+}
+
+func (m *MockReportRepo) GetAll(limit, offset int) ([]models.Report, error) {
+	args := m.Called(limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Report), args.Error(1)
+}
+
+func (m *MockReportRepo) GetByID(id uint) (*models.Report, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Report), args.Error(1)
+}
+
+// --- Tests ---
+
+func TestGenerateWeeklyReport(t *testing.T) {
+	mockReportRepo := new(MockReportRepo)
+	mockAttRepo := new(MockAttendanceRepo) // Reusing the mock from attendance_service_test.go
+	service := services.NewReportService(mockReportRepo, mockAttRepo, fakeCalendar{})
+
+	alice := models.Student{Model: gorm.Model{ID: 1}, Name: "Alice", Department: models.Department{Name: "IT"}}
+	bob := models.Student{Model: gorm.Model{ID: 2}, Name: "Bob", Department: models.Department{Name: "HR"}}
+	monday := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	records := []models.Attendance{
+		{StudentID: 2, Student: bob, Date: monday, Status: "absent"},
+		{StudentID: 1, Student: alice, Date: monday, Status: "present"},
+		{StudentID: 1, Student: alice, Date: monday.AddDate(0, 0, 1), Status: "late", LateMinutes: 12},
+		{StudentID: 1, Student: alice, Date: monday.AddDate(0, 0, 2), Status: "absent"},
+		{StudentID: 1, Student: alice, Date: monday.AddDate(0, 0, 3), Status: "excused"},
+	}
+
+	// Case 1: Success, covers the seven days before today
+	var from, to time.Time
+	mockAttRepo.On("GetAttendanceBetween", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		from, to = args.Get(0).(time.Time), args.Get(1).(time.Time)
+	}).Return(records, nil).Once()
+	mockAttRepo.On("GetUnmarkedSessions", mock.Anything, mock.Anything).Return([]models.Attendance{}, nil).Once()
+	mockReportRepo.On("Create", mock.AnythingOfType("*models.Report")).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Report).ID = 5
+	}).Return(nil).Once()
+
+	resp, err := service.GenerateWeeklyReport()
+	assert.NoError(t, err)
+	assert.Equal(t, 6*24*time.Hour, to.Sub(from))
+	assert.Equal(t, uint(5), resp.ID)
+	assert.Equal(t, "weekly", resp.Type)
+	assert.Len(t, resp.Rows, 2)
+
+	row := resp.Rows[0]
+	assert.Equal(t, "Alice", row.StudentName)
+	assert.Equal(t, "IT", row.Department)
+	assert.Equal(t, []int{1, 1, 1, 1, 4}, []int{row.Present, row.Absent, row.Late, row.Excused, row.Total})
+	assert.Equal(t, 50.0, row.Percentage)
+	assert.Equal(t, 12, row.LateMinutes)
+	assert.Equal(t, 0.0, resp.Rows[1].Percentage)
+
+	// Case 2: DB Error on save
+	mockAttRepo.On("GetAttendanceBetween", mock.Anything, mock.Anything).Return(records, nil).Once()
+	mockAttRepo.On("GetUnmarkedSessions", mock.Anything, mock.Anything).Return([]models.Attendance{}, nil).Once()
+	mockReportRepo.On("Create", mock.Anything).Return(errors.New("db error")).Once()
+	resp, err = service.GenerateWeeklyReport()
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+}
+
+func TestGenerateMonthlyReport(t *testing.T) {
+	mockReportRepo := new(MockReportRepo)
+	mockAttRepo := new(MockAttendanceRepo)
+	service := services.NewReportService(mockReportRepo, mockAttRepo, fakeCalendar{})
+
+	// Covers the whole previous calendar month
+	var from, to time.Time
+	mockAttRepo.On("GetAttendanceBetween", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		from, to = args.Get(0).(time.Time), args.Get(1).(time.Time)
+	}).Return([]models.Attendance{}, nil).Once()
+	mockAttRepo.On("GetUnmarkedSessions", mock.Anything, mock.Anything).Return([]models.Attendance{}, nil).Once()
+	mockReportRepo.On("Create", mock.AnythingOfType("*models.Report")).Return(nil).Once()
+
+	resp, err := service.GenerateMonthlyReport()
+	assert.NoError(t, err)
+	assert.Equal(t, "monthly", resp.Type)
+	now := time.Now()
+	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, firstOfMonth.AddDate(0, -1, 0), from)
+	assert.Equal(t, firstOfMonth.AddDate(0, 0, -1), to)
+}
+
+func TestGetAttendanceSummary(t *testing.T) {
+	mockReportRepo := new(MockReportRepo)
+	mockAttRepo := new(MockAttendanceRepo)
+	service := services.NewReportService(mockReportRepo, mockAttRepo, fakeCalendar{})
+
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC)
+	alice := models.Student{Name: "Alice", Department: models.Department{Name: "IT"}}
+
+	// Case 1: Success, nothing is stored; an unmarked session counts as an absence
+	mockAttRepo.On("GetAttendanceBetween", from, to).Return([]models.Attendance{
+		{StudentID: 1, Student: alice, Date: from, Status: "present"},
+		{StudentID: 1, Student: alice, Date: from.AddDate(0, 0, 1), Status: "absent"},
+	}, nil).Once()
+	mockAttRepo.On("GetUnmarkedSessions", from, to).Return([]models.Attendance{
+		{StudentID: 1, Student: alice, Date: from.AddDate(0, 0, 2), Status: "absent"},
+	}, nil).Once()
+
+	rows, err := service.GetAttendanceSummary(from, to)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "IT", rows[0].Department)
+	assert.Equal(t, 2, rows[0].Absent)
+	assert.Equal(t, 33.33, rows[0].Percentage)
+	mockReportRepo.AssertNotCalled(t, "Create", mock.Anything)
+
+	// Case 2: Days without classes count neither as attended nor as missed
+	sunday := time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC)
+	weekends := services.NewReportService(mockReportRepo, mockAttRepo, fakeCalendar{sunday: "weekend"})
+	mockAttRepo.On("GetAttendanceBetween", from, to).Return([]models.Attendance{
+		{StudentID: 1, Student: alice, Date: from, Status: "present"},
+		{StudentID: 1, Student: alice, Date: sunday, Status: "absent"},
+	}, nil).Once()
+	mockAttRepo.On("GetUnmarkedSessions", from, to).Return([]models.Attendance{
+		{StudentID: 1, Student: alice, Date: sunday, Status: "absent"},
+	}, nil).Once()
+	rows, err = weekends.GetAttendanceSummary(from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, rows[0].Total)
+	assert.Equal(t, 100.0, rows[0].Percentage)
+
+	// Case 3: A day counts once: a whole day record covers its classes, and any class attended
+	// makes the day attended
+	section := uint(4)
+	mockAttRepo.On("GetAttendanceBetween", from, to).Return([]models.Attendance{
+		{StudentID: 1, Student: alice, Date: from, Status: "present"},
+		{StudentID: 1, Student: alice, Date: from, SectionID: &section, Status: "absent"},
+		{StudentID: 1, Student: alice, Date: from.AddDate(0, 0, 1), SectionID: &section, Status: "late", LateMinutes: 5},
+		{StudentID: 1, Student: alice, Date: from.AddDate(0, 0, 1), SectionID: &section, Status: "absent"},
+		{StudentID: 1, Student: alice, Date: from.AddDate(0, 0, 2), SectionID: &section, Status: "excused"},
+	}, nil).Once()
+	mockAttRepo.On("GetUnmarkedSessions", from, to).Return([]models.Attendance{
+		{StudentID: 1, Student: alice, Date: from.AddDate(0, 0, 2), SectionID: &section, Status: "excused"},
+	}, nil).Once()
+	rows, err = service.GetAttendanceSummary(from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 0, 1, 1, 3}, []int{rows[0].Present, rows[0].Absent, rows[0].Late, rows[0].Excused, rows[0].Total})
+	assert.Equal(t, 5, rows[0].LateMinutes)
+
+	// Case 4: DB Error
+	mockAttRepo.On("GetAttendanceBetween", from, to).Return(nil, errors.New("db error")).Once()
+	rows, err = service.GetAttendanceSummary(from, to)
+	assert.Error(t, err)
+	assert.Nil(t, rows)
+}
+
+func TestGetAllReports(t *testing.T) {
+	mockReportRepo := new(MockReportRepo)
+	service := services.NewReportService(mockReportRepo, new(MockAttendanceRepo), fakeCalendar{})
+
+	// Case 1: Success with Pagination (Page 2, Limit 5 -> Offset 5)
+	mockReportRepo.On("GetAll", 5, 5).Return([]models.Report{{Model: gorm.Model{ID: 1}, Type: "weekly"}}, nil).Once()
+	resp, err := service.GetAllReports(2, 5)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 1)
+
+	// Case 2: DB Error
+	mockReportRepo.On("GetAll", 10, 0).Return(nil, errors.New("db error")).Once()
+	resp, err = service.GetAllReports(0, 0)
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+}
+
+func TestGetReportByID(t *testing.T) {
+	mockReportRepo := new(MockReportRepo)
+	service := services.NewReportService(mockReportRepo, new(MockAttendanceRepo), fakeCalendar{})
+
+	// Case 1: Found
+	report := &models.Report{Model: gorm.Model{ID: 1}, Type: "weekly", Rows: []models.ReportRow{{StudentID: 1, Present: 3, Total: 3}}}
+	mockReportRepo.On("GetByID", uint(1)).Return(report, nil).Once()
+	resp, err := service.GetReportByID(1)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Rows, 1)
+
+	// Case 2: Not Found
+	mockReportRepo.On("GetByID", uint(99)).Return(nil, gorm.ErrRecordNotFound).Once()
+	resp, err = service.GetReportByID(99)
+	assert.ErrorIs(t, err, services.ErrReportNotFound)
+	assert.Nil(t, resp)
+}
@@ -1,148 +1,412 @@
 package services
 
 import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
 	"hrms_backend/internal/models"
 	"hrms_backend/internal/repository"
 	"hrms_backend/internal/viewmodels"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
 )
 
+// ErrStudentEmailTaken is returned when creating or updating a student with an email already in use.
+var ErrStudentEmailTaken = &Error{
+	Kind:    ErrConflict,
+	Code:    "student_email_taken",
+	Message: "a student with this email already exists",
+	Fields:  map[string]string{"email": "already in use"},
+}
+
+// ErrStudentNotDeleted is returned when restoring a student that is not deleted.
+var ErrStudentNotDeleted = &Error{Kind: ErrConflict, Code: "student_not_deleted", Message: "student is not deleted"}
+
 type StudentService interface {
-	CreateStudent(req viewmodels.CreateStudentRequest) (*viewmodels.StudentResponse, error)
-	GetAllStudents(page, limit int) ([]viewmodels.StudentResponse, error)
+	CreateStudent(req viewmodels.CreateStudentRequest, actor Actor) (*viewmodels.StudentResponse, error)
+	ListStudents(query viewmodels.StudentListQuery) (*viewmodels.StudentListResponse, error)
 	GetStudentByID(id uint) (*viewmodels.StudentResponse, error)
-	UpdateStudent(id uint, req viewmodels.UpdateStudentRequest) (*viewmodels.StudentResponse, error)
-	DeleteStudent(id uint) error
+	UpdateStudent(id uint, req viewmodels.UpdateStudentRequest, actor Actor) (*viewmodels.StudentResponse, error)
+	DeleteStudent(id uint, actor Actor) error
+	RestoreStudent(id uint, actor Actor) (*viewmodels.StudentResponse, error)
+	PurgeStudent(id uint, actor Actor) error
+	ImportStudents(file io.Reader, dryRun bool, actor Actor) (*viewmodels.StudentImportResponse, error)
 }
 
 type studentService struct {
-	repo repository.StudentRepository
+	repo        repository.StudentRepository
+	departments repository.DepartmentRepository
+	tx          repository.TxManager // read-check-write sequences
 }
 
 // Constructor
-func NewStudentService(repo repository.StudentRepository) StudentService {
+func NewStudentService(repo repository.StudentRepository, departments repository.DepartmentRepository, tx repository.TxManager) StudentService {
 	// sends
-	return &studentService{repo: repo}
+	return &studentService{repo: repo, departments: departments, tx: tx}
 }
 
-func (s *studentService) CreateStudent(req viewmodels.CreateStudentRequest) (*viewmodels.StudentResponse, error) {
+func (s *studentService) CreateStudent(req viewmodels.CreateStudentRequest, actor Actor) (*viewmodels.StudentResponse, error) {
+
+	department, err := s.departments.GetByID(req.DepartmentID)
+	if err != nil {
+		return nil, translateDBError(err, ErrUnknownDepartment, nil)
+	}
 
 	// 1️⃣ Convert ViewModel → Model
 	student := models.Student{
-		Name:       req.Name,
-		Email:      req.Email,
-		Department: req.Department,
+		Name:         req.Name,
+		Email:        req.Email,
+		DepartmentID: department.ID,
 	}
 
 	// 2️⃣ Call Repository
-	err := s.repo.Create(&student)
+	err = s.repo.Create(&student, actor.auditEntry(models.AuditActionCreate, models.AuditEntityStudent))
 	if err != nil {
-		return nil, err
+		return nil, translateDBError(err, nil, ErrStudentEmailTaken)
 	}
 
 	// 3️⃣ Convert Model → Response DTO
-	response := viewmodels.StudentResponse{
-		ID:         student.ID,
-		Name:       student.Name,
-		Email:      student.Email,
-		Department: student.Department,
-		CreatedAt:  student.CreatedAt,
-	}
+	student.Department = *department
+	response := toStudentResponse(student)
 
 	return &response, nil
 }
 
-// Get all students
-func (s *studentService) GetAllStudents(page, limit int) ([]viewmodels.StudentResponse, error) {
-	if page < 1 {
-		page = 1
+// ListStudents returns a filtered, sorted page of students; soft deleted ones are only listed if asked for
+func (s *studentService) ListStudents(query viewmodels.StudentListQuery) (*viewmodels.StudentListResponse, error) {
+	if query.Page < 1 {
+		query.Page = 1
 	}
-	if limit < 1 {
-		limit = 10
+	if query.Limit < 1 {
+		query.Limit = 10
 	}
-	offset := (page - 1) * limit
 
-	students, err := s.repo.GetAll(limit, offset)
+	filter := repository.StudentFilter{
+		Query:          query.Q,
+		DepartmentID:   query.DepartmentID,
+		CreatedFrom:    query.CreatedFrom,
+		IncludeDeleted: query.IncludeDeleted,
+		Sort:           query.Sort,
+		Limit:          query.Limit + 1, // one extra row tells whether another page follows
+		Offset:         (query.Page - 1) * query.Limit,
+		After:          query.Cursor,
+	}
+	// created_to is a whole day, inclusive
+	if !query.CreatedTo.IsZero() {
+		filter.CreatedTo = query.CreatedTo.AddDate(0, 0, 1)
+	}
+	students, total, err := s.repo.List(filter)
 	if err != nil {
-		return nil, err
+		return nil, translateDBError(err, nil, nil)
+	}
+	hasMore := len(students) > query.Limit
+	if hasMore {
+		students = students[:query.Limit]
 	}
 
 	// Map to DTO
 	responses := make([]viewmodels.StudentResponse, 0, len(students))
 	for _, st := range students {
-		responses = append(responses, viewmodels.StudentResponse{
-			ID:         st.ID,
-			Name:       st.Name,
-			Email:      st.Email,
-			Department: st.Department,
-			CreatedAt:  st.CreatedAt,
-		})
-	}
-	return responses, nil
+		responses = append(responses, toStudentResponse(st))
+	}
+
+	resp := &viewmodels.StudentListResponse{
+		Data:  responses,
+		Total: total,
+		Limit: query.Limit,
+	}
+	if query.Cursor == "" {
+		resp.Page = query.Page
+	}
+	if hasMore {
+		resp.NextCursor = repository.StudentCursor(query.Sort, &students[len(students)-1])
+		if query.Cursor == "" {
+			next := query.Page + 1
+			resp.Next = &next
+		}
+	}
+	return resp, nil
 }
 
 func (s *studentService) GetStudentByID(id uint) (*viewmodels.StudentResponse, error) {
 	st, err := s.repo.GetByID(id)
 	if err != nil {
-		return nil, err
-	}
-	resp := viewmodels.StudentResponse{
-		ID:         st.ID,
-		Name:       st.Name,
-		Email:      st.Email,
-		Department: st.Department,
-		CreatedAt:  st.CreatedAt,
+		return nil, translateDBError(err, ErrStudentNotFound, nil)
 	}
+	resp := toStudentResponse(*st)
 	return &resp, nil
 }
 
 // UpdateStudent updates fields provided in the request and returns the updated DTO.
 // It reads the existing record, updates only non-empty fields
-func (s *studentService) UpdateStudent(id uint, req viewmodels.UpdateStudentRequest) (*viewmodels.StudentResponse, error) {
-	// fetch existing
-	existing, err := s.repo.GetByID(id)
+func (s *studentService) UpdateStudent(id uint, req viewmodels.UpdateStudentRequest, actor Actor) (*viewmodels.StudentResponse, error) {
+	var updated *models.Student
+	err := s.tx.WithTx(context.Background(), func(repos repository.Repos) error {
+		// fetch existing, locked until the update is stored so concurrent updates cannot overwrite each other
+		existing, err := repos.Students.GetByID(id)
+		if err != nil {
+			return translateDBError(err, ErrStudentNotFound, nil)
+		}
+
+		// apply changes only when provided (empty string => no change)
+		if req.Name != "" {
+			existing.Name = req.Name
+		}
+		if req.Email != "" {
+			existing.Email = req.Email
+		}
+		if req.DepartmentID != 0 {
+			if _, err := s.departments.GetByID(req.DepartmentID); err != nil {
+				return translateDBError(err, ErrUnknownDepartment, nil)
+			}
+			existing.DepartmentID = req.DepartmentID
+		}
+
+		// persist update
+		if err := repos.Students.Update(id, existing, actor.auditEntry(models.AuditActionUpdate, models.AuditEntityStudent)); err != nil {
+			return translateDBError(err, ErrStudentNotFound, ErrStudentEmailTaken)
+		}
+
+		// fetch again to ensure fields like UpdatedAt are current (optional)
+		updated, err = repos.Students.GetByID(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toStudentResponse(*updated)
+	return &resp, nil
+}
+
+// deletes the student with the given id.
+func (s *studentService) DeleteStudent(id uint, actor Actor) error {
+	return s.tx.WithTx(context.Background(), func(repos repository.Repos) error {
+		// Optionally, verify existence first
+		_, err := repos.Students.GetByID(id)
+		if err != nil {
+			return translateDBError(err, ErrStudentNotFound, nil)
+		}
+		err = repos.Students.Delete(id, actor.auditEntry(models.AuditActionDelete, models.AuditEntityStudent))
+		return translateDBError(err, ErrStudentNotFound, nil)
+	})
+}
+
+// RestoreStudent undoes a soft delete. It fails with ErrStudentEmailTaken if another
+// student has been created with the same email in the meantime.
+func (s *studentService) RestoreStudent(id uint, actor Actor) (*viewmodels.StudentResponse, error) {
+	var st *models.Student
+	err := s.tx.WithTx(context.Background(), func(repos repository.Repos) error {
+		var err error
+		st, err = repos.Students.GetByIDWithDeleted(id)
+		if err != nil {
+			return translateDBError(err, ErrStudentNotFound, nil)
+		}
+		if !st.DeletedAt.Valid {
+			return ErrStudentNotDeleted
+		}
+
+		err = repos.Students.Restore(id, actor.auditEntry(models.AuditActionRestore, models.AuditEntityStudent))
+		return translateDBError(err, ErrStudentNotFound, ErrStudentEmailTaken)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// apply changes only when provided (empty string => no change)
-	if req.Name != "" {
-		existing.Name = req.Name
+	st.DeletedAt = gorm.DeletedAt{}
+	resp := toStudentResponse(*st)
+	return &resp, nil
+}
+
+// PurgeStudent permanently deletes a student, soft deleted or not, together with its attendance
+func (s *studentService) PurgeStudent(id uint, actor Actor) error {
+	err := s.repo.Purge(id, actor.auditEntry(models.AuditActionPurge, models.AuditEntityStudent))
+	return translateDBError(err, ErrStudentNotFound, nil)
+}
+
+// maxImportRows caps the size of a single import
+const maxImportRows = 5000
+
+// columns an import file must have, in any order
+var importColumns = []string{"name", "email", "department"}
+
+var emailValidator = validator.New()
+
+// ImportStudents reads a CSV file with a header row naming the name, email and department columns
+// and checks every row: required fields, lengths, email format, departments (by name), and emails
+// repeated within the file or already used by a student. Unless dryRun, the valid rows are inserted in one transaction;
+// invalid rows are reported and skipped either way.
+func (s *studentService) ImportStudents(file io.Reader, dryRun bool, actor Actor) (*viewmodels.StudentImportResponse, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ValidationError("file", "is empty")
+	}
+	if err != nil {
+		return nil, ValidationError("file", "is not valid CSV")
 	}
-	if req.Email != "" {
-		existing.Email = req.Email
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // byte order mark written by spreadsheet exports
+		}
+		index[strings.ToLower(strings.TrimSpace(name))] = i
 	}
-	if req.Department != "" {
-		existing.Department = req.Department
+	for _, column := range importColumns {
+		if _, ok := index[column]; !ok {
+			return nil, ValidationError("file", "missing column "+column)
+		}
 	}
 
-	// persist update
-	if err := s.repo.Update(id, existing); err != nil {
+	departments, err := s.departments.GetAll()
+	if err != nil {
 		return nil, err
 	}
+	byName := make(map[string]uint, len(departments))
+	for _, d := range departments {
+		byName[strings.ToLower(d.Name)] = d.ID // names are unique case insensitively
+	}
 
-	// fetch again to ensure fields like UpdatedAt are current (optional)
-	updated, err := s.repo.GetByID(id)
+	resp := &viewmodels.StudentImportResponse{DryRun: dryRun, Errors: []viewmodels.StudentImportError{}}
+	var students []models.Student
+	var lines []int
+	firstLine := make(map[string]int) // lower cased email -> line it first appeared on
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		resp.Total++
+		if resp.Total > maxImportRows {
+			return nil, ValidationError("file", fmt.Sprintf("has more than %d rows", maxImportRows))
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			resp.Errors = append(resp.Errors, viewmodels.StudentImportError{Line: parseErr.StartLine, Message: parseErr.Err.Error()})
+			resp.Invalid++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		field := func(column string) string {
+			if i := index[column]; i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		department := field("department")
+		st := models.Student{Name: field("name"), Email: field("email"), DepartmentID: byName[strings.ToLower(department)]}
+
+		rowErrors := validateImportRow(st.Name, st.Email, department)
+		if department != "" && st.DepartmentID == 0 {
+			rowErrors = append(rowErrors, viewmodels.StudentImportError{Field: "department", Message: "unknown department"})
+		}
+		if key := strings.ToLower(st.Email); st.Email != "" {
+			if first, seen := firstLine[key]; seen {
+				rowErrors = append(rowErrors, viewmodels.StudentImportError{Field: "email", Message: fmt.Sprintf("repeats line %d", first)})
+			} else {
+				firstLine[key] = line
+			}
+		}
+		if len(rowErrors) > 0 {
+			for _, e := range rowErrors {
+				e.Line, e.Email = line, st.Email
+				resp.Errors = append(resp.Errors, e)
+			}
+			resp.Invalid++
+			continue
+		}
+		students = append(students, st)
+		lines = append(lines, line)
+	}
+	if resp.Total == 0 {
+		return nil, ValidationError("file", "has no rows")
+	}
+
+	// drop the rows whose email an existing student already uses
+	emails := make([]string, len(students))
+	for i, st := range students {
+		emails[i] = st.Email
+	}
+	existing, err := s.repo.GetByEmails(emails)
 	if err != nil {
-		// if fetch fails after update, surface the update error or return a wrapped error
 		return nil, err
 	}
+	taken := make(map[string]bool, len(existing))
+	for _, st := range existing {
+		taken[strings.ToLower(st.Email)] = true
+	}
+	valid := students[:0]
+	for i, st := range students {
+		if taken[strings.ToLower(st.Email)] {
+			resp.Errors = append(resp.Errors, viewmodels.StudentImportError{
+				Line: lines[i], Field: "email", Email: st.Email, Message: "already used by another student",
+			})
+			resp.Invalid++
+			continue
+		}
+		valid = append(valid, st)
+	}
+	resp.Valid = len(valid)
+	slices.SortStableFunc(resp.Errors, func(a, b viewmodels.StudentImportError) int { return a.Line - b.Line })
 
-	resp := viewmodels.StudentResponse{
-		ID:         updated.ID,
-		Name:       updated.Name,
-		Email:      updated.Email,
-		Department: updated.Department,
-		CreatedAt:  updated.CreatedAt,
+	if dryRun || len(valid) == 0 {
+		return resp, nil
 	}
-	return &resp, nil
+	if err := s.repo.CreateBatch(valid, actor.auditEntry(models.AuditActionCreate, models.AuditEntityStudent)); err != nil {
+		// a concurrent request took one of the emails since the check above
+		return nil, translateDBError(err, nil, ErrStudentEmailTaken)
+	}
+	resp.Created = len(valid)
+	return resp, nil
 }
 
-// deletes the student with the given id.
-func (s *studentService) DeleteStudent(id uint) error {
-	// Optionally, verify existence first
-	_, err := s.repo.GetByID(id)
-	if err != nil {
-		return err
+// validateImportRow applies the rules of CreateStudentRequest plus the column sizes
+func validateImportRow(name, email, department string) []viewmodels.StudentImportError {
+	var errs []viewmodels.StudentImportError
+	check := func(field, value string, max int) bool {
+		switch {
+		case value == "":
+			errs = append(errs, viewmodels.StudentImportError{Field: field, Message: "is required"})
+		case utf8.RuneCountInString(value) > max:
+			errs = append(errs, viewmodels.StudentImportError{Field: field, Message: fmt.Sprintf("must be at most %d characters", max)})
+		default:
+			return true
+		}
+		return false
+	}
+	check("name", name, 100)
+	if check("email", email, 150) && emailValidator.Var(email, "email") != nil {
+		errs = append(errs, viewmodels.StudentImportError{Field: "email", Message: "must be a valid email address"})
+	}
+	check("department", department, 100)
+	return errs
+}
+
+func toStudentResponse(st models.Student) viewmodels.StudentResponse {
+	resp := viewmodels.StudentResponse{
+		ID:           st.ID,
+		Name:         st.Name,
+		Email:        st.Email,
+		DepartmentID: st.DepartmentID,
+		Department:   st.Department.Name,
+		CreatedAt:    st.CreatedAt,
+	}
+	if st.DeletedAt.Valid {
+		resp.DeletedAt = &st.DeletedAt.Time
 	}
-	return s.repo.Delete(id)
+	return resp
 }
@@ -1,10 +1,14 @@
 package services_test
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
 	"hrms_backend/internal/services"
 	"hrms_backend/internal/viewmodels"
 
@@ -18,17 +22,17 @@ type MockStudentRepo struct {
 	mock.Mock
 }
 
-func (m *MockStudentRepo) Create(student *models.Student) error {
-	args := m.Called(student)
+func (m *MockStudentRepo) Create(student *models.Student, audit *models.AuditLog) error {
+	args := m.Called(student, audit)
 	return args.Error(0)
 }
 
-func (m *MockStudentRepo) GetAll(limit, offset int) ([]models.Student, error) {
-	args := m.Called(limit, offset)
+func (m *MockStudentRepo) List(filter repository.StudentFilter) ([]models.Student, int64, error) {
+	args := m.Called(filter)
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, 0, args.Error(2)
 	}
-	return args.Get(0).([]models.Student), args.Error(1)
+	return args.Get(0).([]models.Student), args.Get(1).(int64), args.Error(2)
 }
 
 func (m *MockStudentRepo) GetByID(id uint) (*models.Student, error) {
@@ -39,62 +43,176 @@ func (m *MockStudentRepo) GetByID(id uint) (*models.Student, error) {
 	return args.Get(0).(*models.Student), args.Error(1)
 }
 
-func (m *MockStudentRepo) Update(id uint, student *models.Student) error {
-	args := m.Called(id, student)
+func (m *MockStudentRepo) GetByIDWithDeleted(id uint) (*models.Student, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Student), args.Error(1)
+}
+
+func (m *MockStudentRepo) GetByIDs(ids []uint) ([]models.Student, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Student), args.Error(1)
+}
+
+func (m *MockStudentRepo) GetByEmails(emails []string) ([]models.Student, error) {
+	args := m.Called(emails)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Student), args.Error(1)
+}
+
+func (m *MockStudentRepo) CreateBatch(students []models.Student, audit *models.AuditLog) error {
+	args := m.Called(students, audit)
 	return args.Error(0)
 }
 
-func (m *MockStudentRepo) Delete(id uint) error {
-	args := m.Called(id)
+func (m *MockStudentRepo) GetByDepartment(departmentID uint) ([]models.Student, error) {
+	args := m.Called(departmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Student), args.Error(1)
+}
+
+func (m *MockStudentRepo) Update(id uint, student *models.Student, audit *models.AuditLog) error {
+	args := m.Called(id, student, audit)
+	return args.Error(0)
+}
+
+func (m *MockStudentRepo) Delete(id uint, audit *models.AuditLog) error {
+	args := m.Called(id, audit)
 	return args.Error(0)
 }
 
+func (m *MockStudentRepo) Restore(id uint, audit *models.AuditLog) error {
+	args := m.Called(id, audit)
+	return args.Error(0)
+}
+
+func (m *MockStudentRepo) Purge(id uint, audit *models.AuditLog) error {
+	args := m.Called(id, audit)
+	return args.Error(0)
+}
+
+// fakeTx runs fn right away, handing it the given mocks as the transaction's repositories
+type fakeTx repository.Repos
+
+func (f fakeTx) WithTx(ctx context.Context, fn func(repos repository.Repos) error) error {
+	return fn(repository.Repos(f))
+}
+
 // --- Tests ---
 
 func TestCreateStudent(t *testing.T) {
 	mockRepo := new(MockStudentRepo)
-	service := services.NewStudentService(mockRepo)
+	mockDeptRepo := new(MockDepartmentRepo)
+	service := services.NewStudentService(mockRepo, mockDeptRepo, fakeTx{Students: mockRepo})
 
-	req := viewmodels.CreateStudentRequest{Name: "Alice", Email: "alice@test.com", Department: "IT"}
+	req := viewmodels.CreateStudentRequest{Name: "Alice", Email: "alice@test.com", DepartmentID: 2}
+	mockDeptRepo.On("GetByID", uint(2)).Return(&models.Department{ID: 2, Name: "IT"}, nil)
 
 	// Case 1: Success
-	mockRepo.On("Create", mock.AnythingOfType("*models.Student")).Return(nil).Once()
-	resp, err := service.CreateStudent(req)
+	mockRepo.On("Create", mock.MatchedBy(func(s *models.Student) bool { return s.DepartmentID == 2 }), mock.Anything).Return(nil).Once()
+	resp, err := service.CreateStudent(req, services.Actor{})
 	assert.NoError(t, err)
 	assert.Equal(t, "Alice", resp.Name)
+	assert.Equal(t, "IT", resp.Department)
 
 	// Case 2: DB Error
-	mockRepo.On("Create", mock.Anything).Return(errors.New("db error")).Once()
-	resp, err = service.CreateStudent(req)
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
+	resp, err = service.CreateStudent(req, services.Actor{})
 	assert.Error(t, err)
 	assert.Nil(t, resp)
+
+	// Case 3: Duplicate email
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(gorm.ErrDuplicatedKey).Once()
+	resp, err = service.CreateStudent(req, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrStudentEmailTaken)
+	assert.ErrorIs(t, err, services.ErrConflict)
+	assert.Nil(t, resp)
+
+	// Case 4: Unknown department
+	mockDeptRepo.On("GetByID", uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.CreateStudent(viewmodels.CreateStudentRequest{Name: "Bob", Email: "b@b.com", DepartmentID: 9}, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrUnknownDepartment)
+	assert.ErrorIs(t, err, services.ErrValidation)
 }
 
-func TestGetAllStudents(t *testing.T) {
+func TestListStudents(t *testing.T) {
 	mockRepo := new(MockStudentRepo)
-	service := services.NewStudentService(mockRepo)
+	service := services.NewStudentService(mockRepo, new(MockDepartmentRepo), fakeTx{Students: mockRepo})
 
 	mockData := []models.Student{
 		{Model: gorm.Model{ID: 1}, Name: "A", Email: "a@a.com"},
 		{Model: gorm.Model{ID: 2}, Name: "B", Email: "b@b.com"},
 	}
 
-	// Case 1: Success with Pagination (Page 1, Limit 10 -> Offset 0)
-	mockRepo.On("GetAll", 10, 0).Return(mockData, nil).Once()
-	resp, err := service.GetAllStudents(1, 10)
+	// Case 1: Defaults (Page 1, Limit 10 -> Offset 0), last page
+	mockRepo.On("List", repository.StudentFilter{Limit: 11}).Return(mockData, int64(2), nil).Once()
+	resp, err := service.ListStudents(viewmodels.StudentListQuery{})
 	assert.NoError(t, err)
-	assert.Len(t, resp, 2)
+	assert.Len(t, resp.Data, 2)
+	assert.Equal(t, int64(2), resp.Total)
+	assert.Equal(t, 1, resp.Page)
+	assert.Nil(t, resp.Next)
+	assert.Empty(t, resp.NextCursor)
+	assert.Nil(t, resp.Data[0].DeletedAt)
 
-	// Case 2: DB Error
-	mockRepo.On("GetAll", 10, 0).Return(nil, errors.New("db error")).Once()
-	resp, err = service.GetAllStudents(1, 10)
+	// Case 2: Filters are passed through, created_to covers the whole day, more pages follow
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("List", repository.StudentFilter{
+		Query:        "ali",
+		DepartmentID: 3,
+		CreatedFrom:  from,
+		CreatedTo:    to.AddDate(0, 0, 1),
+		Sort:         "-created_at",
+		Limit:        2,
+		Offset:       1,
+	}).Return(mockData, int64(5), nil).Once()
+	resp, err = service.ListStudents(viewmodels.StudentListQuery{
+		Q: "ali", DepartmentID: 3, CreatedFrom: from, CreatedTo: to, Sort: "-created_at", Page: 2, Limit: 1,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Data, 1)
+	if assert.NotNil(t, resp.Next) {
+		assert.Equal(t, 3, *resp.Next)
+	}
+	assert.NotEmpty(t, resp.NextCursor)
+
+	// Case 3: Following the cursor replaces the offset and leaves out page numbers
+	cursor := resp.NextCursor
+	mockRepo.On("List", repository.StudentFilter{Sort: "-created_at", Limit: 2, Offset: 0, After: cursor}).Return(mockData[1:], int64(5), nil).Once()
+	resp, err = service.ListStudents(viewmodels.StudentListQuery{Sort: "-created_at", Limit: 1, Cursor: cursor})
+	assert.NoError(t, err)
+	assert.Zero(t, resp.Page)
+	assert.Nil(t, resp.Next)
+	assert.Empty(t, resp.NextCursor)
+
+	// Case 4: Including soft deleted students
+	deleted := models.Student{Model: gorm.Model{ID: 3, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}, Name: "C"}
+	mockRepo.On("List", repository.StudentFilter{IncludeDeleted: true, Limit: 11}).Return(append(mockData, deleted), int64(3), nil).Once()
+	resp, err = service.ListStudents(viewmodels.StudentListQuery{IncludeDeleted: true})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Data, 3)
+	assert.NotNil(t, resp.Data[2].DeletedAt)
+
+	// Case 5: DB Error
+	mockRepo.On("List", repository.StudentFilter{Limit: 11}).Return(nil, int64(0), errors.New("db error")).Once()
+	resp, err = service.ListStudents(viewmodels.StudentListQuery{})
 	assert.Error(t, err)
 	assert.Nil(t, resp)
 }
 
 func TestGetStudentByID(t *testing.T) {
 	mockRepo := new(MockStudentRepo)
-	service := services.NewStudentService(mockRepo)
+	service := services.NewStudentService(mockRepo, new(MockDepartmentRepo), fakeTx{Students: mockRepo})
 
 	student := &models.Student{Model: gorm.Model{ID: 1}, Name: "Alice"}
 
@@ -113,7 +231,7 @@ func TestGetStudentByID(t *testing.T) {
 
 func TestUpdateStudent(t *testing.T) {
 	mockRepo := new(MockStudentRepo)
-	service := services.NewStudentService(mockRepo)
+	service := services.NewStudentService(mockRepo, new(MockDepartmentRepo), fakeTx{Students: mockRepo})
 
 	existing := &models.Student{Model: gorm.Model{ID: 1}, Name: "Old Name"}
 	req := viewmodels.UpdateStudentRequest{Name: "New Name"}
@@ -123,34 +241,141 @@ func TestUpdateStudent(t *testing.T) {
 	mockRepo.On("GetByID", uint(1)).Return(existing, nil).Once()
 	mockRepo.On("Update", uint(1), mock.MatchedBy(func(s *models.Student) bool {
 		return s.Name == "New Name"
-	})).Return(nil).Once()
+	}), mock.Anything).Return(nil).Once()
 	mockRepo.On("GetByID", uint(1)).Return(&models.Student{Model: gorm.Model{ID: 1}, Name: "New Name"}, nil).Once()
 
-	resp, err := service.UpdateStudent(1, req)
+	resp, err := service.UpdateStudent(1, req, services.Actor{})
 	assert.NoError(t, err)
 	assert.Equal(t, "New Name", resp.Name)
 
 	// Case 2: Student Not Found
 	mockRepo.On("GetByID", uint(99)).Return(nil, errors.New("not found")).Once()
-	resp, err = service.UpdateStudent(99, req)
+	resp, err = service.UpdateStudent(99, req, services.Actor{})
 	assert.Error(t, err)
 	assert.Nil(t, resp)
 }
 
 func TestDeleteStudent(t *testing.T) {
 	mockRepo := new(MockStudentRepo)
-	service := services.NewStudentService(mockRepo)
+	service := services.NewStudentService(mockRepo, new(MockDepartmentRepo), fakeTx{Students: mockRepo})
 
 	// Case 1: Success
 	// Service usually checks existence first
 	mockRepo.On("GetByID", uint(1)).Return(&models.Student{}, nil).Once()
-	mockRepo.On("Delete", uint(1)).Return(nil).Once()
-	err := service.DeleteStudent(1)
+	mockRepo.On("Delete", uint(1), mock.Anything).Return(nil).Once()
+	err := service.DeleteStudent(1, services.Actor{})
 	assert.NoError(t, err)
 
 	// Case 2: Repo Error (e.g., Delete fails)
 	mockRepo.On("GetByID", uint(2)).Return(&models.Student{}, nil).Once()
-	mockRepo.On("Delete", uint(2)).Return(errors.New("delete failed")).Once()
-	err = service.DeleteStudent(2)
+	mockRepo.On("Delete", uint(2), mock.Anything).Return(errors.New("delete failed")).Once()
+	err = service.DeleteStudent(2, services.Actor{})
 	assert.Error(t, err)
 }
+
+func TestRestoreStudent(t *testing.T) {
+	mockRepo := new(MockStudentRepo)
+	service := services.NewStudentService(mockRepo, new(MockDepartmentRepo), fakeTx{Students: mockRepo})
+
+	deletedAt := gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	// Case 1: Success
+	mockRepo.On("GetByIDWithDeleted", uint(1)).Return(&models.Student{Model: gorm.Model{ID: 1, DeletedAt: deletedAt}, Name: "Alice"}, nil).Once()
+	mockRepo.On("Restore", uint(1), mock.MatchedBy(func(a *models.AuditLog) bool { return a.Action == models.AuditActionRestore })).Return(nil).Once()
+	resp, err := service.RestoreStudent(1, services.Actor{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice", resp.Name)
+	assert.Nil(t, resp.DeletedAt)
+
+	// Case 2: Not deleted
+	mockRepo.On("GetByIDWithDeleted", uint(2)).Return(&models.Student{Model: gorm.Model{ID: 2}}, nil).Once()
+	_, err = service.RestoreStudent(2, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrStudentNotDeleted)
+
+	// Case 3: Email taken by a student created since
+	mockRepo.On("GetByIDWithDeleted", uint(3)).Return(&models.Student{Model: gorm.Model{ID: 3, DeletedAt: deletedAt}}, nil).Once()
+	mockRepo.On("Restore", uint(3), mock.Anything).Return(gorm.ErrDuplicatedKey).Once()
+	_, err = service.RestoreStudent(3, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrStudentEmailTaken)
+
+	// Case 4: Not Found
+	mockRepo.On("GetByIDWithDeleted", uint(99)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.RestoreStudent(99, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrStudentNotFound)
+}
+
+func TestPurgeStudent(t *testing.T) {
+	mockRepo := new(MockStudentRepo)
+	service := services.NewStudentService(mockRepo, new(MockDepartmentRepo), fakeTx{Students: mockRepo})
+
+	// Case 1: Success
+	mockRepo.On("Purge", uint(1), mock.MatchedBy(func(a *models.AuditLog) bool { return a.Action == models.AuditActionPurge })).Return(nil).Once()
+	assert.NoError(t, service.PurgeStudent(1, services.Actor{}))
+
+	// Case 2: Not Found
+	mockRepo.On("Purge", uint(99), mock.Anything).Return(gorm.ErrRecordNotFound).Once()
+	assert.ErrorIs(t, service.PurgeStudent(99, services.Actor{}), services.ErrStudentNotFound)
+}
+
+func TestImportStudents(t *testing.T) {
+	mockRepo := new(MockStudentRepo)
+	mockDeptRepo := new(MockDepartmentRepo)
+	service := services.NewStudentService(mockRepo, mockDeptRepo, fakeTx{Students: mockRepo})
+	mockDeptRepo.On("GetAll").Return([]models.Department{{ID: 1, Name: "CS"}, {ID: 2, Name: "Math"}}, nil)
+
+	file := "\ufeffEmail,Name,Department\n" +
+		"alice@example.com,Alice,CS\n" + // line 2: valid
+		"bob@example.com,Bob,CS\n" + // line 3: email already used
+		",Carol,\n" + // line 4: email and department missing
+		"ALICE@example.com,Alice Again,CS\n" + // line 5: repeats line 2
+		"not-an-email,Dave,CS\n" + // line 6: bad email
+		"erin@example.com,Erin,math\n" + // line 7: valid, department names ignore case
+		"frank@example.com,Frank,Physics\n" // line 8: unknown department
+	valid := []models.Student{
+		{Name: "Alice", Email: "alice@example.com", DepartmentID: 1},
+		{Name: "Erin", Email: "erin@example.com", DepartmentID: 2},
+	}
+	emails := []string{"alice@example.com", "bob@example.com", "erin@example.com"}
+	existing := []models.Student{{Model: gorm.Model{ID: 9}, Email: "Bob@example.com"}}
+
+	// Case 1: Dry run reports every problem and stores nothing
+	mockRepo.On("GetByEmails", emails).Return(existing, nil).Once()
+	resp, err := service.ImportStudents(strings.NewReader(file), true, services.Actor{})
+	assert.NoError(t, err)
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, 7, resp.Total)
+	assert.Equal(t, 2, resp.Valid)
+	assert.Equal(t, 5, resp.Invalid)
+	assert.Equal(t, 0, resp.Created)
+	assert.Equal(t, []viewmodels.StudentImportError{
+		{Line: 3, Field: "email", Email: "bob@example.com", Message: "already used by another student"},
+		{Line: 4, Field: "email", Message: "is required"},
+		{Line: 4, Field: "department", Message: "is required"},
+		{Line: 5, Field: "email", Email: "ALICE@example.com", Message: "repeats line 2"},
+		{Line: 6, Field: "email", Email: "not-an-email", Message: "must be a valid email address"},
+		{Line: 8, Field: "department", Email: "frank@example.com", Message: "unknown department"},
+	}, resp.Errors)
+	mockRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+
+	// Case 2: Commit inserts the valid rows
+	mockRepo.On("GetByEmails", emails).Return(existing, nil).Once()
+	mockRepo.On("CreateBatch", valid, mock.Anything).Return(nil).Once()
+	resp, err = service.ImportStudents(strings.NewReader(file), false, services.Actor{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resp.Created)
+
+	// Case 3: An email taken concurrently rolls the batch back
+	mockRepo.On("GetByEmails", emails).Return(existing, nil).Once()
+	mockRepo.On("CreateBatch", valid, mock.Anything).Return(gorm.ErrDuplicatedKey).Once()
+	_, err = service.ImportStudents(strings.NewReader(file), false, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrStudentEmailTaken)
+
+	// Case 4: Missing column
+	_, err = service.ImportStudents(strings.NewReader("name,email\nA,a@a.com\n"), true, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrValidation)
+
+	// Case 5: Header only
+	_, err = service.ImportStudents(strings.NewReader("name,email,department\n"), true, services.Actor{})
+	assert.ErrorIs(t, err, services.ErrValidation)
+	mockRepo.AssertExpectations(t)
+}
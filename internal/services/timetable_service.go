@@ -0,0 +1,313 @@
+package services
+
+import (
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
+	"hrms_backend/internal/viewmodels"
+	"strings"
+	"time"
+)
+
+// sessionHorizonDays is how far ahead sessions are generated, by the daily job and whenever a slot changes
+const sessionHorizonDays = 14
+
+// maxGenerateDays bounds the range of a single GenerateSessions call
+const maxGenerateDays = 366
+
+var (
+	// ErrSlotNotFound is returned when a timetable slot ID does not exist.
+	ErrSlotNotFound = &Error{Kind: ErrNotFound, Code: "slot_not_found", Message: "timetable slot not found"}
+	// ErrSessionNotFound is returned when a class session ID does not exist.
+	ErrSessionNotFound = &Error{Kind: ErrNotFound, Code: "session_not_found", Message: "class session not found"}
+	// ErrUnknownTeacher is returned when a slot names a user that does not exist or cannot teach.
+	ErrUnknownTeacher = &Error{
+		Kind:    ErrValidation,
+		Code:    "unknown_teacher",
+		Message: "teacher does not exist",
+		Fields:  map[string]string{"teacher_id": "must be a teacher or admin user"},
+	}
+)
+
+type TimetableService interface {
+	CreateSlot(req viewmodels.CreateTimetableSlotRequest) (*viewmodels.TimetableSlotResponse, error)
+	ListSlots(sectionID uint) ([]viewmodels.TimetableSlotResponse, error)
+	UpdateSlot(id uint, req viewmodels.TimetableSlotRequest) (*viewmodels.TimetableSlotResponse, error)
+	DeleteSlot(id uint) error
+	GenerateSessions(from, to time.Time) (*viewmodels.GenerateSessionsResponse, error)
+	GenerateUpcomingSessions() (*viewmodels.GenerateSessionsResponse, error)
+	ListSessions(query viewmodels.ClassSessionQuery) ([]viewmodels.ClassSessionResponse, error)
+	SetSessionCancelled(id uint, cancelled bool) (*viewmodels.ClassSessionResponse, error)
+}
+
+type timetableService struct {
+	repo     repository.TimetableRepository
+	sections repository.SectionRepository
+	users    repository.UserRepository
+	calendar SchoolCalendar // no sessions on days without classes
+}
+
+func NewTimetableService(repo repository.TimetableRepository, sections repository.SectionRepository, users repository.UserRepository, calendar SchoolCalendar) TimetableService {
+	return &timetableService{repo: repo, sections: sections, users: users, calendar: calendar}
+}
+
+// CreateSlot adds a weekly slot to a section and generates its upcoming sessions
+func (s *timetableService) CreateSlot(req viewmodels.CreateTimetableSlotRequest) (*viewmodels.TimetableSlotResponse, error) {
+	section, err := s.sections.GetByID(req.SectionID)
+	if err != nil {
+		return nil, translateDBError(err, ErrUnknownSection, nil)
+	}
+	slot := models.TimetableSlot{SectionID: section.ID, Section: *section}
+	if err := s.applySchedule(&slot, req.TimetableSlotRequest); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateSlot(&slot); err != nil {
+		return nil, err
+	}
+	if err := s.generateUpcoming(slot); err != nil {
+		return nil, err
+	}
+	resp := toSlotResponse(slot)
+	return &resp, nil
+}
+
+// ListSlots returns the slots of a section, or of every section when sectionID is 0, in weekly order
+func (s *timetableService) ListSlots(sectionID uint) ([]viewmodels.TimetableSlotResponse, error) {
+	slots, err := s.repo.GetSlots(sectionID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]viewmodels.TimetableSlotResponse, 0, len(slots))
+	for _, slot := range slots {
+		responses = append(responses, toSlotResponse(slot))
+	}
+	return responses, nil
+}
+
+// UpdateSlot changes the schedule of a slot. Its sessions from today on are generated again,
+// except cancelled ones; past sessions keep the schedule they had.
+func (s *timetableService) UpdateSlot(id uint, req viewmodels.TimetableSlotRequest) (*viewmodels.TimetableSlotResponse, error) {
+	slot, err := s.repo.GetSlotByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrSlotNotFound, nil)
+	}
+	if err := s.applySchedule(slot, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateSlot(slot, today()); err != nil {
+		return nil, translateDBError(err, ErrSlotNotFound, nil)
+	}
+	if err := s.generateUpcoming(*slot); err != nil {
+		return nil, err
+	}
+	resp := toSlotResponse(*slot)
+	return &resp, nil
+}
+
+// DeleteSlot removes a slot and its sessions from today on; past sessions are kept
+func (s *timetableService) DeleteSlot(id uint) error {
+	return translateDBError(s.repo.DeleteSlot(id, today()), ErrSlotNotFound, nil)
+}
+
+// GenerateSessions creates the sessions of every slot within [from, to], skipping the days without
+// classes (weekends, holidays, closures, days outside every term). Sessions that already exist,
+// cancelled ones included, are left alone, so it is safe to run again over the same days.
+func (s *timetableService) GenerateSessions(from, to time.Time) (*viewmodels.GenerateSessionsResponse, error) {
+	from, to = truncateToDay(from), truncateToDay(to)
+	if to.Before(from) {
+		return nil, ValidationError("to", "must not be before from")
+	}
+	if to.Sub(from) >= maxGenerateDays*24*time.Hour {
+		return nil, ValidationError("to", "must be less than a year after from")
+	}
+
+	slots, err := s.repo.GetSlotsValidBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+	off, err := s.calendar.NonSchoolDays(from, to)
+	if err != nil {
+		return nil, err
+	}
+	var sessions []models.ClassSession
+	for _, slot := range slots {
+		sessions = append(sessions, sessionsOf(slot, from, to, off)...)
+	}
+	created, err := s.repo.CreateSessions(sessions)
+	if err != nil {
+		return nil, err
+	}
+	return &viewmodels.GenerateSessionsResponse{From: from, To: to, Created: created}, nil
+}
+
+// GenerateUpcomingSessions generates the sessions of the next sessionHorizonDays days, today included
+func (s *timetableService) GenerateUpcomingSessions() (*viewmodels.GenerateSessionsResponse, error) {
+	from := today()
+	return s.GenerateSessions(from, from.AddDate(0, 0, sessionHorizonDays-1))
+}
+
+// ListSessions returns the sessions of [from, to] in chronological order
+func (s *timetableService) ListSessions(query viewmodels.ClassSessionQuery) ([]viewmodels.ClassSessionResponse, error) {
+	sessions, err := s.repo.ListSessions(repository.SessionFilter{
+		SectionID:        query.SectionID,
+		TeacherID:        query.TeacherID,
+		From:             truncateToDay(query.From),
+		To:               truncateToDay(query.To),
+		IncludeCancelled: query.IncludeCancelled,
+	})
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]viewmodels.ClassSessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		responses = append(responses, toSessionResponse(session))
+	}
+	return responses, nil
+}
+
+// SetSessionCancelled cancels a session, so its students are no longer expected, or restores it
+func (s *timetableService) SetSessionCancelled(id uint, cancelled bool) (*viewmodels.ClassSessionResponse, error) {
+	if err := s.repo.SetCancelled(id, cancelled); err != nil {
+		return nil, translateDBError(err, ErrSessionNotFound, nil)
+	}
+	session, err := s.repo.GetSessionByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrSessionNotFound, nil)
+	}
+	resp := toSessionResponse(*session)
+	return &resp, nil
+}
+
+// applySchedule validates req and copies it to slot
+func (s *timetableService) applySchedule(slot *models.TimetableSlot, req viewmodels.TimetableSlotRequest) error {
+	// zero padded "15:04" times compare as strings
+	if req.EndTime <= req.StartTime {
+		return ValidationError("end_time", "must be after start_time")
+	}
+	validFrom := truncateToDay(req.ValidFrom)
+	var validUntil *time.Time
+	if req.ValidUntil != nil {
+		day := truncateToDay(*req.ValidUntil)
+		if day.Before(validFrom) {
+			return ValidationError("valid_until", "must not be before valid_from")
+		}
+		validUntil = &day
+	}
+	if req.TeacherID != nil {
+		user, err := s.users.GetByID(*req.TeacherID)
+		if err != nil {
+			return translateDBError(err, ErrUnknownTeacher, nil)
+		}
+		if user.Role != models.RoleTeacher && user.Role != models.RoleAdmin {
+			return ErrUnknownTeacher
+		}
+	}
+
+	slot.Weekday = int(parseWeekday(req.Weekday))
+	slot.StartTime = req.StartTime
+	slot.EndTime = req.EndTime
+	slot.Room = req.Room
+	slot.TeacherID = req.TeacherID
+	slot.LateAfterMinutes = req.LateAfterMinutes
+	slot.ValidFrom = validFrom
+	slot.ValidUntil = validUntil
+	return nil
+}
+
+// generateUpcoming creates the sessions of one slot over the next sessionHorizonDays days
+func (s *timetableService) generateUpcoming(slot models.TimetableSlot) error {
+	from := today()
+	to := from.AddDate(0, 0, sessionHorizonDays-1)
+	off, err := s.calendar.NonSchoolDays(from, to)
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.CreateSessions(sessionsOf(slot, from, to, off))
+	return err
+}
+
+// sessionsOf lists the sessions of slot on the days of [from, to] it is valid on, except the days in off
+func sessionsOf(slot models.TimetableSlot, from, to time.Time, off map[time.Time]string) []models.ClassSession {
+	if slot.ValidFrom.After(from) {
+		from = slot.ValidFrom
+	}
+	if slot.ValidUntil != nil && slot.ValidUntil.Before(to) {
+		to = *slot.ValidUntil
+	}
+	// first matching weekday on or after from
+	day := from.AddDate(0, 0, (slot.Weekday-int(from.Weekday())+7)%7)
+
+	var sessions []models.ClassSession
+	for ; !day.After(to); day = day.AddDate(0, 0, 7) {
+		if _, closed := off[day]; closed {
+			continue
+		}
+		slotID := slot.ID
+		sessions = append(sessions, models.ClassSession{
+			SectionID:        slot.SectionID,
+			SlotID:           &slotID,
+			Date:             day,
+			StartTime:        slot.StartTime,
+			EndTime:          slot.EndTime,
+			Room:             slot.Room,
+			TeacherID:        slot.TeacherID,
+			LateAfterMinutes: slot.LateAfterMinutes,
+		})
+	}
+	return sessions
+}
+
+// parseWeekday maps a lower case English day name (as accepted by the request binding) to time.Weekday
+func parseWeekday(name string) time.Weekday {
+	day, _ := weekdayNamed(name)
+	return day
+}
+
+// weekdayNamed matches an English day name, ignoring case
+func weekdayNamed(name string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), name) {
+			return d, true
+		}
+	}
+	return time.Sunday, false
+}
+
+func today() time.Time {
+	return truncateToDay(time.Now())
+}
+
+func toSlotResponse(slot models.TimetableSlot) viewmodels.TimetableSlotResponse {
+	return viewmodels.TimetableSlotResponse{
+		ID:               slot.ID,
+		SectionID:        slot.SectionID,
+		CourseCode:       slot.Section.Course.Code,
+		SectionName:      slot.Section.Name,
+		Weekday:          strings.ToLower(time.Weekday(slot.Weekday).String()),
+		StartTime:        slot.StartTime,
+		EndTime:          slot.EndTime,
+		Room:             slot.Room,
+		TeacherID:        slot.TeacherID,
+		LateAfterMinutes: slot.LateAfterMinutes,
+		ValidFrom:        slot.ValidFrom,
+		ValidUntil:       slot.ValidUntil,
+	}
+}
+
+func toSessionResponse(session models.ClassSession) viewmodels.ClassSessionResponse {
+	return viewmodels.ClassSessionResponse{
+		ID:               session.ID,
+		SectionID:        session.SectionID,
+		CourseCode:       session.Section.Course.Code,
+		SectionName:      session.Section.Name,
+		SlotID:           session.SlotID,
+		Date:             session.Date,
+		StartTime:        session.StartTime,
+		EndTime:          session.EndTime,
+		Room:             session.Room,
+		TeacherID:        session.TeacherID,
+		LateAfterMinutes: session.LateAfterMinutes,
+		Cancelled:        session.Cancelled,
+	}
+}
@@ -0,0 +1,201 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// --- Mock Timetable Repo ---
+type MockTimetableRepo struct {
+	mock.Mock
+}
+
+func (m *MockTimetableRepo) CreateSlot(slot *models.TimetableSlot) error {
+	args := m.Called(slot)
+	return args.Error(0)
+}
+
+func (m *MockTimetableRepo) GetSlots(sectionID uint) ([]models.TimetableSlot, error) {
+	args := m.Called(sectionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TimetableSlot), args.Error(1)
+}
+
+func (m *MockTimetableRepo) GetSlotsValidBetween(from, to time.Time) ([]models.TimetableSlot, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TimetableSlot), args.Error(1)
+}
+
+func (m *MockTimetableRepo) GetSlotByID(id uint) (*models.TimetableSlot, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TimetableSlot), args.Error(1)
+}
+
+func (m *MockTimetableRepo) UpdateSlot(slot *models.TimetableSlot, from time.Time) error {
+	args := m.Called(slot, from)
+	return args.Error(0)
+}
+
+func (m *MockTimetableRepo) DeleteSlot(id uint, from time.Time) error {
+	args := m.Called(id, from)
+	return args.Error(0)
+}
+
+func (m *MockTimetableRepo) CreateSessions(sessions []models.ClassSession) (int, error) {
+	args := m.Called(sessions)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTimetableRepo) ListSessions(filter repository.SessionFilter) ([]models.ClassSession, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ClassSession), args.Error(1)
+}
+
+func (m *MockTimetableRepo) GetSessionByID(id uint) (*models.ClassSession, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ClassSession), args.Error(1)
+}
+
+func (m *MockTimetableRepo) SetCancelled(id uint, cancelled bool) error {
+	args := m.Called(id, cancelled)
+	return args.Error(0)
+}
+
+// --- Tests ---
+
+func TestCreateSlot(t *testing.T) {
+	mockRepo := new(MockTimetableRepo)
+	mockSections := new(MockSectionRepo)
+	mockUsers := new(MockUserRepo)
+	service := services.NewTimetableService(mockRepo, mockSections, mockUsers, fakeCalendar{})
+
+	teacherID := uint(2)
+	req := viewmodels.CreateTimetableSlotRequest{SectionID: 3, TimetableSlotRequest: viewmodels.TimetableSlotRequest{
+		Weekday: "monday", StartTime: "09:00", EndTime: "10:30", Room: "B12", TeacherID: &teacherID,
+		ValidFrom: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+	}}
+	section := &models.Section{ID: 3, Name: "A", Course: models.Course{Code: "CS101"}}
+
+	// Case 1: Success, upcoming sessions are generated right away
+	mockSections.On("GetByID", uint(3)).Return(section, nil)
+	mockUsers.On("GetByID", uint(2)).Return(&models.User{Role: models.RoleTeacher}, nil).Once()
+	mockRepo.On("CreateSlot", mock.MatchedBy(func(s *models.TimetableSlot) bool {
+		return s.Weekday == int(time.Monday) && s.SectionID == 3
+	})).Return(nil).Once()
+	mockRepo.On("CreateSessions", mock.MatchedBy(func(sessions []models.ClassSession) bool {
+		return len(sessions) == 2 && sessions[0].Date.Weekday() == time.Monday && sessions[0].Room == "B12"
+	})).Return(2, nil).Once()
+	resp, err := service.CreateSlot(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "monday", resp.Weekday)
+	assert.Equal(t, "CS101", resp.CourseCode)
+
+	// Case 2: Ends before it starts
+	bad := req
+	bad.EndTime = "08:00"
+	_, err = service.CreateSlot(bad)
+	assert.ErrorIs(t, err, services.ErrValidation)
+
+	// Case 3: Teacher is a student account
+	mockUsers.On("GetByID", uint(2)).Return(&models.User{Role: models.RoleStudent}, nil).Once()
+	_, err = service.CreateSlot(req)
+	assert.ErrorIs(t, err, services.ErrUnknownTeacher)
+
+	// Case 4: Unknown section
+	mockSections.On("GetByID", uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+	bad = req
+	bad.SectionID = 9
+	_, err = service.CreateSlot(bad)
+	assert.ErrorIs(t, err, services.ErrUnknownSection)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGenerateSessions(t *testing.T) {
+	mockRepo := new(MockTimetableRepo)
+	service := services.NewTimetableService(mockRepo, new(MockSectionRepo), new(MockUserRepo), fakeCalendar{})
+
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC) // a Monday
+	to := time.Date(2025, 12, 14, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC)
+	slots := []models.TimetableSlot{
+		// Mondays, starting after the first one
+		{ID: 1, SectionID: 3, Weekday: int(time.Monday), StartTime: "09:00", EndTime: "10:00", ValidFrom: time.Date(2025, 12, 2, 0, 0, 0, 0, time.UTC)},
+		// Wednesdays, ending within the first week
+		{ID: 2, SectionID: 4, Weekday: int(time.Wednesday), StartTime: "14:00", EndTime: "15:00", LateAfterMinutes: 10, ValidFrom: from, ValidUntil: &until},
+	}
+
+	// Case 1: Only the days each slot is valid on
+	var created []models.ClassSession
+	mockRepo.On("GetSlotsValidBetween", from, to).Return(slots, nil).Once()
+	mockRepo.On("CreateSessions", mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(0).([]models.ClassSession)
+	}).Return(1, nil).Once()
+	resp, err := service.GenerateSessions(from.Add(10*time.Hour), to)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.Created)
+	assert.Len(t, created, 2)
+	assert.Equal(t, time.Date(2025, 12, 8, 0, 0, 0, 0, time.UTC), created[0].Date)
+	assert.Equal(t, uint(1), *created[0].SlotID)
+	assert.Equal(t, time.Date(2025, 12, 3, 0, 0, 0, 0, time.UTC), created[1].Date)
+	assert.Equal(t, "14:00", created[1].StartTime)
+	assert.Equal(t, 10, created[1].LateAfterMinutes)
+
+	// Case 2: No sessions on days without classes
+	holidays := services.NewTimetableService(mockRepo, new(MockSectionRepo), new(MockUserRepo), fakeCalendar{
+		time.Date(2025, 12, 8, 0, 0, 0, 0, time.UTC): "holiday: Immaculate Conception",
+	})
+	mockRepo.On("GetSlotsValidBetween", from, to).Return(slots, nil).Once()
+	mockRepo.On("CreateSessions", mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(0).([]models.ClassSession)
+	}).Return(1, nil).Once()
+	_, err = holidays.GenerateSessions(from, to)
+	assert.NoError(t, err)
+	assert.Len(t, created, 1)
+	assert.Equal(t, uint(2), *created[0].SlotID)
+
+	// Case 3: Reversed or too long range
+	_, err = service.GenerateSessions(to, from)
+	assert.ErrorIs(t, err, services.ErrValidation)
+	_, err = service.GenerateSessions(from, from.AddDate(1, 1, 0))
+	assert.ErrorIs(t, err, services.ErrValidation)
+}
+
+func TestSetSessionCancelled(t *testing.T) {
+	mockRepo := new(MockTimetableRepo)
+	service := services.NewTimetableService(mockRepo, new(MockSectionRepo), new(MockUserRepo), fakeCalendar{})
+
+	// Case 1: Cancelled
+	mockRepo.On("SetCancelled", uint(5), true).Return(nil).Once()
+	mockRepo.On("GetSessionByID", uint(5)).Return(&models.ClassSession{ID: 5, Cancelled: true}, nil).Once()
+	resp, err := service.SetSessionCancelled(5, true)
+	assert.NoError(t, err)
+	assert.True(t, resp.Cancelled)
+
+	// Case 2: Not found
+	mockRepo.On("SetCancelled", uint(9), true).Return(gorm.ErrRecordNotFound).Once()
+	_, err = service.SetSessionCancelled(9, true)
+	assert.ErrorIs(t, err, services.ErrSessionNotFound)
+}
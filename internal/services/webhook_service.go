@@ -0,0 +1,373 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
+	"hrms_backend/internal/viewmodels"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrWebhookNotFound is returned when a webhook subscription ID does not exist.
+	ErrWebhookNotFound = &Error{Kind: ErrNotFound, Code: "webhook_not_found", Message: "webhook not found"}
+	// ErrDeliveryNotFound is returned when a webhook delivery ID does not exist.
+	ErrDeliveryNotFound = &Error{Kind: ErrNotFound, Code: "delivery_not_found", Message: "webhook delivery not found"}
+	// ErrDeliverySucceeded is returned when retrying a delivery the receiver already accepted.
+	ErrDeliverySucceeded = &Error{Kind: ErrConflict, Code: "delivery_succeeded", Message: "webhook delivery already succeeded"}
+)
+
+const (
+	// MaxDeliveryAttempts is how many times a delivery is tried before it is marked failed
+	MaxDeliveryAttempts = 10
+	// the first retry waits deliveryBackoff, every next one twice as long, up to maxDeliveryBackoff
+	deliveryBackoff    = 30 * time.Second
+	maxDeliveryBackoff = 6 * time.Hour
+	// deliveries sent per run of the delivery job
+	deliveryBatchSize = 100
+)
+
+// EventPublisher passes changes on to the webhooks subscribed to them. The outbox dispatcher calls
+// it and retries an event until it succeeds, so publishing the same event ID twice must be harmless.
+type EventPublisher interface {
+	Publish(event WebhookEvent) error
+}
+
+// WebhookEvent is the JSON body of every delivery
+type WebhookEvent struct {
+	ID        string    `json:"id"` // the outbox event ID, same for every subscription the event is sent to
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+type WebhookService interface {
+	EventPublisher
+	CreateWebhook(req viewmodels.WebhookRequest) (*viewmodels.WebhookResponse, error)
+	ListWebhooks() ([]viewmodels.WebhookResponse, error)
+	GetWebhook(id uint) (*viewmodels.WebhookResponse, error)
+	UpdateWebhook(id uint, req viewmodels.WebhookRequest) (*viewmodels.WebhookResponse, error)
+	DeleteWebhook(id uint) error
+	ListDeliveries(subscriptionID uint, query viewmodels.WebhookDeliveryQuery) (*viewmodels.WebhookDeliveryListResponse, error)
+	RetryDelivery(id uint) (*viewmodels.WebhookDeliveryResponse, error)
+	DeliverDue() (*viewmodels.WebhookRunResponse, error)
+}
+
+type webhookService struct {
+	repo   repository.WebhookRepository
+	client *http.Client
+}
+
+// NewWebhookService sends deliveries with client, or with a client timing out after 10 seconds if nil
+func NewWebhookService(repo repository.WebhookRepository, client *http.Client) WebhookService {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &webhookService{repo: repo, client: client}
+}
+
+// CreateWebhook subscribes a URL to events. The secret is generated when left out; either way
+// this is the only response it is returned in.
+func (s *webhookService) CreateWebhook(req viewmodels.WebhookRequest) (*viewmodels.WebhookResponse, error) {
+	sub := models.WebhookSubscription{URL: req.URL, Secret: req.Secret, Events: uniqueEvents(req.Events), Active: true}
+	if sub.Secret == "" {
+		sub.Secret = randomHex(32)
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	if err := s.repo.CreateSubscription(&sub); err != nil {
+		return nil, err
+	}
+	resp := toWebhookResponse(sub)
+	resp.Secret = sub.Secret
+	return &resp, nil
+}
+
+func (s *webhookService) ListWebhooks() ([]viewmodels.WebhookResponse, error) {
+	subs, err := s.repo.GetSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]viewmodels.WebhookResponse, 0, len(subs))
+	for _, sub := range subs {
+		responses = append(responses, toWebhookResponse(sub))
+	}
+	return responses, nil
+}
+
+func (s *webhookService) GetWebhook(id uint) (*viewmodels.WebhookResponse, error) {
+	sub, err := s.repo.GetSubscriptionByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrWebhookNotFound, nil)
+	}
+	resp := toWebhookResponse(*sub)
+	return &resp, nil
+}
+
+// UpdateWebhook replaces the URL and events; the secret and active flag only change when given.
+// Deliveries already queued keep the payload they were queued with but go to the new URL.
+func (s *webhookService) UpdateWebhook(id uint, req viewmodels.WebhookRequest) (*viewmodels.WebhookResponse, error) {
+	sub, err := s.repo.GetSubscriptionByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrWebhookNotFound, nil)
+	}
+	sub.URL = req.URL
+	sub.Events = uniqueEvents(req.Events)
+	if req.Secret != "" {
+		sub.Secret = req.Secret
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	if err := s.repo.UpdateSubscription(sub); err != nil {
+		return nil, translateDBError(err, ErrWebhookNotFound, nil)
+	}
+	resp := toWebhookResponse(*sub)
+	return &resp, nil
+}
+
+// DeleteWebhook removes a subscription together with its delivery log
+func (s *webhookService) DeleteWebhook(id uint) error {
+	return translateDBError(s.repo.DeleteSubscription(id), ErrWebhookNotFound, nil)
+}
+
+// ListDeliveries returns a page of a subscription's delivery log, newest first
+func (s *webhookService) ListDeliveries(subscriptionID uint, query viewmodels.WebhookDeliveryQuery) (*viewmodels.WebhookDeliveryListResponse, error) {
+	if _, err := s.repo.GetSubscriptionByID(subscriptionID); err != nil {
+		return nil, translateDBError(err, ErrWebhookNotFound, nil)
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 {
+		query.Limit = 20
+	}
+
+	deliveries, total, err := s.repo.ListDeliveries(repository.DeliveryFilter{
+		SubscriptionID: subscriptionID,
+		Status:         query.Status,
+		Event:          query.Event,
+		Limit:          query.Limit,
+		Offset:         (query.Page - 1) * query.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]viewmodels.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, d := range deliveries {
+		data = append(data, toDeliveryResponse(d))
+	}
+	return &viewmodels.WebhookDeliveryListResponse{Data: data, Total: total, Page: query.Page, Limit: query.Limit}, nil
+}
+
+// RetryDelivery attempts a pending or failed delivery right away, e.g. once the receiver is fixed.
+// A failed delivery that fails again stays failed; a pending one keeps its backoff schedule.
+func (s *webhookService) RetryDelivery(id uint) (*viewmodels.WebhookDeliveryResponse, error) {
+	delivery, err := s.repo.GetDeliveryByID(id)
+	if err != nil {
+		return nil, translateDBError(err, ErrDeliveryNotFound, nil)
+	}
+	if delivery.Status == models.DeliverySucceeded {
+		return nil, ErrDeliverySucceeded
+	}
+	sub, err := s.repo.GetSubscriptionByID(delivery.SubscriptionID)
+	if err != nil {
+		return nil, translateDBError(err, ErrWebhookNotFound, nil)
+	}
+	delivery.Subscription = *sub
+
+	if err := s.attempt(delivery); err != nil {
+		return nil, err
+	}
+	resp := toDeliveryResponse(*delivery)
+	return &resp, nil
+}
+
+// Publish queues a delivery of the event to every active subscription that asked for it.
+// The delivery job sends them. Subscriptions the event is already queued for are skipped.
+func (s *webhookService) Publish(event WebhookEvent) error {
+	subs, err := s.repo.GetActiveSubscriptions()
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var deliveries []models.WebhookDelivery
+	for _, sub := range subs {
+		if !slices.Contains(sub.Events, event.Event) {
+			continue
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			Event:          event.Event,
+			Payload:        string(payload),
+			Status:         models.DeliveryPending,
+			NextAttemptAt:  now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return s.repo.CreateDeliveries(deliveries)
+}
+
+// DeliverDue sends the deliveries whose next attempt is due, oldest first. Receivers failing is
+// not an error of the run: their deliveries are rescheduled, or marked failed once out of attempts.
+func (s *webhookService) DeliverDue() (*viewmodels.WebhookRunResponse, error) {
+	due, err := s.repo.GetDueDeliveries(time.Now(), deliveryBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &viewmodels.WebhookRunResponse{}
+	for i := range due {
+		if err := s.attempt(&due[i]); err != nil {
+			return resp, err
+		}
+		resp.Attempted++
+		switch due[i].Status {
+		case models.DeliverySucceeded:
+			resp.Succeeded++
+		case models.DeliveryFailed:
+			resp.Failed++
+		default:
+			resp.Retrying++
+		}
+	}
+	return resp, nil
+}
+
+// attempt sends the delivery once and records the outcome; the error is only about recording it
+func (s *webhookService) attempt(delivery *models.WebhookDelivery) error {
+	status, err := s.send(delivery)
+	now := time.Now()
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+	if err == nil {
+		delivery.Status = models.DeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	} else {
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= MaxDeliveryAttempts {
+			delivery.Status = models.DeliveryFailed
+		} else {
+			delivery.NextAttemptAt = now.Add(retryDelay(delivery.Attempts))
+		}
+	}
+	return s.repo.UpdateDelivery(delivery)
+}
+
+// send posts the payload, signed with the subscription's secret, and returns the response status.
+// Anything but a 2xx answer is a failure.
+func (s *webhookService) send(delivery *models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, delivery.Subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "hrms-webhooks/1.0")
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-ID", delivery.EventID)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", SignWebhook(delivery.Subscription.Secret, timestamp, body))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("receiver answered %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
+
+// SignWebhook returns the X-Webhook-Signature of a delivery: "sha256=" and the hex HMAC-SHA256,
+// keyed with the subscription's secret, of the X-Webhook-Timestamp, a dot and the body.
+// Receivers compute the same to check the delivery is genuine and recent.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryDelay is the wait after the given number of failed attempts: 30s, 1m, 2m, ... up to 6h
+func retryDelay(attempts int) time.Duration {
+	delay := deliveryBackoff
+	for i := 1; i < attempts && delay < maxDeliveryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDeliveryBackoff)
+}
+
+// uniqueEvents drops repeated events, keeping the order
+func uniqueEvents(events []string) []string {
+	unique := make([]string, 0, len(events))
+	for _, e := range events {
+		if !slices.Contains(unique, e) {
+			unique = append(unique, e)
+		}
+	}
+	return unique
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func toWebhookResponse(sub models.WebhookSubscription) viewmodels.WebhookResponse {
+	return viewmodels.WebhookResponse{
+		ID:        sub.ID,
+		URL:       sub.URL,
+		Events:    sub.Events,
+		Active:    sub.Active,
+		CreatedAt: sub.CreatedAt,
+		UpdatedAt: sub.UpdatedAt,
+	}
+}
+
+func toDeliveryResponse(d models.WebhookDelivery) viewmodels.WebhookDeliveryResponse {
+	resp := viewmodels.WebhookDeliveryResponse{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		Event:          d.Event,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.Status == models.DeliveryPending {
+		next := d.NextAttemptAt
+		resp.NextAttemptAt = &next
+	}
+	return resp
+}
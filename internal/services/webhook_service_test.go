@@ -0,0 +1,268 @@
+package services_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hrms_backend/internal/models"
+	"hrms_backend/internal/repository"
+	"hrms_backend/internal/services"
+	"hrms_backend/internal/viewmodels"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// --- Mock Webhook Repo ---
+type MockWebhookRepo struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepo) CreateSubscription(sub *models.WebhookSubscription) error {
+	args := m.Called(sub)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) GetSubscriptions() ([]models.WebhookSubscription, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepo) GetSubscriptionByID(id uint) (*models.WebhookSubscription, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepo) GetActiveSubscriptions() ([]models.WebhookSubscription, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepo) UpdateSubscription(sub *models.WebhookSubscription) error {
+	args := m.Called(sub)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) DeleteSubscription(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) CreateDeliveries(deliveries []models.WebhookDelivery) error {
+	args := m.Called(deliveries)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) GetDueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	args := m.Called(now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepo) GetDeliveryByID(id uint) (*models.WebhookDelivery, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepo) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) ListDeliveries(filter repository.DeliveryFilter) ([]models.WebhookDelivery, int64, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]models.WebhookDelivery), args.Get(1).(int64), args.Error(2)
+}
+
+// --- Tests ---
+
+func TestCreateWebhook(t *testing.T) {
+	mockRepo := new(MockWebhookRepo)
+	service := services.NewWebhookService(mockRepo, nil)
+	req := viewmodels.WebhookRequest{URL: "https://lms.test/hook", Events: []string{models.EventStudentCreated, models.EventStudentCreated}}
+
+	// Case 1: Secret generated, repeated events dropped, active by default
+	mockRepo.On("CreateSubscription", mock.MatchedBy(func(s *models.WebhookSubscription) bool {
+		return len(s.Secret) == 64 && len(s.Events) == 1 && s.Active
+	})).Return(nil).Once()
+	resp, err := service.CreateWebhook(req)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Secret, 64)
+
+	// Case 2: Secret given, created inactive
+	inactive := false
+	req.Secret, req.Active = "0123456789abcdef", &inactive
+	mockRepo.On("CreateSubscription", mock.MatchedBy(func(s *models.WebhookSubscription) bool {
+		return s.Secret == "0123456789abcdef" && !s.Active
+	})).Return(nil).Once()
+	resp, err = service.CreateWebhook(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "0123456789abcdef", resp.Secret)
+
+	// Case 3: Reading it back does not reveal the secret
+	mockRepo.On("GetSubscriptionByID", uint(1)).Return(&models.WebhookSubscription{ID: 1, Secret: "0123456789abcdef"}, nil).Once()
+	resp, err = service.GetWebhook(1)
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Secret)
+
+	// Case 4: Unknown subscription
+	mockRepo.On("GetSubscriptionByID", uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.UpdateWebhook(9, req)
+	assert.ErrorIs(t, err, services.ErrWebhookNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPublish(t *testing.T) {
+	mockRepo := new(MockWebhookRepo)
+	service := services.NewWebhookService(mockRepo, nil)
+	mockRepo.On("GetActiveSubscriptions").Return([]models.WebhookSubscription{
+		{ID: 1, Events: []string{models.EventStudentCreated, models.EventAttendanceMarked}},
+		{ID: 2, Events: []string{models.EventStudentDeleted}},
+		{ID: 3, Events: []string{models.EventAttendanceMarked}},
+	}, nil)
+
+	// Case 1: One delivery per subscription to the event, sharing the event ID and payload
+	var queued []models.WebhookDelivery
+	mockRepo.On("CreateDeliveries", mock.Anything).Run(func(args mock.Arguments) {
+		queued = args.Get(0).([]models.WebhookDelivery)
+	}).Return(nil).Once()
+	err := service.Publish(services.WebhookEvent{ID: "42", Event: models.EventAttendanceMarked, Data: viewmodels.AttendanceResponse{ID: 5, Status: "present"}})
+	assert.NoError(t, err)
+	if assert.Len(t, queued, 2) {
+		assert.Equal(t, []uint{1, 3}, []uint{queued[0].SubscriptionID, queued[1].SubscriptionID})
+		assert.Equal(t, "42", queued[0].EventID)
+		assert.Equal(t, "42", queued[1].EventID)
+		assert.Equal(t, models.DeliveryPending, queued[0].Status)
+
+		var body struct {
+			ID    string                        `json:"id"`
+			Event string                        `json:"event"`
+			Data  viewmodels.AttendanceResponse `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(queued[0].Payload), &body))
+		assert.Equal(t, "42", body.ID)
+		assert.Equal(t, models.EventAttendanceMarked, body.Event)
+		assert.Equal(t, uint(5), body.Data.ID)
+	}
+
+	// Case 2: Nobody subscribed: nothing queued
+	assert.NoError(t, service.Publish(services.WebhookEvent{ID: "43", Event: models.EventStudentPurged, Data: map[string]uint{"id": 1}}))
+	mockRepo.AssertNumberOfCalls(t, "CreateDeliveries", 1)
+
+	// Case 3: Queuing fails: reported, so the outbox tries again
+	mockRepo.On("CreateDeliveries", mock.Anything).Return(errors.New("db down")).Once()
+	assert.Error(t, service.Publish(services.WebhookEvent{ID: "44", Event: models.EventAttendanceMarked}))
+}
+
+func TestDeliverDue(t *testing.T) {
+	var got *http.Request
+	var gotBody []byte
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	mockRepo := new(MockWebhookRepo)
+	service := services.NewWebhookService(mockRepo, server.Client())
+	sub := models.WebhookSubscription{ID: 1, URL: server.URL, Secret: "0123456789abcdef", Active: true}
+	delivery := func(attempts int) models.WebhookDelivery {
+		return models.WebhookDelivery{ID: 7, SubscriptionID: 1, Subscription: sub, EventID: "abc", Event: models.EventStudentCreated,
+			Payload: `{"id":"abc","event":"student.created"}`, Status: models.DeliveryPending, Attempts: attempts}
+	}
+	var saved *models.WebhookDelivery
+	mockRepo.On("UpdateDelivery", mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(0).(*models.WebhookDelivery)
+	}).Return(nil)
+
+	// Case 1: Delivered, signed with the subscription's secret
+	mockRepo.On("GetDueDeliveries", mock.Anything, mock.Anything).Return([]models.WebhookDelivery{delivery(0)}, nil).Once()
+	resp, err := service.DeliverDue()
+	assert.NoError(t, err)
+	assert.Equal(t, viewmodels.WebhookRunResponse{Attempted: 1, Succeeded: 1}, *resp)
+	assert.Equal(t, models.EventStudentCreated, got.Header.Get("X-Webhook-Event"))
+	assert.Equal(t, "7", got.Header.Get("X-Webhook-Delivery"))
+	timestamp := got.Header.Get("X-Webhook-Timestamp")
+	assert.Equal(t, services.SignWebhook("0123456789abcdef", timestamp, gotBody), got.Header.Get("X-Webhook-Signature"))
+	assert.Equal(t, models.DeliverySucceeded, saved.Status)
+	assert.Equal(t, 1, saved.Attempts)
+	assert.NotNil(t, saved.DeliveredAt)
+
+	// Case 2: Receiver fails: retried later, with a longer wait after every failure
+	status = http.StatusBadGateway
+	mockRepo.On("GetDueDeliveries", mock.Anything, mock.Anything).Return([]models.WebhookDelivery{delivery(0)}, nil).Once()
+	resp, err = service.DeliverDue()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.Retrying)
+	assert.Equal(t, models.DeliveryPending, saved.Status)
+	assert.Equal(t, http.StatusBadGateway, saved.ResponseStatus)
+	assert.Contains(t, saved.LastError, "502")
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), saved.NextAttemptAt, 5*time.Second)
+
+	mockRepo.On("GetDueDeliveries", mock.Anything, mock.Anything).Return([]models.WebhookDelivery{delivery(3)}, nil).Once()
+	_, err = service.DeliverDue()
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(4*time.Minute), saved.NextAttemptAt, 5*time.Second)
+
+	// Case 3: Last attempt fails: the delivery is marked failed
+	mockRepo.On("GetDueDeliveries", mock.Anything, mock.Anything).Return([]models.WebhookDelivery{delivery(services.MaxDeliveryAttempts - 1)}, nil).Once()
+	resp, err = service.DeliverDue()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.Failed)
+	assert.Equal(t, models.DeliveryFailed, saved.Status)
+	assert.Equal(t, services.MaxDeliveryAttempts, saved.Attempts)
+}
+
+func TestRetryDelivery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	mockRepo := new(MockWebhookRepo)
+	service := services.NewWebhookService(mockRepo, server.Client())
+
+	// Case 1: A failed delivery succeeds once the receiver is back
+	mockRepo.On("GetDeliveryByID", uint(7)).Return(&models.WebhookDelivery{ID: 7, SubscriptionID: 1, Payload: "{}",
+		Status: models.DeliveryFailed, Attempts: services.MaxDeliveryAttempts}, nil).Once()
+	mockRepo.On("GetSubscriptionByID", uint(1)).Return(&models.WebhookSubscription{ID: 1, URL: server.URL, Secret: "s"}, nil).Once()
+	mockRepo.On("UpdateDelivery", mock.Anything).Return(nil).Once()
+	resp, err := service.RetryDelivery(7)
+	assert.NoError(t, err)
+	assert.Equal(t, models.DeliverySucceeded, resp.Status)
+	assert.Nil(t, resp.NextAttemptAt)
+
+	// Case 2: Already delivered
+	mockRepo.On("GetDeliveryByID", uint(8)).Return(&models.WebhookDelivery{ID: 8, Status: models.DeliverySucceeded}, nil).Once()
+	_, err = service.RetryDelivery(8)
+	assert.ErrorIs(t, err, services.ErrDeliverySucceeded)
+	assert.ErrorIs(t, err, services.ErrConflict)
+
+	// Case 3: Unknown delivery
+	mockRepo.On("GetDeliveryByID", uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.RetryDelivery(9)
+	assert.ErrorIs(t, err, services.ErrDeliveryNotFound)
+	mockRepo.AssertExpectations(t)
+}
@@ -5,14 +5,117 @@ import "time"
 type CreateAttendanceRequest struct {
 	StudentID uint      `json:"student_id" binding:"required"`
 	Date      time.Time `json:"date" binding:"required"`
-	// oneof validation ensures only valid statuses are accepted
-	Status string `json:"status" binding:"required,oneof=present absent late excused"`
+	// oneof validation ensures only valid statuses are accepted.
+	// May be left out with a section_id and a check_in_at: present or late is then derived from the class session.
+	Status string `json:"status" binding:"omitempty,oneof=present absent late excused"`
+	// Optional: records attendance for one class of the day. The student must be enrolled in it.
+	SectionID uint `json:"section_id"`
+	// Optional arrival and departure, on date
+	CheckInAt  *time.Time `json:"check_in_at"`
+	CheckOutAt *time.Time `json:"check_out_at"`
 }
 
 type AttendanceResponse struct {
-	ID          uint      `json:"id"`
-	StudentID   uint      `json:"student_id"`
-	StudentName string    `json:"student_name,omitempty"` // Optional: filled if Student is preloaded
-	Date        time.Time `json:"date"`
-	Status      string    `json:"status"`
+	ID          uint       `json:"id"`
+	StudentID   uint       `json:"student_id"`
+	StudentName string     `json:"student_name,omitempty"` // Optional: filled if Student is preloaded
+	Date        time.Time  `json:"date"`
+	Status      string     `json:"status"`
+	SectionID   *uint      `json:"section_id,omitempty"` // not set for whole day records
+	CheckInAt   *time.Time `json:"check_in_at,omitempty"`
+	CheckOutAt  *time.Time `json:"check_out_at,omitempty"`
+	LateMinutes int        `json:"late_minutes,omitempty"` // how late, for late records checked in to a class session
+}
+
+// POST /attendance/bulk.
+// Either list every student in Records, or give a DepartmentID to mark all of its
+// students with DefaultStatus, listing the students who differ in Exceptions.
+type BulkAttendanceRequest struct {
+	Date          time.Time             `json:"date" binding:"required"`
+	Records       []BulkAttendanceEntry `json:"records" binding:"dive"`
+	DepartmentID  uint                  `json:"department_id"`
+	DefaultStatus string                `json:"default_status" binding:"omitempty,oneof=present absent late excused" example:"present"`
+	Exceptions    []BulkAttendanceEntry `json:"exceptions" binding:"dive"`
+}
+
+type BulkAttendanceEntry struct {
+	StudentID uint   `json:"student_id" binding:"required"`
+	Status    string `json:"status" binding:"required,oneof=present absent late excused"`
+}
+
+// outcome of a single row in a bulk request
+type BulkAttendanceResult struct {
+	StudentID    uint   `json:"student_id"`
+	Status       string `json:"status"`
+	Success      bool   `json:"success"`
+	Created      bool   `json:"created"`                 // false when the same record already existed
+	AttendanceID uint   `json:"attendance_id,omitempty"` // set on success
+	Error        string `json:"error,omitempty"`         // set on failure
+}
+
+type BulkAttendanceResponse struct {
+	Date      time.Time              `json:"date"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Results   []BulkAttendanceResult `json:"results"`
+}
+
+// query parameters for GET /attendance.
+// Dates use the YYYY-MM-DD format and both bounds are inclusive.
+type AttendanceListQuery struct {
+	From         time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To           time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`
+	Status       string    `form:"status" binding:"omitempty,oneof=present absent late excused"`
+	DepartmentID uint      `form:"department_id"`
+	SectionID    uint      `form:"section_id"`
+	StudentID    uint      `form:"student_id"`
+	Page         int       `form:"page" binding:"omitempty,min=1"`
+	Limit        int       `form:"limit" binding:"omitempty,min=1,max=100"`
+	// next_cursor of the previous response; takes precedence over page
+	Cursor string `form:"cursor"`
+	// prefix with "-" for descending order
+	Sort string `form:"sort" binding:"omitempty,oneof=date -date student_id -student_id status -status created_at -created_at"`
+}
+
+// paged response for GET /attendance
+type AttendanceListResponse struct {
+	Data  []AttendanceResponse `json:"data"`
+	Total int64                `json:"total"`
+	Page  int                  `json:"page,omitempty"` // not set when paging with a cursor
+	Limit int                  `json:"limit"`
+	// pass as cursor to get the next page, empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// PUT /attendance/records/:id
+type UpdateAttendanceRequest struct {
+	Status string `json:"status" binding:"required,oneof=present absent late excused"`
+	Reason string `json:"reason" binding:"required,max=255" example:"marked absent by mistake, student was in the lab"`
+}
+
+// POST /attendance/records/:id/check-out
+type CheckOutRequest struct {
+	CheckOutAt time.Time `json:"check_out_at" binding:"required"`
+}
+
+// DELETE /attendance/records/:id
+type DeleteAttendanceRequest struct {
+	Reason string `json:"reason" binding:"required,max=255" example:"recorded for the wrong student"`
+}
+
+// one entry of a record's correction history
+type AttendanceCorrectionResponse struct {
+	ID             uint      `json:"id"`
+	AttendanceID   uint      `json:"attendance_id"`
+	Action         string    `json:"action"`
+	PreviousStatus string    `json:"previous_status"`
+	NewStatus      string    `json:"new_status,omitempty"`
+	Reason         string    `json:"reason"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// query parameters for GET /me/attendance/summary (YYYY-MM-DD, both optional and inclusive)
+type AttendanceSummaryQuery struct {
+	From time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To   time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`
 }
@@ -0,0 +1,37 @@
+package viewmodels
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// query parameters for GET /audit
+type AuditLogQuery struct {
+	EntityType string `form:"entity_type" binding:"omitempty,oneof=student attendance"`
+	EntityID   uint   `form:"entity_id"`
+	ActorID    uint   `form:"actor_id"`
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// GET /audit response item. Before and After hold only the changed columns.
+type AuditLogResponse struct {
+	ID         uint            `json:"id"`
+	ActorID    *uint           `json:"actor_id"` // null for system jobs
+	Action     string          `json:"action" example:"update"`
+	EntityType string          `json:"entity_type" example:"attendance"`
+	EntityID   uint            `json:"entity_id"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	RequestID  string          `json:"request_id,omitempty"`
+	IP         string          `json:"ip,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// paged response for GET /audit
+type AuditLogListResponse struct {
+	Data  []AuditLogResponse `json:"data"`
+	Total int64              `json:"total"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+}
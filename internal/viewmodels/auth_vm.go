@@ -0,0 +1,38 @@
+package viewmodels
+
+import "time"
+
+// POST /auth/login
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// POST /auth/refresh
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// issued by login and refresh
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int    `json:"expires_in" example:"900"` // access token lifetime in seconds
+}
+
+// POST /users (admin only). StudentID is required for the student role.
+type CreateUserRequest struct {
+	Email     string `json:"email" binding:"required,email"`
+	Password  string `json:"password" binding:"required,min=8"`
+	Role      string `json:"role" binding:"required,oneof=admin teacher student"`
+	StudentID *uint  `json:"student_id" binding:"required_if=Role student"`
+}
+
+type UserResponse struct {
+	ID        uint      `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	StudentID *uint     `json:"student_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
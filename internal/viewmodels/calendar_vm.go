@@ -0,0 +1,53 @@
+package viewmodels
+
+import "time"
+
+// POST /calendar/terms and PUT /calendar/terms/:id
+type TermRequest struct {
+	Name      string    `json:"name" binding:"required,max=100" example:"Fall 2025"`
+	StartDate time.Time `json:"start_date" binding:"required"`
+	EndDate   time.Time `json:"end_date" binding:"required"` // inclusive
+}
+
+type TermResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+// POST /calendar/closures and PUT /calendar/closures/:id
+type ClosureRequest struct {
+	Kind      string    `json:"kind" binding:"required,oneof=holiday closure" example:"holiday"`
+	Name      string    `json:"name" binding:"required,max=100" example:"Winter break"`
+	StartDate time.Time `json:"start_date" binding:"required"`
+	EndDate   time.Time `json:"end_date" binding:"required"` // inclusive, same as start_date for a single day
+}
+
+type ClosureResponse struct {
+	ID        uint      `json:"id"`
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+// query parameters for GET /calendar and GET /calendar/closures (YYYY-MM-DD, both inclusive)
+type CalendarQuery struct {
+	From time.Time `form:"from" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	To   time.Time `form:"to" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+}
+
+// one day of GET /calendar
+type CalendarDayResponse struct {
+	Date      time.Time `json:"date"`
+	SchoolDay bool      `json:"school_day"`
+	// why there are no classes, e.g. "weekend" or "holiday: Winter break"
+	Reason string `json:"reason,omitempty"`
+}
+
+// query parameters for GET /calendar/closures (YYYY-MM-DD, both optional)
+type ClosureQuery struct {
+	From time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To   time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`
+}
@@ -0,0 +1,40 @@
+package viewmodels
+
+import "time"
+
+// POST /courses and PUT /courses/:id
+type CourseRequest struct {
+	Code string `json:"code" binding:"required,max=20" example:"CS101"`
+	Name string `json:"name" binding:"required,max=150" example:"Introduction to Programming"`
+}
+
+type CourseResponse struct {
+	ID        uint      `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// POST /courses/:id/sections and PUT /sections/:id
+type SectionRequest struct {
+	Name string `json:"name" binding:"required,max=50" example:"A"`
+}
+
+type SectionResponse struct {
+	ID         uint      `json:"id"`
+	CourseID   uint      `json:"course_id"`
+	CourseCode string    `json:"course_code"`
+	Name       string    `json:"name"`
+	Students   int64     `json:"students"` // enrolled students, deleted ones left out
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// POST /sections/:id/enrollments
+type EnrollStudentsRequest struct {
+	StudentIDs []uint `json:"student_ids" binding:"required,min=1,max=500"`
+}
+
+type EnrollStudentsResponse struct {
+	Enrolled        int `json:"enrolled"`         // new enrollments
+	AlreadyEnrolled int `json:"already_enrolled"` // students that were enrolled before, left as is
+}
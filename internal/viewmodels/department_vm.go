@@ -0,0 +1,45 @@
+package viewmodels
+
+import "time"
+
+// POST /departments and PUT /departments/:id
+type DepartmentRequest struct {
+	Name string `json:"name" binding:"required,max=100" example:"Computer Science"`
+}
+
+type DepartmentResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Students  int64     `json:"students"` // students currently in the department, deleted ones left out
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// POST /departments/:id/merge
+type MergeDepartmentRequest struct {
+	IntoID uint `json:"into_id" binding:"required"`
+}
+
+type MergeDepartmentResponse struct {
+	Department    DepartmentResponse `json:"department"` // the department merged into
+	MovedStudents int                `json:"moved_students"`
+}
+
+// attendance counts of one department's students over a period
+type DepartmentAttendanceResponse struct {
+	DepartmentID   uint    `json:"department_id"`
+	DepartmentName string  `json:"department_name"`
+	Students       int     `json:"students"` // students with at least one record in the period
+	Present        int     `json:"present"`
+	Absent         int     `json:"absent"`
+	Late           int     `json:"late"`
+	LateMinutes    int     `json:"late_minutes"` // summed over the late records
+	Excused        int     `json:"excused"`
+	Total          int     `json:"total"`
+	Percentage     float64 `json:"percentage" example:"87.5"`
+}
+
+// query parameters for the department attendance summaries (YYYY-MM-DD, both inclusive)
+type DepartmentAttendanceQuery struct {
+	From time.Time `form:"from" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	To   time.Time `form:"to" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+}
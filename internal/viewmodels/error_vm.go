@@ -2,5 +2,13 @@ package viewmodels
 
 // ErrorResponse represents a standard error response.
 type ErrorResponse struct {
-	Error string `json:"error" example:"a description of the error"`
+	Code    string       `json:"code" example:"student_not_found"`
+	Message string       `json:"message" example:"student not found"`
+	Details []FieldError `json:"details,omitempty"` // one entry per invalid field
+}
+
+// FieldError describes why one request field was rejected.
+type FieldError struct {
+	Field   string `json:"field" example:"status"`
+	Message string `json:"message" example:"must be one of: present, absent, late, excused"`
 }
@@ -0,0 +1,26 @@
+package viewmodels
+
+import "time"
+
+// GET /jobs response item
+type JobStatusResponse struct {
+	Name           string     `json:"name" example:"weekly_report"`
+	Schedule       string     `json:"schedule" example:"@weekly"`
+	Enabled        bool       `json:"enabled"`
+	Running        bool       `json:"running"`
+	LastRunAt      *time.Time `json:"last_run_at,omitempty"`
+	LastStatus     string     `json:"last_status,omitempty" example:"success"` // "success" or "failed"
+	LastError      string     `json:"last_error,omitempty"`
+	LastDurationMs int64      `json:"last_duration_ms,omitempty"`
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"` // only known once the scheduler is started
+}
+
+// outcome of one run of the absence alert job
+type AlertRunResponse struct {
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Checked  int       `json:"checked"`  // students with attendance in the window
+	Sent     int       `json:"sent"`     // thresholds newly crossed and notified
+	Failed   int       `json:"failed"`   // could not be notified, retried next run
+	Resolved int       `json:"resolved"` // earlier alerts no longer crossed
+}
@@ -0,0 +1,44 @@
+package viewmodels
+
+import "time"
+
+// POST /me/leave-requests
+type LeaveRequestRequest struct {
+	StartDate time.Time `json:"start_date" binding:"required"`
+	EndDate   time.Time `json:"end_date" binding:"required"` // inclusive, same as start_date for a single day
+	Reason    string    `json:"reason" binding:"required,max=500" example:"Medical appointment"`
+}
+
+// POST /leave-requests, submitted by staff on behalf of a student
+type CreateLeaveRequest struct {
+	StudentID uint `json:"student_id" binding:"required"`
+	LeaveRequestRequest
+}
+
+// POST /leave-requests/:id/approve and /reject
+type ReviewLeaveRequest struct {
+	Note string `json:"note" binding:"max=255" example:"Doctor's note received"`
+}
+
+// query parameters for GET /leave-requests
+type LeaveQuery struct {
+	StudentID uint   `form:"student_id"`
+	Status    string `form:"status" binding:"omitempty,oneof=pending approved rejected cancelled"`
+}
+
+type LeaveResponse struct {
+	ID            uint       `json:"id"`
+	StudentID     uint       `json:"student_id"`
+	StudentName   string     `json:"student_name,omitempty"` // Optional: filled if Student is preloaded
+	StartDate     time.Time  `json:"start_date"`
+	EndDate       time.Time  `json:"end_date"`
+	Reason        string     `json:"reason"`
+	Status        string     `json:"status"`
+	RequestedByID *uint      `json:"requested_by_id,omitempty"`
+	ReviewedByID  *uint      `json:"reviewed_by_id,omitempty"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote    string     `json:"review_note,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	// on approval: school days now excused, whole day records created or records converted
+	ExcusedDays int `json:"excused_days,omitempty"`
+}
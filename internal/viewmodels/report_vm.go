@@ -0,0 +1,35 @@
+package viewmodels
+
+import "time"
+
+// GET /reports and GET /reports/:id responses.
+// Rows are only included when a single report is requested.
+type ReportResponse struct {
+	ID          uint                `json:"id"`
+	Type        string              `json:"type" example:"weekly"`
+	PeriodStart time.Time           `json:"period_start"`
+	PeriodEnd   time.Time           `json:"period_end"`
+	CreatedAt   time.Time           `json:"created_at"`
+	Rows        []ReportRowResponse `json:"rows,omitempty"`
+}
+
+// attendance counts of one student over the report period
+type ReportRowResponse struct {
+	StudentID   uint    `json:"student_id"`
+	StudentName string  `json:"student_name"`
+	Department  string  `json:"department"`
+	Present     int     `json:"present"`
+	Absent      int     `json:"absent"`
+	Late        int     `json:"late"`
+	LateMinutes int     `json:"late_minutes"` // summed over the late records
+	Excused     int     `json:"excused"`
+	Total       int     `json:"total"`
+	Percentage  float64 `json:"percentage" example:"87.5"`
+}
+
+// query parameters for GET /reports/attendance (dates are YYYY-MM-DD, both inclusive)
+type AttendanceExportQuery struct {
+	From   time.Time `form:"from" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	To     time.Time `form:"to" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	Format string    `form:"format" binding:"omitempty,oneof=csv xlsx pdf"`
+}
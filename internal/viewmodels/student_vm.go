@@ -5,23 +5,71 @@ import "time"
 // when creating a student.
 // `binding:"required"` tags enforce presence of the fields.
 type CreateStudentRequest struct {
-	Name       string `json:"name" binding:"required"`
-	Email      string `json:"email" binding:"required,email"`
-	Department string `json:"department" binding:"required"`
+	Name         string `json:"name" binding:"required"`
+	Email        string `json:"email" binding:"required,email"`
+	DepartmentID uint   `json:"department_id" binding:"required"`
 }
 
 // for PUT /students/:id.
 type UpdateStudentRequest struct {
-	Name       string `json:"name"`
-	Email      string `json:"email"`
-	Department string `json:"department"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	DepartmentID uint   `json:"department_id"`
 }
 
 // GET/POST responses
 type StudentResponse struct {
-	ID         uint      `json:"id"`
-	Name       string    `json:"name"`
-	Email      string    `json:"email"`
-	Department string    `json:"department"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID           uint      `json:"id"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	DepartmentID uint      `json:"department_id"`
+	Department   string    `json:"department"` // department name
+	CreatedAt    time.Time `json:"created_at"`
+	// set for soft deleted students, listed with include_deleted=true
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// POST /students/import report
+type StudentImportResponse struct {
+	DryRun  bool                 `json:"dry_run"`
+	Total   int                  `json:"total"`   // data rows in the file
+	Valid   int                  `json:"valid"`   // rows that can be (or were) imported
+	Invalid int                  `json:"invalid"` // rows skipped because of errors
+	Created int                  `json:"created"` // always 0 in a dry run
+	Errors  []StudentImportError `json:"errors"`
+}
+
+// one problem with one row of an import; a row can have several
+type StudentImportError struct {
+	Line    int    `json:"line"` // line number in the file, the header being line 1
+	Field   string `json:"field,omitempty" example:"email"`
+	Email   string `json:"email,omitempty"`
+	Message string `json:"message" example:"already used by another student"`
+}
+
+// query parameters for GET /students.
+// Dates use the YYYY-MM-DD format and both bounds are inclusive.
+type StudentListQuery struct {
+	Q              string    `form:"q"` // substring of the name or email
+	DepartmentID   uint      `form:"department_id"`
+	CreatedFrom    time.Time `form:"created_from" time_format:"2006-01-02" time_utc:"1"`
+	CreatedTo      time.Time `form:"created_to" time_format:"2006-01-02" time_utc:"1"`
+	IncludeDeleted bool      `form:"include_deleted"`
+	Page           int       `form:"page" binding:"omitempty,min=1"`
+	Limit          int       `form:"limit" binding:"omitempty,min=1,max=100"`
+	// next_cursor of the previous response; takes precedence over page
+	Cursor string `form:"cursor"`
+	// prefix with "-" for descending order
+	Sort string `form:"sort" binding:"omitempty,oneof=id -id name -name email -email department_id -department_id created_at -created_at"`
+}
+
+// paged response for GET /students
+type StudentListResponse struct {
+	Data  []StudentResponse `json:"data"`
+	Total int64             `json:"total"`
+	Page  int               `json:"page,omitempty"` // not set when paging with a cursor
+	Limit int               `json:"limit"`
+	Next  *int              `json:"next"` // next page number, null on the last page or when paging with a cursor
+	// pass as cursor to get the next page, empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
@@ -0,0 +1,79 @@
+package viewmodels
+
+import "time"
+
+// schedule of a timetable slot, PUT /timetable/slots/:id
+type TimetableSlotRequest struct {
+	Weekday   string `json:"weekday" binding:"required,oneof=sunday monday tuesday wednesday thursday friday saturday" example:"monday"`
+	StartTime string `json:"start_time" binding:"required,datetime=15:04" example:"09:00"`
+	EndTime   string `json:"end_time" binding:"required,datetime=15:04" example:"10:30"`
+	Room      string `json:"room" binding:"max=50" example:"B12"`
+	TeacherID *uint  `json:"teacher_id"` // user with the teacher or admin role
+	// check-ins more than this many minutes after start_time are late
+	LateAfterMinutes int `json:"late_after_minutes" binding:"min=0,max=240" example:"5"`
+	// first and last day the slot applies to, both inclusive; no valid_until means open ended
+	ValidFrom  time.Time  `json:"valid_from" binding:"required"`
+	ValidUntil *time.Time `json:"valid_until"`
+}
+
+// POST /timetable/slots
+type CreateTimetableSlotRequest struct {
+	SectionID uint `json:"section_id" binding:"required"`
+	TimetableSlotRequest
+}
+
+type TimetableSlotResponse struct {
+	ID               uint       `json:"id"`
+	SectionID        uint       `json:"section_id"`
+	CourseCode       string     `json:"course_code,omitempty"`
+	SectionName      string     `json:"section_name,omitempty"`
+	Weekday          string     `json:"weekday"`
+	StartTime        string     `json:"start_time"`
+	EndTime          string     `json:"end_time"`
+	Room             string     `json:"room,omitempty"`
+	TeacherID        *uint      `json:"teacher_id,omitempty"`
+	LateAfterMinutes int        `json:"late_after_minutes"`
+	ValidFrom        time.Time  `json:"valid_from"`
+	ValidUntil       *time.Time `json:"valid_until,omitempty"`
+}
+
+// query parameters for GET /timetable/sessions (YYYY-MM-DD, both inclusive)
+type ClassSessionQuery struct {
+	From             time.Time `form:"from" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	To               time.Time `form:"to" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	SectionID        uint      `form:"section_id"`
+	TeacherID        uint      `form:"teacher_id"`
+	IncludeCancelled bool      `form:"include_cancelled"`
+}
+
+type ClassSessionResponse struct {
+	ID               uint      `json:"id"`
+	SectionID        uint      `json:"section_id"`
+	CourseCode       string    `json:"course_code,omitempty"`
+	SectionName      string    `json:"section_name,omitempty"`
+	SlotID           *uint     `json:"slot_id,omitempty"`
+	Date             time.Time `json:"date"`
+	StartTime        string    `json:"start_time"`
+	EndTime          string    `json:"end_time"`
+	Room             string    `json:"room,omitempty"`
+	TeacherID        *uint     `json:"teacher_id,omitempty"`
+	LateAfterMinutes int       `json:"late_after_minutes"`
+	Cancelled        bool      `json:"cancelled"`
+}
+
+// PUT /timetable/sessions/:id
+type UpdateClassSessionRequest struct {
+	Cancelled *bool `json:"cancelled" binding:"required"`
+}
+
+// POST /timetable/sessions/generate
+type GenerateSessionsRequest struct {
+	From time.Time `json:"from" binding:"required"`
+	To   time.Time `json:"to" binding:"required"`
+}
+
+type GenerateSessionsResponse struct {
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Created int       `json:"created"` // sessions that did not exist yet
+}
@@ -0,0 +1,66 @@
+package viewmodels
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// POST /webhooks and PUT /webhooks/:id
+type WebhookRequest struct {
+	URL string `json:"url" binding:"required,url,max=500" example:"https://lms.example.com/hooks/attendance"`
+	// used to sign the deliveries; generated on create when left out, kept on update when left out
+	Secret string   `json:"secret" binding:"omitempty,min=16,max=100"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=student.created student.updated student.deleted student.restored student.purged attendance.marked attendance.updated attendance.deleted" example:"student.created,attendance.marked"`
+	Active *bool    `json:"active"` // defaults to true on create, unchanged on update
+}
+
+type WebhookResponse struct {
+	ID     uint     `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Active bool     `json:"active"`
+	// only returned when the subscription is created
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// query parameters for GET /webhooks/:id/deliveries
+type WebhookDeliveryQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending succeeded failed"`
+	Event  string `form:"event"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// delivery log entry
+type WebhookDeliveryResponse struct {
+	ID             uint            `json:"id"`
+	SubscriptionID uint            `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	Event          string          `json:"event" example:"attendance.marked"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status" example:"pending"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"` // while pending
+	ResponseStatus int             `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// paged response for GET /webhooks/:id/deliveries
+type WebhookDeliveryListResponse struct {
+	Data  []WebhookDeliveryResponse `json:"data"`
+	Total int64                     `json:"total"`
+	Page  int                       `json:"page"`
+	Limit int                       `json:"limit"`
+}
+
+// outcome of one run of the webhook delivery job
+type WebhookRunResponse struct {
+	Attempted int `json:"attempted"`
+	Succeeded int `json:"succeeded"`
+	Retrying  int `json:"retrying"` // failed, another attempt is scheduled
+	Failed    int `json:"failed"`   // failed for the last time
+}
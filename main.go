@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -9,6 +12,8 @@ import (
 	"hrms_backend/internal/config"
 	"hrms_backend/internal/controllers"
 	"hrms_backend/internal/cronJob"
+	"hrms_backend/internal/middleware"
+	"hrms_backend/internal/notify"
 	"hrms_backend/internal/repository"
 	"hrms_backend/internal/services"
 
@@ -31,6 +36,11 @@ import (
 
 // @host      localhost:8080
 // @BasePath  /
+
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 Access token from POST /auth/login, sent as "Bearer <token>".
 func main() {
 	// load env
 	err := godotenv.Load()
@@ -43,6 +53,7 @@ func main() {
 
 	// Initialize the Router
 	r := gin.Default()
+	r.Use(middleware.RequestID(), middleware.ErrorHandler())
 
 	// Swagger endpoint
 	docs.SwaggerInfo.BasePath = "/"
@@ -52,33 +63,126 @@ func main() {
 	// internal/repository/student_repository.go
 	studentRepo := repository.NewStudentRepository(config.DB)
 	attendanceRepo := repository.NewAttendanceRepository(config.DB)
+	reportRepo := repository.NewReportRepository(config.DB)
+	userRepo := repository.NewUserRepository(config.DB)
+	auditRepo := repository.NewAuditRepository(config.DB)
+	departmentRepo := repository.NewDepartmentRepository(config.DB)
+	courseRepo := repository.NewCourseRepository(config.DB)
+	sectionRepo := repository.NewSectionRepository(config.DB)
+	timetableRepo := repository.NewTimetableRepository(config.DB)
+	calendarRepo := repository.NewCalendarRepository(config.DB)
+	leaveRepo := repository.NewLeaveRepository(config.DB)
+	alertRepo := repository.NewAlertRepository(config.DB)
+	webhookRepo := repository.NewWebhookRepository(config.DB)
+	outboxRepo := repository.NewOutboxRepository(config.DB)
+	txManager := repository.NewTxManager(config.DB)
 
 	// Service (Talks to Repository)
 	// internal/services/student_service.go
-	studentService := services.NewStudentService(studentRepo)
-	attendanceService := services.NewAttendanceService(attendanceRepo, studentRepo)
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if err := services.ValidateSecret(jwtSecret); err != nil {
+		log.Fatal("Invalid JWT_SECRET: ", err)
+	}
+	authService := services.NewAuthService(userRepo, studentRepo, []byte(jwtSecret))
+	// Bootstrap admin account, created on first start
+	if email, password := os.Getenv("ADMIN_EMAIL"), os.Getenv("ADMIN_PASSWORD"); email != "" && password != "" {
+		if err := authService.EnsureAdmin(email, password); err != nil {
+			log.Fatal("Failed to create admin user:", err)
+		}
+	}
+	// Days of the week without classes, e.g. SCHOOL_WEEKEND=friday,saturday (default saturday,sunday)
+	weekend, err := services.ParseWeekend(os.Getenv("SCHOOL_WEEKEND"))
+	if err != nil {
+		log.Fatal("Invalid SCHOOL_WEEKEND: ", err)
+	}
+	calendarService := services.NewCalendarService(calendarRepo, weekend)
+	// Outbound webhooks: the outbox dispatcher queues student and attendance changes for the subscriptions that asked for them
+	webhookService := services.NewWebhookService(webhookRepo, nil)
+	outboxDispatcher := services.NewOutboxDispatcher(outboxRepo, departmentRepo, webhookService)
+	studentService := services.NewStudentService(studentRepo, departmentRepo, txManager)
+	attendanceService := services.NewAttendanceService(attendanceRepo, studentRepo, calendarService, txManager)
+	reportService := services.NewReportService(reportRepo, attendanceRepo, calendarService)
+	auditService := services.NewAuditService(auditRepo)
+	departmentService := services.NewDepartmentService(departmentRepo, attendanceRepo, calendarService)
+	courseService := services.NewCourseService(courseRepo, sectionRepo, studentRepo)
+	timetableService := services.NewTimetableService(timetableRepo, sectionRepo, userRepo, calendarService)
+	leaveService := services.NewLeaveService(leaveRepo, calendarService, txManager)
+	// Absence alerts: thresholds from ALERT_* (see .env), sent by email and/or webhook
+	thresholds, err := services.ParseAlertThresholds(os.Getenv("ALERT_WINDOW_DAYS"), os.Getenv("ALERT_MIN_PERCENTAGE"),
+		os.Getenv("ALERT_MIN_RECORDS"), os.Getenv("ALERT_CONSECUTIVE_ABSENCES"))
+	if err != nil {
+		log.Fatal("Invalid alert threshold: ", err)
+	}
+	notifier, err := notify.FromEnv()
+	if err != nil {
+		log.Fatal("Invalid alert notifier: ", err)
+	}
+	alertService := services.NewAlertService(alertRepo, attendanceRepo, calendarService, notifier, thresholds)
 	// Controller (Talks to Service)
 	// internal/controllers/student_controller.go
 	studentController := controllers.NewStudentController(studentService)
 	attendanceController := controllers.NewAttendanceController(attendanceService)
+	reportController := controllers.NewReportController(reportService)
+	authController := controllers.NewAuthController(authService)
+	meController := controllers.NewMeController(studentService, attendanceService, leaveService)
+	auditController := controllers.NewAuditController(auditService)
+	departmentController := controllers.NewDepartmentController(departmentService)
+	courseController := controllers.NewCourseController(courseService)
+	sectionController := controllers.NewSectionController(courseService)
+	timetableController := controllers.NewTimetableController(timetableService)
+	calendarController := controllers.NewCalendarController(calendarService)
+	leaveController := controllers.NewLeaveController(leaveService)
+	webhookController := controllers.NewWebhookController(webhookService)
+
+	// Public: login and token refresh
+	authController.RegisterRoutes(r.Group("/auth"))
+
+	// Everything else requires a bearer token; controllers check roles per route
+	protected := r.Group("", middleware.Authenticate(authService))
 	// Create : http://localhost:8080/students
-	studentGroup := r.Group("/students")
-	attendanceGroup := r.Group("/attendance")
+	studentGroup := protected.Group("/students")
+	attendanceGroup := protected.Group("/attendance")
+	reportGroup := protected.Group("/reports")
 	// Pass the group to the controller so it can define endpoints
 	studentController.RegisterRoutes(studentGroup)
 	attendanceController.RegisterRoutes(attendanceGroup)
+	reportController.RegisterRoutes(reportGroup)
+	authController.RegisterUserRoutes(protected.Group("/users"))
+	meController.RegisterRoutes(protected.Group("/me"))
+	auditController.RegisterRoutes(protected.Group("/audit"))
+	departmentController.RegisterRoutes(protected.Group("/departments"))
+	courseController.RegisterRoutes(protected.Group("/courses"))
+	sectionController.RegisterRoutes(protected.Group("/sections"))
+	timetableController.RegisterRoutes(protected.Group("/timetable"))
+	calendarController.RegisterRoutes(protected.Group("/calendar"))
+	leaveController.RegisterRoutes(protected.Group("/leave-requests"))
+	webhookController.RegisterRoutes(protected.Group("/webhooks"))
 
+	// Scheduled jobs: each schedule can be overridden with JOB_<NAME>_SCHEDULE
+	// and each job switched off with JOB_<NAME>_ENABLED=false (see .env)
 	c := cron.New()
-	attendanceCron := cronJob.NewAttendanceCron(attendanceService)
-
-	// Schedule: Run every minute for testing purposes ("@every 1m")
-	// For actual weekly: "@weekly" or "0 0 * * 0" (Sunday midnight)
-	_, err = c.AddFunc("@every 1m", attendanceCron.RunWeeklyReport)
-	if err != nil {
-		log.Fatal("Failed to add cron job:", err)
+	jobs := cronJob.NewRegistry(c)
+	attendanceCron := cronJob.NewAttendanceCron(reportService)
+	alertCron := cronJob.NewAlertCron(alertService)
+	timetableCron := cronJob.NewTimetableCron(timetableService)
+	webhookCron := cronJob.NewWebhookCron(webhookService)
+	for _, job := range []cronJob.Job{
+		cronJob.JobFromEnv("weekly_report", "@weekly", true, attendanceCron.RunWeeklyReport),
+		cronJob.JobFromEnv("monthly_report", "@monthly", true, attendanceCron.RunMonthlyReport),
+		cronJob.JobFromEnv("absence_alerts", "@daily", true, alertCron.RunAbsenceAlerts),
+		cronJob.JobFromEnv("class_sessions", "@daily", true, timetableCron.RunGenerateSessions),
+		cronJob.JobFromEnv("webhook_deliveries", "@every 10s", true, webhookCron.RunDeliveries),
+	} {
+		if err := jobs.Register(job); err != nil {
+			log.Fatal("Failed to add cron job:", err)
+		}
 	}
+	jobController := controllers.NewJobController(jobs)
+	jobController.RegisterRoutes(protected.Group("/jobs"))
+
 	c.Start()
 	log.Println("⏳ Cron Scheduler started...")
+	go outboxDispatcher.Run(context.Background(), time.Second)
 
 	// Start the server
 	log.Println("Server starting on port 8080...")